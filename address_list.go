@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ProviderList is an ordered list of distinct provider addresses.
+//
+// Addresses are kept in the order they were first added, and adding an
+// address that is already present has no effect. The zero value is an
+// empty list ready to use.
+//
+// A ProviderList marshals to JSON as an array of strings in the form
+// returned by Provider.MarshalText, in the list's order, so that an
+// address with an unknown namespace can be parsed back.
+type ProviderList struct {
+	items []Provider
+	index map[Provider]struct{}
+}
+
+// NewProviderList returns a ProviderList containing the given addresses,
+// with any duplicates removed.
+func NewProviderList(providers ...Provider) *ProviderList {
+	ret := &ProviderList{}
+	for _, p := range providers {
+		ret.Add(p)
+	}
+	return ret
+}
+
+// Add appends the given address to the list and returns true, or returns
+// false if it is already present.
+//
+// Add panics if given the zero value of Provider.
+func (l *ProviderList) Add(p Provider) bool {
+	if p.IsZero() {
+		panic("called Add with zero-value addrs.Provider")
+	}
+	if _, exists := l.index[p]; exists {
+		return false
+	}
+	if l.index == nil {
+		l.index = make(map[Provider]struct{})
+	}
+	l.index[p] = struct{}{}
+	l.items = append(l.items, p)
+	return true
+}
+
+// Has returns true if the given address is in the list.
+func (l *ProviderList) Has(p Provider) bool {
+	_, exists := l.index[p]
+	return exists
+}
+
+// Len returns the number of addresses in the list.
+func (l *ProviderList) Len() int {
+	return len(l.items)
+}
+
+// Providers returns the addresses in the list, in order. The result is a
+// new slice on each call, so the caller may modify it.
+func (l *ProviderList) Providers() []Provider {
+	if len(l.items) == 0 {
+		return nil
+	}
+	ret := make([]Provider, len(l.items))
+	copy(ret, l.items)
+	return ret
+}
+
+func (l ProviderList) MarshalJSON() ([]byte, error) {
+	raw := make([]string, len(l.items))
+	for i, p := range l.items {
+		text, err := p.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = string(text)
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON replaces the content of the list with the addresses in the
+// given JSON array of provider source strings, removing any duplicates.
+func (l *ProviderList) UnmarshalJSON(b []byte) error {
+	var raw []string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var ret ProviderList
+	for _, s := range raw {
+		p, err := ParseProviderSource(s)
+		if err != nil {
+			return fmt.Errorf("invalid provider address %q: %s", s, err)
+		}
+		ret.Add(p)
+	}
+	*l = ret
+	return nil
+}
+
+// ModulePackageList is an ordered list of distinct module package
+// addresses, with the same behavior as ProviderList.
+//
+// Addresses on a host whose DefaultHostProfile has CaseInsensitiveModules
+// set, such as the public registry, are distinct only if they differ other
+// than in case, as for LookupModule. The list keeps the first of several
+// such addresses that it is given.
+//
+// A ModulePackageList marshals to JSON as an array of strings in the form
+// returned by ModulePackage.String, in the list's order.
+type ModulePackageList struct {
+	items []ModulePackage
+	index map[ModulePackage]struct{}
+}
+
+// NewModulePackageList returns a ModulePackageList containing the given
+// addresses, with any duplicates removed.
+func NewModulePackageList(pkgs ...ModulePackage) *ModulePackageList {
+	ret := &ModulePackageList{}
+	for _, pkg := range pkgs {
+		ret.Add(pkg)
+	}
+	return ret
+}
+
+// Add appends the given address to the list and returns true, or returns
+// false if it is already present.
+//
+// Add panics if given the zero value of ModulePackage.
+func (l *ModulePackageList) Add(pkg ModulePackage) bool {
+	if pkg.IsZero() {
+		panic("called Add with zero-value ModulePackage")
+	}
+	key := modulePackageListKey(pkg)
+	if _, exists := l.index[key]; exists {
+		return false
+	}
+	if l.index == nil {
+		l.index = make(map[ModulePackage]struct{})
+	}
+	l.index[key] = struct{}{}
+	l.items = append(l.items, pkg)
+	return true
+}
+
+// Has returns true if the given address is in the list.
+func (l *ModulePackageList) Has(pkg ModulePackage) bool {
+	_, exists := l.index[modulePackageListKey(pkg)]
+	return exists
+}
+
+// modulePackageListKey returns the key under which a ModulePackageList
+// indexes the given address, with its namespace, name, and target system
+// folded to lowercase if its host treats them case-insensitively.
+func modulePackageListKey(pkg ModulePackage) ModulePackage {
+	if DefaultHostProfile(pkg.Host).CaseInsensitiveModules {
+		pkg.Namespace = strings.ToLower(pkg.Namespace)
+		pkg.Name = strings.ToLower(pkg.Name)
+		pkg.TargetSystem = strings.ToLower(pkg.TargetSystem)
+	}
+	return pkg
+}
+
+// Len returns the number of addresses in the list.
+func (l *ModulePackageList) Len() int {
+	return len(l.items)
+}
+
+// Packages returns the addresses in the list, in order. The result is a
+// new slice on each call, so the caller may modify it.
+func (l *ModulePackageList) Packages() []ModulePackage {
+	if len(l.items) == 0 {
+		return nil
+	}
+	ret := make([]ModulePackage, len(l.items))
+	copy(ret, l.items)
+	return ret
+}
+
+func (l ModulePackageList) MarshalJSON() ([]byte, error) {
+	raw := make([]string, len(l.items))
+	for i, pkg := range l.items {
+		raw[i] = pkg.String()
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON replaces the content of the list with the addresses in the
+// given JSON array of module package source strings, removing any
+// duplicates. The source strings must not include a subdirectory.
+func (l *ModulePackageList) UnmarshalJSON(b []byte) error {
+	var raw []string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var ret ModulePackageList
+	for _, s := range raw {
+		mod, err := ParseModuleSource(s)
+		if err != nil {
+			return fmt.Errorf("invalid module package address %q: %s", s, err)
+		}
+		if mod.Subdir != "" {
+			return fmt.Errorf("invalid module package address %q: must not include a subdirectory", s)
+		}
+		ret.Add(mod.Package)
+	}
+	*l = ret
+	return nil
+}
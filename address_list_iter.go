@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfaddr
+
+import (
+	"iter"
+	"sort"
+)
+
+// All returns an iterator over the addresses in the list, in the order
+// defined by Provider.LessThan rather than the list's own order, for use
+// with range-over-func loops. Use Providers to visit the addresses in the
+// list's order.
+//
+// The iterator works on a snapshot of the list taken when iteration
+// begins, so the list may be modified while iterating.
+func (l *ProviderList) All() iter.Seq[Provider] {
+	return func(yield func(Provider) bool) {
+		providers := l.Providers()
+		SortProviders(providers)
+		for _, p := range providers {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the module packages in the list, ordered by
+// hostname, then namespace, then name, then target system, each compared
+// bytewise, for use with range-over-func loops. Use Packages to visit the
+// packages in the list's own order.
+//
+// The iterator works on a snapshot of the list taken when iteration
+// begins, so the list may be modified while iterating.
+func (l *ModulePackageList) All() iter.Seq[ModulePackage] {
+	return func(yield func(ModulePackage) bool) {
+		pkgs := l.Packages()
+		sort.Slice(pkgs, func(i, j int) bool {
+			a, b := pkgs[i], pkgs[j]
+			switch {
+			case a.Host != b.Host:
+				return a.Host < b.Host
+			case a.Namespace != b.Namespace:
+				return a.Namespace < b.Namespace
+			case a.Name != b.Name:
+				return a.Name < b.Name
+			default:
+				return a.TargetSystem < b.TargetSystem
+			}
+		})
+		for _, pkg := range pkgs {
+			if !yield(pkg) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the addresses in the set, in the order
+// defined by Provider.LessThan, for use with range-over-func loops.
+//
+// The iterator works on a snapshot of the set taken when iteration
+// begins, so the set may be modified while iterating.
+func (s ProviderSet) All() iter.Seq[Provider] {
+	return func(yield func(Provider) bool) {
+		for _, p := range s.Sorted() {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderListAll(t *testing.T) {
+	l := NewProviderList(
+		MustParseProviderSource("hashicorp/null"),
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("hashicorp/null"),
+		MustParseProviderSource("example.com/foo/bar"),
+	)
+
+	var got []Provider
+	for p := range l.All() {
+		got = append(got, p)
+	}
+	want := []Provider{
+		MustParseProviderSource("example.com/foo/bar"),
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("hashicorp/null"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// Stopping early must not cause a panic.
+	for range l.All() {
+		break
+	}
+}
+
+func TestModulePackageListAll(t *testing.T) {
+	l := NewModulePackageList(
+		MustParseModuleSource("hashicorp/vault/aws").Package,
+		MustParseModuleSource("hashicorp/consul/aws").Package,
+		MustParseModuleSource("example.com/hashicorp/vault/aws").Package,
+	)
+
+	var got []ModulePackage
+	for pkg := range l.All() {
+		got = append(got, pkg)
+	}
+	want := []ModulePackage{
+		MustParseModuleSource("example.com/hashicorp/vault/aws").Package,
+		MustParseModuleSource("hashicorp/consul/aws").Package,
+		MustParseModuleSource("hashicorp/vault/aws").Package,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	var empty ModulePackageList
+	for range empty.All() {
+		t.Error("empty list yielded an element")
+	}
+}
+
+func TestProviderSetAll(t *testing.T) {
+	s := NewProviderSet(
+		MustParseProviderSource("hashicorp/null"),
+		MustParseProviderSource("hashicorp/aws"),
+	)
+
+	var got []Provider
+	for p := range s.All() {
+		s.Remove(p)
+		got = append(got, p)
+	}
+	want := []Provider{
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("hashicorp/null"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
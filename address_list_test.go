@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderList(t *testing.T) {
+	aws := MustParseProviderSource("hashicorp/aws")
+	null := MustParseProviderSource("hashicorp/null")
+	example := MustParseProviderSource("example.com/test/example")
+
+	var list ProviderList
+	if !list.Add(null) {
+		t.Error("first Add returned false")
+	}
+	list.Add(aws)
+	if list.Add(MustParseProviderSource("registry.terraform.io/HashiCorp/null")) {
+		t.Error("duplicate Add returned true")
+	}
+	list.Add(example)
+
+	want := []Provider{null, aws, example}
+	if diff := cmp.Diff(want, list.Providers()); diff != "" {
+		t.Errorf("wrong providers\n%s", diff)
+	}
+	if got, want := list.Len(), 3; got != want {
+		t.Errorf("wrong length %d; want %d", got, want)
+	}
+	if !list.Has(aws) || list.Has(MustParseProviderSource("hashicorp/google")) {
+		t.Error("wrong result from Has")
+	}
+
+	got, err := json.Marshal(&list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantJSON := `["registry.terraform.io/hashicorp/null","registry.terraform.io/hashicorp/aws","example.com/test/example"]`
+	if string(got) != wantJSON {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, wantJSON)
+	}
+}
+
+func TestProviderListUnmarshalJSON(t *testing.T) {
+	list := NewProviderList(MustParseProviderSource("hashicorp/google"))
+	err := json.Unmarshal([]byte(`["hashicorp/null","aws","registry.terraform.io/hashicorp/null"]`), list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Provider{
+		MustParseProviderSource("hashicorp/null"),
+		MustParseProviderSource("aws"),
+	}
+	if diff := cmp.Diff(want, list.Providers()); diff != "" {
+		t.Errorf("wrong providers\n%s", diff)
+	}
+
+	got, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantJSON := `["registry.terraform.io/hashicorp/null","aws"]`
+	if string(got) != wantJSON {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, wantJSON)
+	}
+	var roundTrip ProviderList
+	if err := json.Unmarshal(got, &roundTrip); err != nil {
+		t.Fatalf("failed to unmarshal %s: %s", got, err)
+	}
+	if diff := cmp.Diff(want, roundTrip.Providers()); diff != "" {
+		t.Errorf("wrong providers after round trip\n%s", diff)
+	}
+
+	err = json.Unmarshal([]byte(`["hashicorp/not valid"]`), list)
+	if err == nil {
+		t.Fatal("unexpected success with invalid address")
+	}
+}
+
+func TestModulePackageList(t *testing.T) {
+	consul := MustParseModuleSource("hashicorp/consul/aws").Package
+	vpc := MustParseModuleSource("terraform-aws-modules/vpc/aws").Package
+
+	list := NewModulePackageList(vpc, consul, vpc)
+	want := []ModulePackage{vpc, consul}
+	if diff := cmp.Diff(want, list.Packages()); diff != "" {
+		t.Errorf("wrong packages\n%s", diff)
+	}
+
+	got, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantJSON := `["registry.terraform.io/terraform-aws-modules/vpc/aws","registry.terraform.io/hashicorp/consul/aws"]`
+	if string(got) != wantJSON {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, wantJSON)
+	}
+
+	var roundTrip ModulePackageList
+	if err := json.Unmarshal(got, &roundTrip); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, roundTrip.Packages()); diff != "" {
+		t.Errorf("wrong packages after round trip\n%s", diff)
+	}
+
+	err = json.Unmarshal([]byte(`["hashicorp/consul/aws//modules/consul-cluster"]`), &roundTrip)
+	if err == nil {
+		t.Fatal("unexpected success with subdirectory")
+	}
+	if got, want := err.Error(), `invalid module package address "hashicorp/consul/aws//modules/consul-cluster": must not include a subdirectory`; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAddressListsMarshalJSONByValue(t *testing.T) {
+	type lists struct {
+		Providers ProviderList      `json:"providers"`
+		Packages  ModulePackageList `json:"packages"`
+	}
+	v := lists{
+		Providers: *NewProviderList(MustParseProviderSource("hashicorp/aws")),
+		Packages:  *NewModulePackageList(MustParseModuleSource("hashicorp/consul/aws").Package),
+	}
+
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"providers":["registry.terraform.io/hashicorp/aws"],"packages":["registry.terraform.io/hashicorp/consul/aws"]}`
+	if string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestModulePackageListCaseInsensitive(t *testing.T) {
+	consul := MustParseModuleSource("hashicorp/consul/aws").Package
+	mixedCase := MustParseModuleSource("HashiCorp/Consul/aws").Package
+	private := MustParseModuleSource("example.com/hashicorp/consul/aws").Package
+	privateMixedCase := MustParseModuleSource("example.com/HashiCorp/Consul/aws").Package
+
+	list := NewModulePackageList(consul, mixedCase, private, privateMixedCase)
+	want := []ModulePackage{consul, private, privateMixedCase}
+	if diff := cmp.Diff(want, list.Packages()); diff != "" {
+		t.Errorf("wrong packages\n%s", diff)
+	}
+	if !list.Has(mixedCase) {
+		t.Errorf("list doesn't have %s", mixedCase)
+	}
+}
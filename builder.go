@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// ProviderBuilder constructs a Provider from its individual segments,
+// validating each segment as it is set.
+//
+// This is intended for programs that assemble addresses from separately
+// stored values, such as database fields, rather than from source strings.
+// Use NewProviderBuilder to create a ProviderBuilder.
+type ProviderBuilder struct {
+	addr Provider
+	err  error
+}
+
+// NewProviderBuilder returns a ProviderBuilder whose hostname defaults to
+// DefaultProviderRegistryHost.
+func NewProviderBuilder() *ProviderBuilder {
+	return &ProviderBuilder{
+		addr: Provider{Hostname: DefaultProviderRegistryHost},
+	}
+}
+
+// Hostname sets the registry hostname of the provider address being built.
+func (b *ProviderBuilder) Hostname(given string) *ProviderBuilder {
+	if b.err != nil {
+		return b
+	}
+	host, err := parseHostname(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "hostname", Value: given, Err: err}
+		return b
+	}
+	b.addr.Hostname = host
+	return b
+}
+
+// Namespace sets the namespace of the provider address being built.
+//
+// The special legacy and unknown namespaces are not accepted.
+func (b *ProviderBuilder) Namespace(given string) *ProviderBuilder {
+	if b.err != nil {
+		return b
+	}
+	namespace, err := ParseProviderNamespace(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "namespace", Value: given, Err: err}
+		return b
+	}
+	b.addr.Namespace = string(namespace)
+	return b
+}
+
+// Type sets the type of the provider address being built.
+func (b *ProviderBuilder) Type(given string) *ProviderBuilder {
+	if b.err != nil {
+		return b
+	}
+	typeName, err := ParseProviderType(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "type", Value: given, Err: err}
+		return b
+	}
+	b.addr.Type = string(typeName)
+	return b
+}
+
+// Build returns the provider address, or the first error encountered while
+// setting its segments. It also returns an error if the namespace or type
+// was not set.
+func (b *ProviderBuilder) Build() (Provider, error) {
+	switch {
+	case b.err != nil:
+		return Provider{}, b.err
+	case b.addr.Namespace == "":
+		return Provider{}, &SegmentError{Segment: "namespace", Err: fmt.Errorf("must be set")}
+	case b.addr.Type == "":
+		return Provider{}, &SegmentError{Segment: "type", Err: fmt.Errorf("must be set")}
+	case b.addr.Namespace == LegacyProviderNamespace && b.addr.Hostname != DefaultProviderRegistryHost:
+		// We can only get here when building from an existing legacy
+		// address, as in Provider.WithHostname.
+		return Provider{}, &SegmentError{Segment: "hostname", Value: b.addr.Hostname.ForDisplay(), Err: fmt.Errorf("the legacy provider namespace %q can be used only with hostname %s", LegacyProviderNamespace, DefaultProviderRegistryHost.ForDisplay())}
+	}
+	return b.addr, nil
+}
+
+// NewProviderChecked is like NewProvider, except that it returns an error
+// instead of panicking if any of the given segments is invalid, applying
+// the same rules as ParseProviderSource.
+//
+// The hostname must already be in the normalized form returned by
+// svchost.ForComparison. The namespace and type are normalized in the
+// same way as by NewProvider.
+func NewProviderChecked(hostname svchost.Hostname, namespace, typeName string) (Provider, error) {
+	if err := checkNormalizedHostname(hostname); err != nil {
+		return Provider{}, &SegmentError{Segment: "hostname", Value: string(hostname), Err: err}
+	}
+	b := &ProviderBuilder{addr: Provider{Hostname: hostname}}
+	return b.Namespace(namespace).Type(typeName).Build()
+}
+
+// ModulePackageBuilder constructs a ModulePackage from its individual
+// segments, validating each segment as it is set.
+//
+// This is intended for programs that assemble addresses from separately
+// stored values, such as database fields, rather than from source strings.
+// Use NewModulePackageBuilder to create a ModulePackageBuilder.
+type ModulePackageBuilder struct {
+	addr ModulePackage
+	err  error
+}
+
+// NewModulePackageBuilder returns a ModulePackageBuilder whose host defaults
+// to DefaultModuleRegistryHost.
+func NewModulePackageBuilder() *ModulePackageBuilder {
+	return &ModulePackageBuilder{
+		addr: ModulePackage{Host: DefaultModuleRegistryHost},
+	}
+}
+
+// Host sets the registry hostname of the module package being built.
+func (b *ModulePackageBuilder) Host(given string) *ModulePackageBuilder {
+	if b.err != nil {
+		return b
+	}
+	host, err := parseHostname(given)
+	switch {
+	case err != nil:
+		b.err = &SegmentError{Segment: "hostname", Value: given, Err: err}
+	case !strings.Contains(host.String(), "."):
+		b.err = &SegmentError{Segment: "hostname", Value: given, Err: fmt.Errorf("must contain at least one dot")}
+	case host == svchost.Hostname("github.com") || host == svchost.Hostname("bitbucket.org"):
+		b.err = &SegmentError{Segment: "hostname", Value: given, Err: fmt.Errorf("reserved for installing directly from version control repositories")}
+	default:
+		b.addr.Host = host
+	}
+	return b
+}
+
+// Namespace sets the namespace of the module package being built.
+func (b *ModulePackageBuilder) Namespace(given string) *ModulePackageBuilder {
+	if b.err != nil {
+		return b
+	}
+	namespace, err := parseModuleRegistryName(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "namespace", Value: given, Err: err}
+		return b
+	}
+	b.addr.Namespace = namespace
+	return b
+}
+
+// Name sets the module name of the module package being built.
+func (b *ModulePackageBuilder) Name(given string) *ModulePackageBuilder {
+	if b.err != nil {
+		return b
+	}
+	name, err := parseModuleRegistryName(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "module name", Value: given, Err: err}
+		return b
+	}
+	b.addr.Name = name
+	return b
+}
+
+// System sets the target system of the module package being built.
+func (b *ModulePackageBuilder) System(given string) *ModulePackageBuilder {
+	if b.err != nil {
+		return b
+	}
+	system, err := parseModuleRegistryTargetSystem(given)
+	if err != nil {
+		b.err = &SegmentError{Segment: "target system", Value: given, Err: err}
+		return b
+	}
+	b.addr.TargetSystem = system
+	return b
+}
+
+// Build returns the module package address, or the first error encountered
+// while setting its segments. It also returns an error if the namespace,
+// name, or target system was not set.
+func (b *ModulePackageBuilder) Build() (ModulePackage, error) {
+	switch {
+	case b.err != nil:
+		return ModulePackage{}, b.err
+	case b.addr.Namespace == "":
+		return ModulePackage{}, &SegmentError{Segment: "namespace", Err: fmt.Errorf("must be set")}
+	case b.addr.Name == "":
+		return ModulePackage{}, &SegmentError{Segment: "module name", Err: fmt.Errorf("must be set")}
+	case b.addr.TargetSystem == "":
+		return ModulePackage{}, &SegmentError{Segment: "target system", Err: fmt.Errorf("must be set")}
+	}
+	return b.addr, nil
+}
+
+// NewModulePackageChecked constructs a ModulePackage from its segments,
+// returning an error if any of them is invalid, applying the same rules
+// as ParseModuleSource.
+//
+// The hostname must already be in the normalized form returned by
+// svchost.ForComparison.
+func NewModulePackageChecked(host svchost.Hostname, namespace, name, system string) (ModulePackage, error) {
+	if err := checkNormalizedHostname(host); err != nil {
+		return ModulePackage{}, &SegmentError{Segment: "hostname", Value: string(host), Err: err}
+	}
+	return NewModulePackageBuilder().Host(host.ForDisplay()).Namespace(namespace).Name(name).System(system).Build()
+}
+
+// checkNormalizedHostname returns an error if the given hostname is not
+// valid or is not in the normalized form returned by svchost.ForComparison.
+func checkNormalizedHostname(host svchost.Hostname) error {
+	if host == "" {
+		return fmt.Errorf("must be set")
+	}
+	if !utf8.ValidString(string(host)) {
+		return fmt.Errorf("must be valid UTF-8")
+	}
+	normalized, err := parseHostname(host.ForDisplay())
+	if err != nil {
+		return err
+	}
+	if normalized != host {
+		return fmt.Errorf("must be given in normalized form %q", string(normalized))
+	}
+	return nil
+}
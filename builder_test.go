@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestProviderBuilder(t *testing.T) {
+	tests := map[string]struct {
+		builder *ProviderBuilder
+		want    Provider
+		wantErr string
+	}{
+		"default hostname": {
+			builder: NewProviderBuilder().Namespace("HashiCorp").Type("AWS"),
+			want:    NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws"),
+		},
+		"explicit hostname": {
+			builder: NewProviderBuilder().Hostname("Example.com").Namespace("foo").Type("bar"),
+			want:    NewProvider(svchost.Hostname("example.com"), "foo", "bar"),
+		},
+		"invalid hostname": {
+			builder: NewProviderBuilder().Hostname("badhost!").Namespace("foo").Type("bar"),
+			wantErr: `invalid hostname "badhost!": idna: disallowed rune U+0021`,
+		},
+		"legacy namespace": {
+			builder: NewProviderBuilder().Namespace("-").Type("bar"),
+			wantErr: `invalid namespace "-": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"invalid type": {
+			builder: NewProviderBuilder().Namespace("foo").Type("bad.type"),
+			wantErr: `invalid type "bad.type": dots are not allowed`,
+		},
+		"redundant type prefix": {
+			builder: NewProviderBuilder().Namespace("foo").Type("terraform-provider-bar"),
+			wantErr: `invalid type "terraform-provider-bar": must not have the redundant prefix "terraform-"`,
+		},
+		"first error wins": {
+			builder: NewProviderBuilder().Namespace("bad!").Type("bad.type"),
+			wantErr: `invalid namespace "bad!": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"missing type": {
+			builder: NewProviderBuilder().Namespace("foo"),
+			wantErr: `invalid type "": must be set`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.builder.Build()
+			if test.wantErr != "" {
+				var segErr *SegmentError
+				switch {
+				case err == nil:
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				case !errors.As(err, &segErr):
+					t.Fatalf("wrong error type %T; want *SegmentError", err)
+				case err.Error() != test.wantErr:
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModulePackageBuilder(t *testing.T) {
+	tests := map[string]struct {
+		builder *ModulePackageBuilder
+		want    ModulePackage
+		wantErr string
+	}{
+		"default host": {
+			builder: NewModulePackageBuilder().Namespace("HashiCorp").Name("consul").System("aws"),
+			want: ModulePackage{
+				Host:         DefaultModuleRegistryHost,
+				Namespace:    "HashiCorp",
+				Name:         "consul",
+				TargetSystem: "aws",
+			},
+		},
+		"explicit host": {
+			builder: NewModulePackageBuilder().Host("Example.com:1234").Namespace("foo").Name("bar").System("baz"),
+			want: ModulePackage{
+				Host:         svchost.Hostname("example.com:1234"),
+				Namespace:    "foo",
+				Name:         "bar",
+				TargetSystem: "baz",
+			},
+		},
+		"host without dots": {
+			builder: NewModulePackageBuilder().Host("localhost").Namespace("foo").Name("bar").System("baz"),
+			wantErr: `invalid hostname "localhost": must contain at least one dot`,
+		},
+		"reserved host": {
+			builder: NewModulePackageBuilder().Host("GitHub.com").Namespace("foo").Name("bar").System("baz"),
+			wantErr: `invalid hostname "GitHub.com": reserved for installing directly from version control repositories`,
+		},
+		"invalid name": {
+			builder: NewModulePackageBuilder().Namespace("foo").Name("-bar").System("baz"),
+			wantErr: `invalid module name "-bar": must be between one and 64 characters, including ASCII letters, digits, dashes, and underscores, where dashes and underscores may not be the prefix or suffix`,
+		},
+		"invalid target system": {
+			builder: NewModulePackageBuilder().Namespace("foo").Name("bar").System("no-no"),
+			wantErr: `invalid target system "no-no": must be between one and 64 ASCII letters or digits`,
+		},
+		"missing namespace": {
+			builder: NewModulePackageBuilder().Name("bar").System("baz"),
+			wantErr: `invalid namespace "": must be set`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.builder.Build()
+			if test.wantErr != "" {
+				var segErr *SegmentError
+				switch {
+				case err == nil:
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				case !errors.As(err, &segErr):
+					t.Fatalf("wrong error type %T; want *SegmentError", err)
+				case err.Error() != test.wantErr:
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewProviderChecked(t *testing.T) {
+	tests := map[string]struct {
+		hostname  svchost.Hostname
+		namespace string
+		typeName  string
+		want      Provider
+		wantErr   string
+	}{
+		"valid": {
+			hostname:  DefaultProviderRegistryHost,
+			namespace: "HashiCorp",
+			typeName:  "AWS",
+			want:      MustParseProviderSource("hashicorp/aws"),
+		},
+		"punycode hostname": {
+			hostname:  "xn--80akhbyknj4f.com",
+			namespace: "foo",
+			typeName:  "bar",
+			want:      MustParseProviderSource("испытание.com/foo/bar"),
+		},
+		"non-normalized hostname": {
+			hostname:  "Example.com",
+			namespace: "foo",
+			typeName:  "bar",
+			wantErr:   `invalid hostname "Example.com": must be given in normalized form "example.com"`,
+		},
+		"empty hostname": {
+			namespace: "foo",
+			typeName:  "bar",
+			wantErr:   `invalid hostname "": must be set`,
+		},
+		"legacy namespace": {
+			hostname:  DefaultProviderRegistryHost,
+			namespace: "-",
+			typeName:  "aws",
+			wantErr:   `invalid namespace "-": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"redundant prefix": {
+			hostname:  DefaultProviderRegistryHost,
+			namespace: "hashicorp",
+			typeName:  "terraform-provider-aws",
+			wantErr:   `invalid type "terraform-provider-aws": must not have the redundant prefix "terraform-"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewProviderChecked(test.hostname, test.namespace, test.typeName)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewModulePackageChecked(t *testing.T) {
+	got, err := NewModulePackageChecked("example.com", "hashicorp", "consul", "aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := MustParseModuleSource("example.com/hashicorp/consul/aws").Package; got != want {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	_, err = NewModulePackageChecked("github.com", "hashicorp", "consul", "aws")
+	if want := `invalid hostname "github.com": reserved for installing directly from version control repositories`; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+	_, err = NewModulePackageChecked("example.com", "hashicorp", "consul", "AWS")
+	if want := `invalid target system "AWS": must be between one and 64 ASCII letters or digits`; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
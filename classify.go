@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// Summary is the result of classifying a set of module source strings with
+// Classify.
+//
+// Each of the maps groups the parsed addresses by one of their properties,
+// so the number of sources in each group is the length of its slice. All
+// of the slices are in the same order as the sources given to Classify,
+// and duplicate sources are counted each time they appear.
+type Summary struct {
+	// Registry is all of the sources that are valid module registry
+	// addresses.
+	Registry []Module
+
+	// ByHost groups the module registry addresses by registry hostname.
+	ByHost map[svchost.Hostname][]Module
+
+	// ByNamespace groups the module registry addresses by registry
+	// namespace, as returned by ModulePackage.RegistryNamespace.
+	ByNamespace map[RegistryNamespace][]Module
+
+	// Unparsed is all of the sources that are not valid module registry
+	// addresses. This includes local paths and direct remote sources such
+	// as Git repositories, which this package doesn't parse.
+	Unparsed []UnparsedSource
+}
+
+// UnparsedSource is a source string that Classify could not parse as a
+// module registry address.
+type UnparsedSource struct {
+	Source string
+	Err    error
+}
+
+// Total returns the total number of sources that were classified.
+func (s Summary) Total() int {
+	return len(s.Registry) + len(s.Unparsed)
+}
+
+// Classify parses each of the given module source strings and summarizes
+// them by registry hostname and namespace, such as when auditing which
+// modules an organization depends on.
+//
+// Classify never fails: sources that are not valid module registry
+// addresses are reported in Summary.Unparsed along with the parse error.
+func Classify(sources []string) Summary {
+	return Parser{}.Classify(sources)
+}
+
+// Classify is like the package-level function Classify, but parses the
+// given sources using the receiver's settings, and so sources rejected by
+// the receiver's host policy or limits are reported as unparsed.
+func (p Parser) Classify(sources []string) Summary {
+	ret := Summary{
+		ByHost:      make(map[svchost.Hostname][]Module),
+		ByNamespace: make(map[RegistryNamespace][]Module),
+	}
+	for _, src := range sources {
+		mod, err := p.ParseModuleSource(src)
+		if err != nil {
+			ret.Unparsed = append(ret.Unparsed, UnparsedSource{Source: src, Err: err})
+			continue
+		}
+		ret.Registry = append(ret.Registry, mod)
+		ret.ByHost[mod.Package.Host] = append(ret.ByHost[mod.Package.Host], mod)
+		namespace := mod.Package.RegistryNamespace()
+		ret.ByNamespace[namespace] = append(ret.ByNamespace[namespace], mod)
+	}
+	return ret
+}
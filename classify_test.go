@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestClassify(t *testing.T) {
+	consul := MustParseModuleSource("hashicorp/consul/aws")
+	consulCluster := MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster")
+	vpc := MustParseModuleSource("terraform-aws-modules/vpc/aws")
+	private := MustParseModuleSource("example.com/hashicorp/consul/aws")
+
+	got := Classify([]string{
+		"hashicorp/consul/aws",
+		"./modules/network",
+		"hashicorp/consul/aws//modules/consul-cluster",
+		"terraform-aws-modules/vpc/aws",
+		"git::https://example.com/network.git",
+		"example.com/hashicorp/consul/aws",
+		"hashicorp/consul/aws",
+	})
+
+	wantRegistry := []Module{consul, consulCluster, vpc, private, consul}
+	if diff := cmp.Diff(wantRegistry, got.Registry); diff != "" {
+		t.Errorf("wrong registry addresses\n%s", diff)
+	}
+	wantByHost := map[svchost.Hostname][]Module{
+		DefaultModuleRegistryHost:       {consul, consulCluster, vpc, consul},
+		svchost.Hostname("example.com"): {private},
+	}
+	if diff := cmp.Diff(wantByHost, got.ByHost); diff != "" {
+		t.Errorf("wrong addresses by host\n%s", diff)
+	}
+	wantByNamespace := map[RegistryNamespace][]Module{
+		MustParseRegistryNamespace("hashicorp"):             {consul, consulCluster, consul},
+		MustParseRegistryNamespace("terraform-aws-modules"): {vpc},
+		MustParseRegistryNamespace("example.com/hashicorp"): {private},
+	}
+	if diff := cmp.Diff(wantByNamespace, got.ByNamespace); diff != "" {
+		t.Errorf("wrong addresses by namespace\n%s", diff)
+	}
+
+	var unparsed []string
+	for _, u := range got.Unparsed {
+		if u.Err == nil {
+			t.Errorf("no error for unparsed source %q", u.Source)
+		}
+		unparsed = append(unparsed, u.Source)
+	}
+	wantUnparsed := []string{"./modules/network", "git::https://example.com/network.git"}
+	if diff := cmp.Diff(wantUnparsed, unparsed); diff != "" {
+		t.Errorf("wrong unparsed sources\n%s", diff)
+	}
+
+	if got, want := got.Total(), 7; got != want {
+		t.Errorf("wrong total %d; want %d", got, want)
+	}
+}
+
+func TestParserClassify(t *testing.T) {
+	parser := Parser{DeniedHosts: []svchost.Hostname{"example.com"}}
+	got := parser.Classify([]string{"example.com/hashicorp/consul/aws"})
+	if len(got.Registry) != 0 || len(got.Unparsed) != 1 {
+		t.Fatalf("denied host was not reported as unparsed: %#v", got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CompleteSource suggests completions of the given partial source string,
+// such as for tab completion in a command line tool or editor, using the
+// given corpus of known source strings.
+//
+// The kind selects whether the known source strings and the prefix are
+// provider addresses, for ProviderKind, or module registry addresses, for
+// ModulePackageKind or ModuleRegistryKind. Known source strings that are
+// not valid addresses of that kind are ignored.
+//
+// Each suggestion completes only the segment being typed, ending with a
+// slash if further segments follow, so that the caller can offer one
+// segment at a time. For example, with the prefix "hashi" and a corpus
+// including "hashicorp/aws", the suggestion is "hashicorp/". Suggestions
+// use the normalized form of each known address, both with and without
+// the default hostname, and the prefix is matched case-insensitively.
+//
+// The result is sorted and has no duplicates. It is nil if there are no
+// suggestions or if kind is not one of the kinds described above.
+func CompleteSource(prefix string, kind AddressKind, known []string) []string {
+	seen := make(map[string]struct{})
+	for _, src := range known {
+		for _, candidate := range completionCandidates(src, kind) {
+			n, ok := foldPrefixLen(candidate, prefix)
+			if !ok {
+				continue
+			}
+			suggestion := candidate
+			if i := strings.Index(candidate[n:], "/"); i != -1 {
+				suggestion = candidate[:n+i+1]
+			}
+			seen[suggestion] = struct{}{}
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(seen))
+	for suggestion := range seen {
+		ret = append(ret, suggestion)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// foldPrefixLen reports whether s starts with prefix under Unicode case
+// folding and, if so, the length in bytes of the matching part of s, which
+// may differ from the length of prefix.
+func foldPrefixLen(s, prefix string) (int, bool) {
+	n := 0
+	for _, pr := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRuneInString(s[n:])
+		if !strings.EqualFold(string(sr), string(pr)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// completionCandidates returns the normalized source strings that
+// CompleteSource matches against for the given known source string, or
+// nil if it isn't valid for the given kind.
+func completionCandidates(src string, kind AddressKind) []string {
+	switch kind {
+	case ProviderKind:
+		p, err := ParseProviderSource(src)
+		if err != nil || !p.HasKnownNamespace() || p.IsLegacy() {
+			return nil
+		}
+		return []string{p.ForDisplay(), p.String()}
+	case ModulePackageKind, ModuleRegistryKind:
+		mod, err := ParseModuleSource(src)
+		if err != nil {
+			return nil
+		}
+		if kind == ModulePackageKind {
+			mod.Subdir = ""
+		}
+		return []string{mod.ForDisplay(), mod.String()}
+	default:
+		return nil
+	}
+}
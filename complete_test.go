@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompleteSource(t *testing.T) {
+	providers := []string{
+		"hashicorp/aws",
+		"HashiCorp/google",
+		"integrations/github",
+		"example.com/acme/internal",
+		"aws",
+		"not valid",
+	}
+	modules := []string{
+		"hashicorp/consul/aws",
+		"hashicorp/consul/aws//modules/consul-cluster",
+		"hashicorp/vault/aws",
+		"terraform-aws-modules/vpc/aws",
+		"./local",
+	}
+
+	tests := map[string]struct {
+		prefix string
+		kind   AddressKind
+		known  []string
+		want   []string
+	}{
+		"provider namespace": {
+			prefix: "hashi",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"hashicorp/"},
+		},
+		"provider type": {
+			prefix: "hashicorp/",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"hashicorp/aws", "hashicorp/google"},
+		},
+		"provider case-insensitive": {
+			prefix: "HashiCorp/G",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"hashicorp/google"},
+		},
+		"provider hostname": {
+			prefix: "re",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"registry.terraform.io/"},
+		},
+		"provider empty prefix": {
+			prefix: "",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"example.com/", "hashicorp/", "integrations/", "registry.terraform.io/"},
+		},
+		"provider with hostname": {
+			prefix: "example.com/acme/",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   []string{"example.com/acme/internal"},
+		},
+		"no match": {
+			prefix: "nothing",
+			kind:   ProviderKind,
+			known:  providers,
+			want:   nil,
+		},
+		"module name": {
+			prefix: "hashicorp/",
+			kind:   ModuleRegistryKind,
+			known:  modules,
+			want:   []string{"hashicorp/consul/", "hashicorp/vault/"},
+		},
+		"module subdir": {
+			prefix: "hashicorp/consul/aws/",
+			kind:   ModuleRegistryKind,
+			known:  modules,
+			want:   []string{"hashicorp/consul/aws//"},
+		},
+		"module package excludes subdir": {
+			prefix: "hashicorp/consul/",
+			kind:   ModulePackageKind,
+			known:  modules,
+			want:   []string{"hashicorp/consul/aws"},
+		},
+		"module subdir with case folding that changes length, complete": {
+			prefix: "hashicorp/consul/aws//ⱥ",
+			kind:   ModuleRegistryKind,
+			known:  []string{"hashicorp/consul/aws//Ⱥ"},
+			want:   []string{"hashicorp/consul/aws//Ⱥ"},
+		},
+		"module subdir with case folding that changes length": {
+			prefix: "hashicorp/consul/aws//ⱥ",
+			kind:   ModuleRegistryKind,
+			known:  []string{"hashicorp/consul/aws//Ⱥ/x"},
+			want:   []string{"hashicorp/consul/aws//Ⱥ/"},
+		},
+		"unsupported kind": {
+			prefix: "",
+			kind:   UnknownAddressKind,
+			known:  providers,
+			want:   nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CompleteSource(test.prefix, test.kind, test.known)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// Describe returns a sentence fragment explaining each part of the address
+// in words, such as:
+//
+//	provider "aws" in namespace "hashicorp" on the public registry, built-in: no, legacy: no
+//
+// This is intended for commands that inspect addresses and for error
+// messages that teach users about the structure of addresses. The wording
+// may change in future versions, so callers should not try to parse it.
+func (pt Provider) Describe() string {
+	if pt.IsZero() {
+		return "no provider address"
+	}
+
+	var namespace string
+	switch {
+	case !pt.HasKnownNamespace():
+		namespace = "an unknown namespace"
+	case pt.IsLegacy():
+		namespace = fmt.Sprintf("the legacy namespace %q", pt.Namespace)
+	default:
+		namespace = fmt.Sprintf("namespace %q", pt.Namespace)
+	}
+	return fmt.Sprintf(
+		"provider %q in %s on %s, built-in: %s, legacy: %s",
+		pt.Type, namespace, describeHost(pt.Hostname),
+		describeBool(pt.IsBuiltIn()), describeBool(pt.IsLegacy()),
+	)
+}
+
+// Describe returns a sentence fragment explaining each part of the module
+// package address in words, such as:
+//
+//	module "consul" for target system "aws" in namespace "hashicorp" on the public registry
+//
+// The wording may change in future versions, so callers should not try to
+// parse it.
+func (s ModulePackage) Describe() string {
+	if s.IsZero() {
+		return "no module package address"
+	}
+	return fmt.Sprintf(
+		"module %q for target system %q in namespace %q on %s",
+		s.Name, s.TargetSystem, s.Namespace, describeHost(s.Host),
+	)
+}
+
+// Describe returns a sentence fragment explaining each part of the module
+// address in words, as for ModulePackage.Describe, followed by the
+// subdirectory if there is one.
+func (s Module) Describe() string {
+	if s.IsZero() {
+		return "no module address"
+	}
+	if s.Subdir == "" {
+		return s.Package.Describe()
+	}
+	return fmt.Sprintf("%s, subdirectory %q", s.Package.Describe(), s.Subdir)
+}
+
+func describeHost(host svchost.Hostname) string {
+	switch host {
+	case DefaultProviderRegistryHost:
+		return "the public registry"
+	case BuiltInProviderHost:
+		return fmt.Sprintf("the built-in provider host %q", host.ForDisplay())
+	default:
+		return fmt.Sprintf("registry %q", host.ForDisplay())
+	}
+}
+
+func describeBool(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
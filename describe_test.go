@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := map[string]struct {
+		addr interface{ Describe() string }
+		want string
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws"),
+			`provider "aws" in namespace "hashicorp" on the public registry, built-in: no, legacy: no`,
+		},
+		"legacy provider": {
+			MustParseProviderSource("-/aws"),
+			`provider "aws" in the legacy namespace "-" on the public registry, built-in: no, legacy: yes`,
+		},
+		"provider with unknown namespace": {
+			MustParseProviderSource("aws"),
+			`provider "aws" in an unknown namespace on the public registry, built-in: no, legacy: no`,
+		},
+		"built-in provider": {
+			MustParseProviderSource("terraform.io/builtin/terraform"),
+			`provider "terraform" in namespace "builtin" on the built-in provider host "terraform.io", built-in: yes, legacy: no`,
+		},
+		"private provider": {
+			MustParseProviderSource("éxample.com/corp/widget"),
+			`provider "widget" in namespace "corp" on registry "éxample.com", built-in: no, legacy: no`,
+		},
+		"zero provider": {
+			Provider{},
+			`no provider address`,
+		},
+		"module package": {
+			MustParseModuleSource("hashicorp/consul/aws").Package,
+			`module "consul" for target system "aws" in namespace "hashicorp" on the public registry`,
+		},
+		"module": {
+			MustParseModuleSource("example.com/hashicorp/consul/aws//modules/foo"),
+			`module "consul" for target system "aws" in namespace "hashicorp" on registry "example.com", subdirectory "modules/foo"`,
+		},
+		"zero module": {
+			Module{},
+			`no module address`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.addr.Describe(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
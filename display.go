@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+// DisplayPolicy selects how the ForDisplayWith methods render an address.
+type DisplayPolicy int
+
+const (
+	// DisplayDefault renders an address as its ForDisplay method does,
+	// omitting the hostname if it is the default registry hostname.
+	DisplayDefault DisplayPolicy = iota
+
+	// DisplayQualified always includes the hostname, as the String method
+	// does. This is useful for audit logs and other output where
+	// unambiguous addresses are more important than brevity.
+	DisplayQualified
+
+	// DisplayShortest uses the most concise source string that would
+	// parse to the same address, as the ShortestForm methods do. This is
+	// useful for user interfaces with limited space.
+	DisplayShortest
+)
+
+// ForDisplayWith returns a string representation of the address chosen by
+// the given policy.
+func (pt Provider) ForDisplayWith(policy DisplayPolicy) string {
+	switch policy {
+	case DisplayQualified:
+		return pt.String()
+	case DisplayShortest:
+		return pt.ShortestForm()
+	default:
+		return pt.ForDisplay()
+	}
+}
+
+// ForDisplayWith returns a string representation of the address chosen by
+// the given policy.
+//
+// The shortest form of a module package address is the same as its
+// ForDisplay result.
+func (s ModulePackage) ForDisplayWith(policy DisplayPolicy) string {
+	if policy == DisplayQualified {
+		return s.String()
+	}
+	return s.ForDisplay()
+}
+
+// ForDisplayWith returns a string representation of the address chosen by
+// the given policy.
+func (s Module) ForDisplayWith(policy DisplayPolicy) string {
+	switch policy {
+	case DisplayQualified:
+		return s.String()
+	case DisplayShortest:
+		return s.ShortestForm()
+	default:
+		return s.ForDisplay()
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestForDisplayWith(t *testing.T) {
+	tests := map[string]struct {
+		addr interface {
+			ForDisplayWith(DisplayPolicy) string
+		}
+		want [3]string
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws"),
+			[3]string{"hashicorp/aws", "registry.terraform.io/hashicorp/aws", "hashicorp/aws"},
+		},
+		"provider with unknown namespace": {
+			MustParseProviderSource("aws"),
+			[3]string{"?/aws", "registry.terraform.io/?/aws", "aws"},
+		},
+		"provider on other host": {
+			MustParseProviderSource("example.com/hashicorp/aws"),
+			[3]string{"example.com/hashicorp/aws", "example.com/hashicorp/aws", "example.com/hashicorp/aws"},
+		},
+		"module package": {
+			MustParseModuleSource("hashicorp/consul/aws").Package,
+			[3]string{"hashicorp/consul/aws", "registry.terraform.io/hashicorp/consul/aws", "hashicorp/consul/aws"},
+		},
+		"module": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			[3]string{"hashicorp/consul/aws//modules/consul-cluster", "registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster", "hashicorp/consul/aws//modules/consul-cluster"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := [3]string{
+				test.addr.ForDisplayWith(DisplayDefault),
+				test.addr.ForDisplayWith(DisplayQualified),
+				test.addr.ForDisplayWith(DisplayShortest),
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong results\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// EquivalenceSet records declarations that different provider addresses
+// refer to the same logical provider, such as when a provider is mirrored
+// from a public registry into a private one.
+//
+// Comparing addresses using Canonical or Equivalent then avoids reporting
+// differences between, for example, the dependency lock files of
+// environments that install providers from different mirrors.
+//
+// The zero value is an empty set ready to use, and a nil *EquivalenceSet
+// treats every address as distinct.
+type EquivalenceSet struct {
+	hosts   map[svchost.Hostname]svchost.Hostname
+	aliases map[Provider]Provider
+}
+
+// DeclareHost declares that every provider on the mirror host is
+// equivalent to the provider with the same namespace and type on the
+// origin host.
+//
+// If the mirror host is already a mirror of some other host then the two
+// groups of equivalent hosts are merged, as for Declare.
+func (s *EquivalenceSet) DeclareHost(mirror, origin svchost.Hostname) {
+	origin = s.canonicalHost(origin)
+	mirror = s.canonicalHost(mirror)
+	if mirror == origin {
+		return
+	}
+	if s.hosts == nil {
+		s.hosts = make(map[svchost.Hostname]svchost.Hostname)
+	}
+	s.hosts[mirror] = origin
+
+	// Merging two hosts can make provider aliases that were previously
+	// distinct refer to one another, so we re-declare all of them under
+	// the new host mapping, which merges any groups that are now joined
+	// rather than creating a cycle.
+	if len(s.aliases) == 0 {
+		return
+	}
+	old := s.aliases
+	s.aliases = nil
+	keys := make([]Provider, 0, len(old))
+	for alias := range old {
+		keys = append(keys, alias)
+	}
+	SortProviders(keys)
+	for _, alias := range keys {
+		s.Declare(old[alias], alias)
+	}
+}
+
+// Declare declares that each of the given aliases is equivalent to the
+// given canonical provider address.
+//
+// If an alias is already equivalent to some other address then the two
+// groups of equivalent addresses are merged, and the canonical address of
+// the merged group is the canonical address of the given one.
+func (s *EquivalenceSet) Declare(canonical Provider, aliases ...Provider) {
+	canonical = s.Canonical(canonical)
+	for _, alias := range aliases {
+		root := s.Canonical(alias)
+		if root == canonical {
+			continue
+		}
+		if s.aliases == nil {
+			s.aliases = make(map[Provider]Provider)
+		}
+		s.aliases[root] = canonical
+	}
+}
+
+// Canonical returns the canonical address of the group of equivalent
+// addresses that the given address belongs to, or the given address
+// itself if no equivalence has been declared for it.
+func (s *EquivalenceSet) Canonical(p Provider) Provider {
+	if s == nil {
+		return p
+	}
+	// Declare links only canonical addresses, and DeclareHost re-declares
+	// all of the aliases whenever hosts are merged, so the aliases never
+	// form a cycle and this always terminates.
+	for {
+		p.Hostname = s.canonicalHost(p.Hostname)
+		next, ok := s.aliases[p]
+		if !ok {
+			return p
+		}
+		p = next
+	}
+}
+
+// Equivalent returns true if the two given addresses have the same
+// canonical address.
+func (s *EquivalenceSet) Equivalent(a, b Provider) bool {
+	return s.Canonical(a) == s.Canonical(b)
+}
+
+// canonicalHost returns the host that the given host is a mirror of,
+// following any chain of mirrors, or the given host if it is not a mirror.
+func (s *EquivalenceSet) canonicalHost(host svchost.Hostname) svchost.Hostname {
+	if s == nil {
+		return host
+	}
+	for {
+		origin, ok := s.hosts[host]
+		if !ok {
+			return host
+		}
+		host = origin
+	}
+}
+
+// LookupEquivalentProvider returns the value in the given map whose key is
+// equivalent to the given provider address according to the given set,
+// and true, or the zero value of T and false if there is no such key.
+//
+// An exact match is preferred. If several keys are equivalent other than
+// exactly, the one whose String result sorts first is used, so that the
+// result is deterministic.
+func LookupEquivalentProvider[T any](s *EquivalenceSet, m map[Provider]T, p Provider) (T, bool) {
+	if v, ok := m[p]; ok {
+		return v, true
+	}
+	want := s.Canonical(p)
+	var best Provider
+	found := false
+	for k := range m {
+		if s.Canonical(k) == want && (!found || k.String() < best.String()) {
+			best, found = k, true
+		}
+	}
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return m[best], true
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestEquivalenceSet(t *testing.T) {
+	aws := MustParseProviderSource("hashicorp/aws")
+	mirroredAWS := MustParseProviderSource("mirror.corp.example/hashicorp/aws")
+	forkedAWS := MustParseProviderSource("example.com/acme/aws")
+	null := MustParseProviderSource("hashicorp/null")
+	mirroredNull := MustParseProviderSource("mirror.corp.example/hashicorp/null")
+	nestedNull := MustParseProviderSource("nested.corp.example/hashicorp/null")
+
+	var s EquivalenceSet
+	s.DeclareHost(svchost.Hostname("mirror.corp.example"), DefaultProviderRegistryHost)
+	s.DeclareHost(svchost.Hostname("nested.corp.example"), svchost.Hostname("mirror.corp.example"))
+	s.Declare(aws, forkedAWS)
+
+	tests := map[string]struct {
+		a, b Provider
+		want bool
+	}{
+		"same":                  {aws, aws, true},
+		"mirrored host":         {mirroredAWS, aws, true},
+		"declared alias":        {forkedAWS, aws, true},
+		"alias and mirror":      {forkedAWS, mirroredAWS, true},
+		"chained mirror":        {nestedNull, null, true},
+		"mirror of other":       {mirroredNull, aws, false},
+		"different type":        {aws, null, false},
+		"undeclared other host": {MustParseProviderSource("other.example/hashicorp/aws"), aws, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := s.Equivalent(test.a, test.b); got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+
+	if got := s.Canonical(forkedAWS); got != aws {
+		t.Errorf("wrong canonical address %s; want %s", got, aws)
+	}
+}
+
+func TestEquivalenceSetMerge(t *testing.T) {
+	a := MustParseProviderSource("a.example/test/a")
+	b := MustParseProviderSource("b.example/test/b")
+	c := MustParseProviderSource("c.example/test/c")
+	d := MustParseProviderSource("d.example/test/d")
+
+	var s EquivalenceSet
+	s.Declare(a, b)
+	s.Declare(c, d)
+	s.Declare(d, b) // d is an alias of c, so c becomes canonical for all
+	s.Declare(a, c) // already equivalent, so no cycle is created
+
+	for _, p := range []Provider{a, b, c, d} {
+		if got, want := s.Canonical(p), c; got != want {
+			t.Errorf("wrong canonical address for %s: %s; want %s", p, got, want)
+		}
+	}
+}
+
+func TestEquivalenceSetNil(t *testing.T) {
+	var s *EquivalenceSet
+	aws := MustParseProviderSource("hashicorp/aws")
+	if got := s.Canonical(aws); got != aws {
+		t.Errorf("wrong canonical address %s", got)
+	}
+	if s.Equivalent(aws, MustParseProviderSource("mirror.corp.example/hashicorp/aws")) {
+		t.Error("nil set treats different addresses as equivalent")
+	}
+}
+
+func TestLookupEquivalentProvider(t *testing.T) {
+	var s EquivalenceSet
+	s.DeclareHost(svchost.Hostname("mirror.corp.example"), DefaultProviderRegistryHost)
+
+	m := map[Provider]string{
+		MustParseProviderSource("mirror.corp.example/hashicorp/aws"): "5.0.0",
+	}
+	got, ok := LookupEquivalentProvider(&s, m, MustParseProviderSource("hashicorp/aws"))
+	if !ok || got != "5.0.0" {
+		t.Errorf("wrong result %q, %t", got, ok)
+	}
+	if _, ok := LookupEquivalentProvider(&s, m, MustParseProviderSource("hashicorp/null")); ok {
+		t.Error("unexpected match for hashicorp/null")
+	}
+}
+
+func TestEquivalenceSetHostAfterAlias(t *testing.T) {
+	h1 := svchost.Hostname("h1.example.com")
+	h2 := svchost.Hostname("h2.example.com")
+	x1 := MustParseProviderSource("h1.example.com/ns/x")
+	x2 := MustParseProviderSource("h2.example.com/ns/x")
+	y1 := MustParseProviderSource("h1.example.com/ns/y")
+	y2 := MustParseProviderSource("h2.example.com/ns/y")
+
+	tests := map[string]func(s *EquivalenceSet){
+		"alias then same host mapping": func(s *EquivalenceSet) {
+			s.Declare(x1, x2)
+			s.DeclareHost(h2, h1)
+		},
+		"alias then opposite host mapping": func(s *EquivalenceSet) {
+			s.Declare(x1, x2)
+			s.DeclareHost(h1, h2)
+		},
+		"crossed aliases joined by host mapping": func(s *EquivalenceSet) {
+			s.Declare(y1, x1)
+			s.Declare(x2, y2)
+			s.DeclareHost(h2, h1)
+		},
+	}
+
+	for name, declare := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s EquivalenceSet
+			declare(&s)
+
+			// Each of these calls would previously never return.
+			want := s.Canonical(x1)
+			for _, p := range []Provider{x1, x2} {
+				if got := s.Canonical(p); got != want {
+					t.Errorf("wrong canonical address for %s: %s; want %s", p, got, want)
+				}
+			}
+			if name == "crossed aliases joined by host mapping" && !s.Equivalent(x1, y2) {
+				t.Errorf("%s and %s are not equivalent", x1, y2)
+			}
+		})
+	}
+}
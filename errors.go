@@ -5,6 +5,8 @@ package tfaddr
 
 import (
 	"fmt"
+
+	svchost "github.com/hashicorp/terraform-svchost"
 )
 
 type ParserError struct {
@@ -15,3 +17,127 @@ type ParserError struct {
 func (pe *ParserError) Error() string {
 	return fmt.Sprintf("%s: %s", pe.Summary, pe.Detail)
 }
+
+// HostPolicyError is returned by a Parser when an otherwise-valid address
+// uses a registry hostname that the parser's host policy doesn't permit.
+type HostPolicyError struct {
+	// Hostname is the registry hostname of the rejected address, which may
+	// have been implied by omission rather than written explicitly.
+	Hostname svchost.Hostname
+
+	// Denied is true if the hostname was rejected because it appears in
+	// Parser.DeniedHosts, or false if it was rejected because it doesn't
+	// appear in a non-empty Parser.AllowedHosts.
+	Denied bool
+
+	// Kind, if not UnknownAddressKind, is the kind of the rejected address,
+	// which was rejected because the HostProfile of its hostname doesn't
+	// support addresses of that kind.
+	Kind AddressKind
+}
+
+func (e *HostPolicyError) Error() string {
+	if e.Kind != UnknownAddressKind {
+		return fmt.Sprintf("registry hostname %q doesn't serve %s addresses", e.Hostname.ForDisplay(), e.Kind)
+	}
+	if e.Denied {
+		return fmt.Sprintf("registry hostname %q is not allowed", e.Hostname.ForDisplay())
+	}
+	return fmt.Sprintf("registry hostname %q is not in the list of allowed hosts", e.Hostname.ForDisplay())
+}
+
+// SegmentError is returned when an individual segment of an address, given
+// separately rather than as part of a source string, is invalid.
+type SegmentError struct {
+	// Segment names the invalid segment, such as "hostname" or "namespace".
+	Segment string
+
+	// Value is the invalid value as given.
+	Value string
+
+	// Err describes why the value is invalid.
+	Err error
+}
+
+func (e *SegmentError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Segment, e.Value, e.Err)
+}
+
+func (e *SegmentError) Unwrap() error {
+	return e.Err
+}
+
+// LocalizedError is returned by a Parser whose Localize function has
+// translated the message of an error into the user's language.
+type LocalizedError struct {
+	// Message is the translated message.
+	Message string
+
+	// Err is the original error, which describes the problem in English.
+	Err error
+}
+
+func (e *LocalizedError) Error() string {
+	return e.Message
+}
+
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// InterpolationError is returned when a source string contains a template
+// interpolation sequence "${" or template directive sequence "%{", which
+// suggests that the author expected it to be evaluated as a template.
+//
+// Source addresses must be static strings, because Terraform needs to
+// install providers and modules before it can evaluate any expressions.
+type InterpolationError struct {
+	// Source is the source string as given.
+	Source string
+
+	// Offset is the byte offset in Source where the template sequence
+	// begins.
+	Offset int
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("source address %q contains the template sequence %q, but source addresses must be static strings that don't refer to variables or other dynamic values", e.Source, e.Source[e.Offset:e.Offset+2])
+}
+
+// SubdirLimitError is returned by a Parser when the subdirectory of a module
+// address is deeper or longer than permitted by the parser's Limits.
+type SubdirLimitError struct {
+	// Subdir is the normalized subdirectory path that was rejected.
+	Subdir string
+
+	// MaxDepth is the Limits.MaxSubdirDepth that was exceeded, or zero if
+	// the depth was within limits.
+	MaxDepth int
+
+	// MaxLength is the Limits.MaxSubdirLength that was exceeded, or zero if
+	// the length was within limits.
+	MaxLength int
+}
+
+func (e *SubdirLimitError) Error() string {
+	if e.MaxDepth > 0 {
+		return fmt.Sprintf("invalid subdirectory path %q: must have no more than %d slash-separated segments", e.Subdir, e.MaxDepth)
+	}
+	return fmt.Sprintf("invalid subdirectory path %q: must be no longer than %d bytes", e.Subdir, e.MaxLength)
+}
+
+// SwappedSegmentsError is returned by lenient module parsing, when enabled
+// using Parser.DetectSwappedSegments, if the namespace, name, and target
+// system of a module address seem to have been written in the wrong order.
+type SwappedSegmentsError struct {
+	// Source is the source string as given.
+	Source string
+
+	// Suggestion is the same source string with its segments reordered so
+	// that a known target system is in the last position.
+	Suggestion string
+}
+
+func (e *SwappedSegmentsError) Error() string {
+	return fmt.Sprintf("module source address %q seems to have its segments in the wrong order, which must be \"namespace/name/system\"; did you mean %q?", e.Source, e.Suggestion)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ShellQuoted returns the fully-qualified string form of the address, as
+// returned by String, quoted if necessary for use as a single word in a
+// POSIX shell command line.
+//
+// Addresses containing only ASCII letters, digits, and punctuation that is
+// not special to the shell are returned unquoted. Any others, including
+// those with internationalized hostnames or namespaces, are enclosed in
+// single quotes.
+func (pt Provider) ShellQuoted() string {
+	return shellQuote(pt.String())
+}
+
+// ShellQuoted returns the fully-qualified string form of the module package
+// address, quoted if necessary for use as a single word in a POSIX shell
+// command line, as described for Provider.ShellQuoted.
+func (s ModulePackage) ShellQuoted() string {
+	return shellQuote(s.String())
+}
+
+// ShellQuoted returns the fully-qualified string form of the module
+// address, quoted if necessary for use as a single word in a POSIX shell
+// command line, as described for Provider.ShellQuoted.
+func (s Module) ShellQuoted() string {
+	return shellQuote(s.String())
+}
+
+// URLPathEscaped returns the fully-qualified address in a form suitable for
+// use as part of the path of a URL, such as in the URL of a web page that
+// describes the provider.
+//
+// The hostname is given in its ASCII-compatible "punycode" form, and any
+// other characters that are not permitted in a URL path are
+// percent-encoded. The slashes between segments are not escaped, and so
+// the result has the same segment structure as the address. The result
+// has no leading slash.
+func (pt Provider) URLPathEscaped() string {
+	return escapeURLPath(pt.Hostname.String(), pt.Namespace, pt.Type)
+}
+
+// URLPathEscaped returns the fully-qualified module package address in a
+// form suitable for use as part of the path of a URL, as described for
+// Provider.URLPathEscaped.
+func (s ModulePackage) URLPathEscaped() string {
+	return escapeURLPath(s.Host.String(), s.Namespace, s.Name, s.TargetSystem)
+}
+
+// URLPathEscaped returns the fully-qualified module address in a form
+// suitable for use as part of the path of a URL, as described for
+// Provider.URLPathEscaped.
+//
+// The subdirectory, if any, follows the package address after a double
+// slash, as in the String form, and each of its segments is escaped
+// separately.
+func (s Module) URLPathEscaped() string {
+	ret := s.Package.URLPathEscaped()
+	if s.Subdir != "" {
+		ret += "//" + escapeURLPath(strings.Split(s.Subdir, "/")...)
+	}
+	return ret
+}
+
+// escapeURLPath percent-encodes each of the given segments for use in a URL
+// path and joins them with slashes.
+func escapeURLPath(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// shellQuote returns the given string unchanged if it contains only
+// characters that are never special to a POSIX shell, or otherwise
+// encloses it in single quotes.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, isShellSpecialRune) < 0 {
+		return s
+	}
+	// A single-quoted string can't contain a single quote, so we end the
+	// quoted string, add an escaped quote, and then start a new one.
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isShellSpecialRune returns true if the given character might need quoting
+// in a POSIX shell command line. Non-ASCII characters are treated as special
+// because their interpretation depends on the shell's locale settings.
+func isShellSpecialRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("-_./:@+,=%", r):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestShellQuoted(t *testing.T) {
+	tests := map[string]struct {
+		addr interface{ ShellQuoted() string }
+		want string
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws"),
+			`registry.terraform.io/hashicorp/aws`,
+		},
+		"provider with port": {
+			MustParseProviderSource("example.com:8443/hashicorp/aws"),
+			`example.com:8443/hashicorp/aws`,
+		},
+		"provider with IDN hostname": {
+			MustParseProviderSource("испытание.com/hashicorp/aws"),
+			`'испытание.com/hashicorp/aws'`,
+		},
+		"module package": {
+			MustParseModuleSource("hashicorp/consul/aws").Package,
+			`registry.terraform.io/hashicorp/consul/aws`,
+		},
+		"module with subdir": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			`registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster`,
+		},
+		"module with special characters in subdir": {
+			MustParseModuleSource("hashicorp/consul/aws//it's here"),
+			`'registry.terraform.io/hashicorp/consul/aws//it'\''s here'`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.addr.ShellQuoted(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestURLPathEscaped(t *testing.T) {
+	tests := map[string]struct {
+		addr interface{ URLPathEscaped() string }
+		want string
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws"),
+			`registry.terraform.io/hashicorp/aws`,
+		},
+		"provider with IDN hostname and namespace": {
+			MustParseProviderSource("испытание.com/испытание/aws"),
+			`xn--80akhbyknj4f.com/%D0%B8%D1%81%D0%BF%D1%8B%D1%82%D0%B0%D0%BD%D0%B8%D0%B5/aws`,
+		},
+		"module package": {
+			MustParseModuleSource("example.com:8443/hashicorp/consul/aws").Package,
+			`example.com:8443/hashicorp/consul/aws`,
+		},
+		"module with subdir": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/my cluster#1"),
+			`registry.terraform.io/hashicorp/consul/aws//modules/my%20cluster%231`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.addr.URLPathEscaped(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difference describes one segment that differs between two addresses, as
+// returned by Explain.
+type Difference struct {
+	// Segment names the segment that differs, using the same names as
+	// SegmentError: "hostname", "namespace", "type", "module name",
+	// "target system", or "subdirectory".
+	//
+	// If the two addresses are of different types then there is a single
+	// Difference whose Segment is "address type", and whose Old and New
+	// fields are the names of the Go types.
+	Segment string
+
+	// Old and New are the segment's values in the first and second
+	// addresses given to Explain, respectively. Hostnames are given in
+	// their display form.
+	Old, New string
+
+	// CaseOnly is true if Old and New differ only in letter case.
+	//
+	// This can only be true for segments that this package doesn't
+	// normalize to lowercase, such as module namespaces and names. Some
+	// registries treat those case-insensitively, so a case-only change may
+	// or may not refer to a different package.
+	CaseOnly bool
+}
+
+func (d Difference) String() string {
+	if d.CaseOnly {
+		return fmt.Sprintf("%s changed from %q to %q (letter case only)", d.Segment, d.Old, d.New)
+	}
+	return fmt.Sprintf("%s changed from %q to %q", d.Segment, d.Old, d.New)
+}
+
+// Explain compares two addresses of the same type and returns a description
+// of each segment that differs between them, in the order the segments
+// appear in the address. The result is empty if the addresses are equal.
+//
+// The supported address types are Provider, Module, and ModulePackage.
+// Explain panics if given any other type, since that indicates a bug in the
+// caller.
+func Explain(a, other any) []Difference {
+	switch a := a.(type) {
+	case Provider:
+		b, ok := other.(Provider)
+		if !ok {
+			return differentTypes(a, other)
+		}
+		var diffs []Difference
+		diffs = appendDifference(diffs, "hostname", a.Hostname.ForDisplay(), b.Hostname.ForDisplay())
+		diffs = appendDifference(diffs, "namespace", a.Namespace, b.Namespace)
+		diffs = appendDifference(diffs, "type", a.Type, b.Type)
+		return diffs
+	case ModulePackage:
+		b, ok := other.(ModulePackage)
+		if !ok {
+			return differentTypes(a, other)
+		}
+		return explainModulePackage(nil, a, b)
+	case Module:
+		b, ok := other.(Module)
+		if !ok {
+			return differentTypes(a, other)
+		}
+		diffs := explainModulePackage(nil, a.Package, b.Package)
+		diffs = appendDifference(diffs, "subdirectory", a.Subdir, b.Subdir)
+		return diffs
+	default:
+		panic(fmt.Sprintf("tfaddr.Explain doesn't support %T", a))
+	}
+}
+
+func explainModulePackage(diffs []Difference, a, b ModulePackage) []Difference {
+	diffs = appendDifference(diffs, "hostname", a.Host.ForDisplay(), b.Host.ForDisplay())
+	diffs = appendDifference(diffs, "namespace", a.Namespace, b.Namespace)
+	diffs = appendDifference(diffs, "module name", a.Name, b.Name)
+	diffs = appendDifference(diffs, "target system", a.TargetSystem, b.TargetSystem)
+	return diffs
+}
+
+func appendDifference(diffs []Difference, segment, old, new string) []Difference {
+	if old == new {
+		return diffs
+	}
+	return append(diffs, Difference{
+		Segment:  segment,
+		Old:      old,
+		New:      new,
+		CaseOnly: strings.EqualFold(old, new),
+	})
+}
+
+func differentTypes(a, b any) []Difference {
+	return []Difference{
+		{
+			Segment: "address type",
+			Old:     fmt.Sprintf("%T", a),
+			New:     fmt.Sprintf("%T", b),
+		},
+	}
+}
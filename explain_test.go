@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExplain(t *testing.T) {
+	tests := map[string]struct {
+		a, b any
+		want []Difference
+	}{
+		"equal providers": {
+			a:    MustParseProviderSource("hashicorp/aws"),
+			b:    MustParseProviderSource("registry.terraform.io/hashicorp/aws"),
+			want: nil,
+		},
+		"provider hostname and type": {
+			a: MustParseProviderSource("hashicorp/aws"),
+			b: MustParseProviderSource("example.com/hashicorp/awscc"),
+			want: []Difference{
+				{Segment: "hostname", Old: "registry.terraform.io", New: "example.com"},
+				{Segment: "type", Old: "aws", New: "awscc"},
+			},
+		},
+		"module case-only name change": {
+			a: MustParseModuleSource("hashicorp/consul/aws"),
+			b: MustParseModuleSource("hashicorp/Consul/aws"),
+			want: []Difference{
+				{Segment: "module name", Old: "consul", New: "Consul", CaseOnly: true},
+			},
+		},
+		"module subdir": {
+			a: MustParseModuleSource("hashicorp/consul/aws//modules/a"),
+			b: MustParseModuleSource("hashicorp/consul/aws"),
+			want: []Difference{
+				{Segment: "subdirectory", Old: "modules/a", New: ""},
+			},
+		},
+		"module packages": {
+			a: MustParseModuleSource("hashicorp/consul/aws").Package,
+			b: MustParseModuleSource("example.com/acme/consul/google").Package,
+			want: []Difference{
+				{Segment: "hostname", Old: "registry.terraform.io", New: "example.com"},
+				{Segment: "namespace", Old: "hashicorp", New: "acme"},
+				{Segment: "target system", Old: "aws", New: "google"},
+			},
+		},
+		"different types": {
+			a: MustParseModuleSource("hashicorp/consul/aws"),
+			b: MustParseProviderSource("hashicorp/aws"),
+			want: []Difference{
+				{Segment: "address type", Old: "tfaddr.Module", New: "tfaddr.Provider"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Explain(test.a, test.b)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDifferenceString(t *testing.T) {
+	tests := map[string]struct {
+		diff Difference
+		want string
+	}{
+		"simple": {
+			Difference{Segment: "type", Old: "aws", New: "awscc"},
+			`type changed from "aws" to "awscc"`,
+		},
+		"case only": {
+			Difference{Segment: "module name", Old: "consul", New: "Consul", CaseOnly: true},
+			`module name changed from "consul" to "Consul" (letter case only)`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.diff.String(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
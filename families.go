@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// AddressFamily describes an additional family of source addresses, defined
+// outside of this package, that takes part in DetectSourceKind and
+// ParseAny once registered with RegisterAddressFamily.
+//
+// This allows tools for other registries in the Terraform ecosystem to
+// classify their own addresses alongside provider and module addresses.
+type AddressFamily struct {
+	// Name is a short description of the family, such as "packer plugin",
+	// which is also returned by the String method of its AddressKind. It
+	// must be unique among registered families.
+	Name string
+
+	// Detect reports whether the given string is intended to be an address
+	// of this family. It should be a quick check of the overall syntax,
+	// such as a prefix, that doesn't fully validate the address.
+	Detect func(raw string) bool
+
+	// Parse parses an address of this family that Detect has accepted.
+	Parse func(raw string) (any, error)
+
+	// Validate, if set, checks an address returned by Parse against any
+	// additional rules, such as those of a particular registry.
+	Validate func(addr any) error
+}
+
+var addressFamilies struct {
+	sync.RWMutex
+	list []AddressFamily
+}
+
+// RegisterAddressFamily registers an additional family of source addresses
+// and returns the AddressKind that identifies it.
+//
+// Registration is intended to happen during program initialization, such
+// as in the init function of the package defining the family. It panics if
+// the family is missing its Name, Detect, or Parse, or if a family with the
+// same name is already registered.
+func RegisterAddressFamily(f AddressFamily) AddressKind {
+	if f.Name == "" || f.Detect == nil || f.Parse == nil {
+		panic("tfaddr: address family requires Name, Detect, and Parse")
+	}
+
+	addressFamilies.Lock()
+	defer addressFamilies.Unlock()
+	for _, existing := range addressFamilies.list {
+		if existing.Name == f.Name {
+			panic(fmt.Sprintf("tfaddr: address family %q is already registered", f.Name))
+		}
+	}
+	addressFamilies.list = append(addressFamilies.list, f)
+	return firstExtensionKind + AddressKind(len(addressFamilies.list)-1)
+}
+
+// firstExtensionKind is the AddressKind of the first family registered with
+// RegisterAddressFamily.
+const firstExtensionKind = ModuleRemoteKind + 1
+
+// registeredFamily returns the registered family with the given kind.
+func registeredFamily(k AddressKind) (AddressFamily, bool) {
+	addressFamilies.RLock()
+	defer addressFamilies.RUnlock()
+	i := int(k - firstExtensionKind)
+	if i < 0 || i >= len(addressFamilies.list) {
+		return AddressFamily{}, false
+	}
+	return addressFamilies.list[i], true
+}
+
+// DetectSourceKind returns the kind of address that the given source string
+// seems to be, or UnknownAddressKind if it matches none of them.
+//
+// Module registry, provider, and remote module addresses are checked first,
+// in that order, so a string that is valid as both a module registry
+// address and a provider address, such as "hashicorp/consul/aws", is
+// detected as a module registry address. Registered address families are
+// checked afterwards, in the order they were registered, so they cannot
+// take over strings that are valid provider or module addresses.
+func DetectSourceKind(raw string) AddressKind {
+	return Parser{}.DetectSourceKind(raw)
+}
+
+// DetectSourceKind is like the package-level function of the same name,
+// but applies the rules configured in the receiver when checking for
+// module registry and provider addresses. The receiver's Observer is not
+// notified of the parsing done to detect the kind.
+func (p Parser) DetectSourceKind(raw string) AddressKind {
+	p.Observer = nil
+	if _, err := p.ParseModuleSource(raw); err == nil {
+		return ModuleRegistryKind
+	}
+	if _, err := p.ParseProviderSource(raw); err == nil {
+		return ProviderKind
+	}
+	if _, err := ParseModuleSourceRemote(raw); err == nil {
+		return ModuleRemoteKind
+	}
+
+	addressFamilies.RLock()
+	defer addressFamilies.RUnlock()
+	for i, f := range addressFamilies.list {
+		if f.Detect(raw) {
+			return firstExtensionKind + AddressKind(i)
+		}
+	}
+	return UnknownAddressKind
+}
+
+// ParseAny parses the given source string as whichever kind of address
+// DetectSourceKind reports, returning a Module, a Provider, a
+// ModuleSourceRemote, or the result of a registered address family's Parse
+// function.
+func ParseAny(raw string) (any, error) {
+	return Parser{}.ParseAny(raw)
+}
+
+// ParseAny is like the package-level function of the same name, but
+// applies the rules configured in the receiver when parsing module registry
+// and provider addresses. The receiver's Observer is notified only of the
+// final parse of the detected kind, not of detection.
+func (p Parser) ParseAny(raw string) (any, error) {
+	kind := p.DetectSourceKind(raw)
+	switch kind {
+	case ModuleRegistryKind:
+		return p.ParseModuleSource(raw)
+	case ProviderKind:
+		return p.ParseProviderSource(raw)
+	case ModuleRemoteKind:
+		return ParseModuleSourceRemote(raw)
+	case UnknownAddressKind:
+		return nil, fmt.Errorf("source address %q is not a valid module registry address, provider address, remote module address, or address of any registered family", raw)
+	}
+
+	f, _ := registeredFamily(kind)
+	addr, err := f.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address %q: %s", f.Name, raw, err)
+	}
+	if f.Validate != nil {
+		if err := f.Validate(addr); err != nil {
+			return nil, fmt.Errorf("invalid %s address %q: %s", f.Name, raw, err)
+		}
+	}
+	return addr, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testPluginAddr struct {
+	Name string
+}
+
+// testPluginKind is registered at initialization, as a real extension
+// would be, so that repeated test runs don't register it twice.
+var testPluginKind = RegisterAddressFamily(AddressFamily{
+	Name: "test plugin",
+	Detect: func(raw string) bool {
+		return strings.HasPrefix(raw, "plugin:")
+	},
+	Parse: func(raw string) (any, error) {
+		name := strings.TrimPrefix(raw, "plugin:")
+		if name == "" {
+			return nil, fmt.Errorf("must have a name")
+		}
+		return testPluginAddr{Name: name}, nil
+	},
+	Validate: func(addr any) error {
+		if addr.(testPluginAddr).Name == "forbidden" {
+			return fmt.Errorf("name is reserved")
+		}
+		return nil
+	},
+})
+
+func TestDetectSourceKind(t *testing.T) {
+	tests := map[string]AddressKind{
+		"hashicorp/consul/aws":            ModuleRegistryKind,
+		"hashicorp/consul/aws//modules/a": ModuleRegistryKind,
+		"example.com/hashicorp/aws":       ProviderKind,
+		"hashicorp/aws":                   ProviderKind,
+		"aws":                             ProviderKind,
+		"git::https://example.com/x.git":  ModuleRemoteKind,
+		"plugin:docker":                   testPluginKind,
+		"plugin:":                         testPluginKind,
+		"./local":                         UnknownAddressKind,
+		"":                                UnknownAddressKind,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got := DetectSourceKind(input); got != want {
+				t.Errorf("wrong kind %s; want %s", got, want)
+			}
+		})
+	}
+
+	if got, want := testPluginKind.String(), "test plugin"; got != want {
+		t.Errorf("wrong name for registered kind %q; want %q", got, want)
+	}
+	if got, want := (testPluginKind + 1).String(), "unknown"; got != want {
+		t.Errorf("wrong name for unregistered kind %q; want %q", got, want)
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	tests := map[string]struct {
+		want    any
+		wantErr string
+	}{
+		"hashicorp/consul/aws": {
+			want: MustParseModuleSource("hashicorp/consul/aws"),
+		},
+		"hashicorp/aws": {
+			want: MustParseProviderSource("hashicorp/aws"),
+		},
+		"plugin:docker": {
+			want: testPluginAddr{Name: "docker"},
+		},
+		"plugin:": {
+			wantErr: `invalid test plugin address "plugin:": must have a name`,
+		},
+		"plugin:forbidden": {
+			wantErr: `invalid test plugin address "plugin:forbidden": name is reserved`,
+		},
+		"./local": {
+			wantErr: `source address "./local" is not a valid module registry address, provider address, remote module address, or address of any registered family`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseAny(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterAddressFamily_duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration did not panic")
+		}
+	}()
+	RegisterAddressFamily(AddressFamily{
+		Name:   "test plugin",
+		Detect: func(string) bool { return false },
+		Parse:  func(string) (any, error) { return nil, nil },
+	})
+}
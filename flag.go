@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+)
+
+// ProviderListFlag is a flag.Value that accumulates provider source
+// addresses from repeated occurrences of a command line flag, such as
+// "-provider=hashicorp/aws -provider=hashicorp/google".
+//
+// Each value is parsed using Parser, and a value that parses to an address
+// already in the list is silently ignored, so the result never contains
+// duplicates. The zero value is an empty list using the default parsing
+// rules.
+//
+//	var providers tfaddr.ProviderListFlag
+//	flag.Var(&providers, "provider", "a provider source address")
+type ProviderListFlag struct {
+	// Parser is used to parse each value given on the command line.
+	Parser Parser
+
+	providers ProviderList
+}
+
+// Providers returns the providers given so far, in the order that they
+// were first given.
+func (f *ProviderListFlag) Providers() []Provider {
+	if f == nil {
+		return nil
+	}
+	return f.providers.Providers()
+}
+
+// String returns a comma-separated list of the providers given so far, in
+// the form returned by Provider.ForDisplay.
+func (f *ProviderListFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	names := make([]string, f.providers.Len())
+	for i, p := range f.providers.items {
+		names[i] = p.ForDisplay()
+	}
+	return strings.Join(names, ",")
+}
+
+// Set parses the given provider source address and adds it to the list,
+// unless it is already present.
+func (f *ProviderListFlag) Set(value string) error {
+	p, err := f.Parser.ParseProviderSource(value)
+	if err != nil {
+		return err
+	}
+	f.providers.Add(p)
+	return nil
+}
+
+// Get returns the same result as Providers, to implement flag.Getter.
+func (f *ProviderListFlag) Get() any {
+	return f.Providers()
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestProviderListFlag(t *testing.T) {
+	tests := map[string]struct {
+		parser  Parser
+		args    []string
+		want    []Provider
+		wantErr string
+	}{
+		"none": {
+			args: nil,
+			want: nil,
+		},
+		"several": {
+			args: []string{"-provider=hashicorp/aws", "-provider", "example.com/foo/bar"},
+			want: []Provider{
+				MustParseProviderSource("hashicorp/aws"),
+				MustParseProviderSource("example.com/foo/bar"),
+			},
+		},
+		"duplicates": {
+			args: []string{
+				"-provider=hashicorp/aws",
+				"-provider=registry.terraform.io/HashiCorp/AWS",
+				"-provider=hashicorp/google",
+				"-provider=hashicorp/aws",
+			},
+			want: []Provider{
+				MustParseProviderSource("hashicorp/aws"),
+				MustParseProviderSource("hashicorp/google"),
+			},
+		},
+		"invalid": {
+			args:    []string{"-provider=hashicorp/aws", "-provider=foo/bar/baz/boop"},
+			wantErr: `invalid value "foo/bar/baz/boop" for flag -provider: Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name". The given string "foo/bar/baz/boop" looks like a module source address, which belongs in the "source" argument of a module block instead.`,
+		},
+		"host policy": {
+			parser:  Parser{AllowedHosts: []svchost.Hostname{"example.com"}},
+			args:    []string{"-provider=hashicorp/aws"},
+			wantErr: `invalid value "hashicorp/aws" for flag -provider: registry hostname "registry.terraform.io" is not in the list of allowed hosts`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			providers := ProviderListFlag{Parser: test.parser}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(&providers, "provider", "a provider source address")
+
+			err := fs.Parse(test.args)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, providers.Providers()); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviderListFlagString(t *testing.T) {
+	var providers ProviderListFlag
+	if got := providers.String(); got != "" {
+		t.Errorf("wrong string for empty list: %q", got)
+	}
+	for _, value := range []string{"hashicorp/aws", "example.com/foo/bar"} {
+		if err := providers.Set(value); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got, want := providers.String(), "hashicorp/aws,example.com/foo/bar"; got != want {
+		t.Errorf("wrong string\ngot:  %s\nwant: %s", got, want)
+	}
+}
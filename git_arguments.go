@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GitSourceArguments holds the query arguments of a git-based module source
+// address that the go-getter git getter understands, such as
+// "?ref=v1.2.0&depth=1".
+type GitSourceArguments struct {
+	// Ref is the branch, tag, or commit to check out, or empty to use the
+	// repository's default branch.
+	Ref string
+
+	// Depth, if greater than zero, requests a shallow clone with only that
+	// many commits of history.
+	Depth int
+
+	// SSHKey is the base64-encoded private key to use when cloning over
+	// SSH, or empty to use the SSH agent.
+	SSHKey string
+}
+
+// parseGitSourceArguments returns the git arguments from the given query,
+// or an error if any of them is invalid. Query arguments that the git
+// getter doesn't understand are ignored.
+func parseGitSourceArguments(query url.Values) (*GitSourceArguments, error) {
+	ret := &GitSourceArguments{
+		Ref:    query.Get("ref"),
+		SSHKey: query.Get("sshkey"),
+	}
+	if raw := query.Get("depth"); raw != "" {
+		depth, err := strconv.Atoi(raw)
+		if err != nil || depth < 0 {
+			return nil, fmt.Errorf("invalid git depth %q: must be a non-negative whole number", raw)
+		}
+		ret.Depth = depth
+	}
+	if ret.SSHKey != "" {
+		if _, err := base64.StdEncoding.DecodeString(ret.SSHKey); err != nil {
+			return nil, fmt.Errorf("invalid git sshkey: must be base64-encoded")
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+)
+
+// GoString implements fmt.GoStringer, returning a Go expression that
+// constructs the receiver, such as
+// tfaddr.NewProvider("registry.terraform.io", "hashicorp", "aws").
+//
+// Values that NewProvider can't construct, such as legacy provider
+// addresses, are instead shown as a struct literal.
+func (pt Provider) GoString() string {
+	if pt.IsZero() {
+		return "tfaddr.Provider{}"
+	}
+	if pt.Namespace != LegacyProviderNamespace && pt.Namespace != UnknownProviderNamespace {
+		ns, nsErr := ParseProviderPart(pt.Namespace)
+		typeName, typeErr := ParseProviderPart(pt.Type)
+		if nsErr == nil && typeErr == nil && ns == pt.Namespace && typeName == pt.Type {
+			return fmt.Sprintf("tfaddr.NewProvider(%q, %q, %q)", string(pt.Hostname), pt.Namespace, pt.Type)
+		}
+	}
+	return fmt.Sprintf("tfaddr.Provider{Type: %q, Namespace: %q, Hostname: %q}", pt.Type, pt.Namespace, string(pt.Hostname))
+}
+
+// GoString implements fmt.GoStringer, returning a Go expression that
+// constructs the receiver.
+func (s ModulePackage) GoString() string {
+	return fmt.Sprintf("tfaddr.ModulePackage{Host: %q, Namespace: %q, Name: %q, TargetSystem: %q}", string(s.Host), s.Namespace, s.Name, s.TargetSystem)
+}
+
+// GoString implements fmt.GoStringer, returning a Go expression that
+// constructs the receiver, such as
+// tfaddr.MustParseModuleSource("registry.terraform.io/hashicorp/consul/aws").
+//
+// Values that MustParseModuleSource can't construct, such as invalid
+// addresses, are instead shown as a struct literal.
+func (s Module) GoString() string {
+	if s.IsZero() {
+		return "tfaddr.Module{}"
+	}
+	if parsed, err := ParseModuleSource(s.String()); err == nil && parsed == s {
+		return fmt.Sprintf("tfaddr.MustParseModuleSource(%q)", s.String())
+	}
+	return fmt.Sprintf("tfaddr.Module{Package: %#v, Subdir: %q}", s.Package, s.Subdir)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGoString(t *testing.T) {
+	tests := map[string]struct {
+		input any
+		want  string
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws"),
+			`tfaddr.NewProvider("registry.terraform.io", "hashicorp", "aws")`,
+		},
+		"provider with internationalized hostname": {
+			MustParseProviderSource("испытание.com/foo/bar"),
+			`tfaddr.NewProvider("xn--80akhbyknj4f.com", "foo", "bar")`,
+		},
+		"legacy provider": {
+			MustParseProviderSource("-/aws"),
+			`tfaddr.Provider{Type: "aws", Namespace: "-", Hostname: "registry.terraform.io"}`,
+		},
+		"non-normalized provider": {
+			Provider{Hostname: DefaultProviderRegistryHost, Namespace: "HashiCorp", Type: "aws"},
+			`tfaddr.Provider{Type: "aws", Namespace: "HashiCorp", Hostname: "registry.terraform.io"}`,
+		},
+		"zero provider": {
+			Provider{},
+			`tfaddr.Provider{}`,
+		},
+		"module package": {
+			MustParseModuleSource("hashicorp/consul/aws").Package,
+			`tfaddr.ModulePackage{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "consul", TargetSystem: "aws"}`,
+		},
+		"module": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/foo"),
+			`tfaddr.MustParseModuleSource("registry.terraform.io/hashicorp/consul/aws//modules/foo")`,
+		},
+		"invalid module": {
+			Module{Package: ModulePackage{Host: "github.com", Namespace: "a", Name: "b", TargetSystem: "c"}},
+			`tfaddr.Module{Package: tfaddr.ModulePackage{Host: "github.com", Namespace: "a", Name: "b", TargetSystem: "c"}, Subdir: ""}`,
+		},
+		"zero module": {
+			Module{},
+			`tfaddr.Module{}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fmt.Sprintf("%#v", test.input)
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// Hash64 returns a 64-bit hash of the provider address, for uses such as
+// sharding and bloom filters.
+//
+// The hash is the 64-bit FNV-1a hash of the lowercase form of the result
+// of String. This definition is part of this package's compatibility
+// promise, so the hash of a particular address will not change in future
+// versions and may be stored or shared between programs.
+func (pt Provider) Hash64() uint64 {
+	return hash64(pt.String())
+}
+
+// Hash64 returns a 64-bit hash of the module package address, for uses
+// such as sharding and bloom filters.
+//
+// The hash is the 64-bit FNV-1a hash of the lowercase form of the result
+// of String. This definition is part of this package's compatibility
+// promise, so the hash of a particular address will not change in future
+// versions and may be stored or shared between programs.
+//
+// Because the hash is computed from the lowercase form, addresses that
+// differ only in the case of their namespace or name have the same hash,
+// matching how the public registry treats them.
+func (s ModulePackage) Hash64() uint64 {
+	return hash64(s.String())
+}
+
+func hash64(canonical string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(canonical)))
+	return h.Sum64()
+}
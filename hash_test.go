@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestHash64(t *testing.T) {
+	// These values are part of the compatibility promise, so they must
+	// never change.
+	tests := map[string]struct {
+		got  uint64
+		want uint64
+	}{
+		"provider": {
+			MustParseProviderSource("hashicorp/aws").Hash64(),
+			0xfb9fd5c92c293708,
+		},
+		"provider with custom host": {
+			MustParseProviderSource("испытание.com/foo/bar").Hash64(),
+			0xc1b6ecc088674997,
+		},
+		"module package": {
+			MustParseModuleSource("hashicorp/consul/aws").Package.Hash64(),
+			0xeacfec21d44ba04d,
+		},
+		"module package case-insensitive": {
+			MustParseModuleSource("HashiCorp/Consul/aws").Package.Hash64(),
+			0xeacfec21d44ba04d,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.got != test.want {
+				t.Errorf("wrong hash\ngot:  %#x\nwant: %#x", test.got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// HostProfile describes the conventions of a particular registry host
+// where they differ between registries, such as between
+// registry.terraform.io, Terraform Enterprise instances, and third-party
+// registries.
+//
+// A Parser consults the profile of the host of each address it parses,
+// either from Parser.HostProfiles or from DefaultHostProfile. The zero
+// value describes a host with no special conventions, which is what
+// Terraform assumes for any host other than the public registry.
+type HostProfile struct {
+	// CaseInsensitiveModules is true if the host matches the namespace,
+	// name, and target system of module addresses case-insensitively, as
+	// the public registry does. Otherwise they must match exactly.
+	CaseInsensitiveModules bool
+
+	// Families, if non-empty, lists the only kinds of address that the
+	// host serves, such as ProviderKind and ModuleRegistryKind. A Parser
+	// rejects addresses of any other kind on the host with a
+	// *HostPolicyError.
+	Families []AddressKind
+
+	// AllowPunycode is true if a Parser should accept the hostname in its
+	// ASCII-compatible "punycode" form, as if Parser.AllowPunycodeHosts
+	// were set for this host only.
+	AllowPunycode bool
+
+	// ReservedNamespacePrefixes are prefixes of namespaces that addresses
+	// on the host may not use, such as namespaces that the registry
+	// reserves for its own purposes. They are compared case-insensitively.
+	ReservedNamespacePrefixes []string
+}
+
+// DefaultHostProfile returns the profile that Terraform itself assumes for
+// the given registry hostname, which is the zero value for every host
+// except DefaultModuleRegistryHost.
+func DefaultHostProfile(host svchost.Hostname) HostProfile {
+	if host == DefaultModuleRegistryHost {
+		return HostProfile{CaseInsensitiveModules: true}
+	}
+	return HostProfile{}
+}
+
+// Supports returns true if the host serves addresses of the given kind.
+func (h HostProfile) Supports(kind AddressKind) bool {
+	if len(h.Families) == 0 {
+		return true
+	}
+	for _, k := range h.Families {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedPrefix returns the prefix in ReservedNamespacePrefixes that the
+// given namespace starts with, and true, or false if there is none.
+func (h HostProfile) reservedPrefix(namespace string) (string, bool) {
+	lower := strings.ToLower(namespace)
+	for _, prefix := range h.ReservedNamespacePrefixes {
+		if strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// HostProfile returns the profile that the receiver uses for the given
+// registry hostname, which is the one in HostProfiles if present, or
+// otherwise the result of DefaultHostProfile.
+func (p Parser) HostProfile(host svchost.Hostname) HostProfile {
+	if profile, ok := p.HostProfiles[host]; ok {
+		return profile
+	}
+	return DefaultHostProfile(host)
+}
+
+// checkProviderHostProfile returns an error if the given provider address
+// doesn't conform to the profile of its host.
+func (p Parser) checkProviderHostProfile(addr Provider, str string) error {
+	profile := p.HostProfile(addr.Hostname)
+	if !profile.Supports(ProviderKind) {
+		return &HostPolicyError{Hostname: addr.Hostname, Kind: ProviderKind}
+	}
+	if prefix, reserved := profile.reservedPrefix(addr.Namespace); reserved {
+		return &ParserError{
+			Summary: "Invalid provider namespace",
+			Detail:  fmt.Sprintf("Invalid provider namespace %q in source %q: namespaces starting with %q are reserved on %s.", addr.Namespace, str, prefix, addr.Hostname.ForDisplay()),
+		}
+	}
+	return nil
+}
+
+// checkModuleHostProfile returns an error if the given module address
+// doesn't conform to the profile of its host.
+func (p Parser) checkModuleHostProfile(addr Module) error {
+	host := addr.Package.Host
+	profile := p.HostProfile(host)
+	if !profile.Supports(ModuleRegistryKind) {
+		return &HostPolicyError{Hostname: host, Kind: ModuleRegistryKind}
+	}
+	if prefix, reserved := profile.reservedPrefix(addr.Package.Namespace); reserved {
+		return &ParserError{
+			Summary: "Invalid module namespace",
+			Detail:  fmt.Sprintf("Invalid module namespace %q in source %q: namespaces starting with %q are reserved on %s.", addr.Package.Namespace, addr.ForDisplay(), prefix, host.ForDisplay()),
+		}
+	}
+	return nil
+}
+
+// allowsPunycodeHost returns true if the receiver accepts the given
+// hostname, decoded from punycode, having been written in punycode form.
+func (p Parser) allowsPunycodeHost(host svchost.Hostname) bool {
+	return p.AllowPunycodeHosts || p.HostProfile(host).AllowPunycode
+}
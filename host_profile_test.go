@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestParserHostProfiles(t *testing.T) {
+	idnHost, err := svchost.ForComparison("испытание.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Parser{
+		HostProfiles: map[svchost.Hostname]HostProfile{
+			svchost.Hostname("providers.example.com"): {
+				Families:                  []AddressKind{ProviderKind},
+				ReservedNamespacePrefixes: []string{"Internal-"},
+			},
+			svchost.Hostname("modules.example.com"): {
+				ReservedNamespacePrefixes: []string{"Internal-"},
+			},
+			idnHost: {
+				AllowPunycode: true,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		provider bool
+		wantErr  string
+	}{
+		"providers.example.com/awesomecorp/happycloud": {
+			provider: true,
+		},
+		"providers.example.com/internal-tools/happycloud": {
+			provider: true,
+			wantErr:  `Invalid provider namespace: Invalid provider namespace "internal-tools" in source "providers.example.com/internal-tools/happycloud": namespaces starting with "Internal-" are reserved on providers.example.com.`,
+		},
+		"modules.example.com/internal-tools/network/happycloud": {
+			wantErr: `Invalid module namespace: Invalid module namespace "internal-tools" in source "modules.example.com/internal-tools/network/happycloud": namespaces starting with "Internal-" are reserved on modules.example.com.`,
+		},
+		"providers.example.com/awesomecorp/network/happycloud": {
+			wantErr: `registry hostname "providers.example.com" doesn't serve module registry source addresses`,
+		},
+		"xn--80akhbyknj4f.example.com/awesomecorp/happycloud": {
+			provider: true,
+		},
+		"xn--80akhbyknj4f.example.com/awesomecorp/network/happycloud": {},
+		"xn--e1afmkfd.example.com/awesomecorp/happycloud": {
+			provider: true,
+			wantErr:  `Invalid provider source hostname: Invalid provider source hostname namespace "" in source "xn--e1afmkfd.example.com/awesomecorp/happycloud": hostname label "xn--e1afmkfd" specified in punycode format; service hostnames must be given in unicode"`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			var err error
+			if test.provider {
+				_, err = p.ParseProviderSource(input)
+			} else {
+				_, err = p.ParseModuleSource(input)
+			}
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got := err.Error(); got != test.wantErr {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+			}
+		})
+	}
+
+	_, providerErr := p.ParseProviderSource("providers.example.com/internal-tools/happycloud")
+	_, moduleErr := p.ParseModuleSource("modules.example.com/internal-tools/network/happycloud")
+	for _, err := range []error{providerErr, moduleErr} {
+		var parserErr *ParserError
+		if !errors.As(err, &parserErr) {
+			t.Errorf("wrong error type for reserved namespace: %T", err)
+		}
+	}
+
+	var hostErr *HostPolicyError
+	if _, err := p.ParseModuleSource("providers.example.com/awesomecorp/network/happycloud"); !errors.As(err, &hostErr) || hostErr.Kind != ModuleRegistryKind {
+		t.Errorf("wrong error type: %#v", err)
+	}
+}
+
+func TestDefaultHostProfile(t *testing.T) {
+	if !DefaultHostProfile(DefaultModuleRegistryHost).CaseInsensitiveModules {
+		t.Errorf("public registry is not case-insensitive for modules")
+	}
+	profile := DefaultHostProfile(svchost.Hostname("example.com"))
+	if profile.CaseInsensitiveModules || !profile.Supports(ModuleRegistryKind) || !profile.Supports(ProviderKind) {
+		t.Errorf("wrong default profile for other hosts: %#v", profile)
+	}
+
+	p := Parser{
+		HostProfiles: map[svchost.Hostname]HostProfile{
+			DefaultModuleRegistryHost: {},
+		},
+	}
+	if p.HostProfile(DefaultModuleRegistryHost).CaseInsensitiveModules {
+		t.Errorf("HostProfiles did not override the default profile")
+	}
+}
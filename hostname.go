@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+	"golang.org/x/net/idna"
+)
+
+// maxHostnameLength is the maximum number of characters we'll accept in the
+// hostname portion of an address, excluding any port number.
+//
+// This is the maximum length of a DNS name, so it doesn't exclude any
+// hostname that would otherwise be valid. Checking it before normalization
+// bounds the work done by the IDNA processing, which can be quadratic in the
+// length of each label.
+const maxHostnameLength = 253
+
+// parseHostname is a wrapper around svchost.ForComparison that first
+// rejects hostnames too long to be valid DNS names.
+//
+// It also rejects strings that are not valid UTF-8, which
+// svchost.ForComparison can otherwise accept, producing a Hostname that
+// panics when rendered for display.
+func parseHostname(given string) (svchost.Hostname, error) {
+	if !utf8.ValidString(given) {
+		return svchost.Hostname(""), fmt.Errorf("must be valid UTF-8")
+	}
+	host := given
+	if colonPos := strings.Index(host, ":"); colonPos != -1 {
+		host = host[:colonPos]
+	}
+	if utf8.RuneCountInString(host) > maxHostnameLength {
+		return svchost.Hostname(""), fmt.Errorf("must be no longer than %d characters", maxHostnameLength)
+	}
+	return svchost.ForComparison(given)
+}
+
+// parseHostname is like the package-level parseHostname, but first
+// converts any labels given in punycode form to Unicode if the receiver's
+// AllowPunycodeHosts is set, or if the HostProfile of the resulting
+// hostname allows punycode.
+func (p Parser) parseHostname(given string) (svchost.Hostname, error) {
+	if (!p.AllowPunycodeHosts && len(p.HostProfiles) == 0) || !strings.Contains(strings.ToLower(given), "xn--") {
+		return parseHostname(given)
+	}
+	host, port := given, ""
+	if colonPos := strings.Index(host, ":"); colonPos != -1 {
+		host, port = host[:colonPos], host[colonPos:]
+	}
+	if len(host) > maxHostnameLength {
+		// Too long to be valid, so we'll let parseHostname report that
+		// before doing any IDNA processing.
+		return parseHostname(given)
+	}
+	decoded, err := idna.ToUnicode(host)
+	if err != nil {
+		if !p.AllowPunycodeHosts {
+			return parseHostname(given)
+		}
+		return svchost.Hostname(""), fmt.Errorf("invalid punycode hostname: %s", err)
+	}
+	ret, err := parseHostname(decoded + port)
+	if err != nil || !p.allowsPunycodeHost(ret) {
+		// If the receiver wouldn't accept this hostname in punycode form
+		// then we'll let parseHostname report the problem with the
+		// hostname as given.
+		return parseHostname(given)
+	}
+	p.tracef("decoded punycode hostname %q as %q", host, decoded)
+	return ret, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build goexperiment.jsonv2
+
+package tfaddr
+
+import (
+	"encoding"
+	"encoding/json/jsontext"
+	"fmt"
+)
+
+// The methods in this file implement the streaming interfaces of the
+// experimental encoding/json/v2 package, and so are available only when
+// building with GOEXPERIMENT=jsonv2. When they are available,
+// encoding/json also uses them in preference to MarshalText and
+// UnmarshalText, so they follow exactly the same rules: each address type
+// is represented in JSON as a string containing the result of its String
+// method, the zero value can't be marshaled, an empty string is rejected
+// with ErrEmptyAddress, and null leaves the address unchanged.
+
+// MarshalJSONTo implements json.MarshalerTo from encoding/json/v2.
+func (pt Provider) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return marshalJSONAddress(enc, pt)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom from encoding/json/v2.
+func (pt *Provider) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return unmarshalJSONAddress(dec, pt)
+}
+
+// MarshalJSONTo implements json.MarshalerTo from encoding/json/v2.
+func (s ModulePackage) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return marshalJSONAddress(enc, s)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom from encoding/json/v2.
+func (s *ModulePackage) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return unmarshalJSONAddress(dec, s)
+}
+
+// MarshalJSONTo implements json.MarshalerTo from encoding/json/v2.
+func (s Module) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return marshalJSONAddress(enc, s)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom from encoding/json/v2.
+func (s *Module) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return unmarshalJSONAddress(dec, s)
+}
+
+// marshalJSONAddress writes the text form of the given address to the
+// given encoder as a JSON string.
+func marshalJSONAddress(enc *jsontext.Encoder, addr encoding.TextMarshaler) error {
+	text, err := addr.MarshalText()
+	if err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.String(string(text)))
+}
+
+// unmarshalJSONAddress reads a JSON string from the given decoder and
+// parses it into the given address using its UnmarshalText method, or
+// leaves the address unchanged if the next token is null.
+func unmarshalJSONAddress(dec *jsontext.Decoder, addr encoding.TextUnmarshaler) error {
+	raw, null, err := readJSONAddressString(dec)
+	if err != nil || null {
+		return err
+	}
+	return addr.UnmarshalText([]byte(raw))
+}
+
+// readJSONAddressString reads the next token from the given decoder, which
+// must be either a string or null.
+func readJSONAddressString(dec *jsontext.Decoder) (raw string, null bool, err error) {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return "", false, err
+	}
+	switch tok.Kind() {
+	case 'n':
+		return "", true, nil
+	case '"':
+		return tok.String(), false, nil
+	default:
+		return "", false, fmt.Errorf("address must be given as a string, not %s", tok.Kind())
+	}
+}
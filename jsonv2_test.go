@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build goexperiment.jsonv2
+
+package tfaddr
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONv2RoundTrip(t *testing.T) {
+	type document struct {
+		Provider Provider      `json:"provider"`
+		Package  ModulePackage `json:"package"`
+		Module   Module        `json:"module"`
+	}
+
+	tests := map[string]struct {
+		doc  document
+		want string
+	}{
+		"populated": {
+			doc: document{
+				Provider: MustParseProviderSource("hashicorp/aws"),
+				Package:  MustParseModuleSource("example.com/hashicorp/consul/aws").Package,
+				Module:   MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			},
+			want: `{"provider":"registry.terraform.io/hashicorp/aws","package":"example.com/hashicorp/consul/aws","module":"registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(test.doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(got) != test.want {
+				t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, test.want)
+			}
+
+			var back document
+			if err := json.Unmarshal(got, &back); err != nil {
+				t.Fatalf("unexpected error decoding: %s", err)
+			}
+			if diff := cmp.Diff(test.doc, back); diff != "" {
+				t.Errorf("wrong result decoding\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONv2UnmarshalErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		into  any
+	}{
+		"provider not a string": {
+			input: `123`,
+			into:  new(Provider),
+		},
+		"provider invalid": {
+			input: `"foo/bar/baz/boop"`,
+			into:  new(Provider),
+		},
+		"package with subdir": {
+			input: `"hashicorp/consul/aws//modules/consul-cluster"`,
+			into:  new(ModulePackage),
+		},
+		"module invalid": {
+			input: `"github.com/hashicorp/consul/aws"`,
+			into:  new(Module),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(test.input), test.into); err == nil {
+				t.Fatalf("unexpected success")
+			}
+		})
+	}
+}
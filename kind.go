@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+// AddressKind identifies which family of address a value belongs to, so
+// that generic code can distinguish them without type assertions.
+//
+// Values greater than ModuleRemoteKind identify address families
+// registered using RegisterAddressFamily.
+type AddressKind int
+
+const (
+	// UnknownAddressKind is the zero value of AddressKind, which doesn't
+	// represent any address family.
+	UnknownAddressKind AddressKind = iota
+
+	// ProviderKind is the kind of a Provider.
+	ProviderKind
+
+	// ModulePackageKind is the kind of a ModulePackage.
+	ModulePackageKind
+
+	// ModuleRegistryKind is the kind of a Module, which is a module
+	// registry source address.
+	ModuleRegistryKind
+
+	// ModuleRemoteKind is the kind of a ModuleSourceRemote.
+	ModuleRemoteKind
+)
+
+func (k AddressKind) String() string {
+	switch k {
+	case ProviderKind:
+		return "provider"
+	case ModulePackageKind:
+		return "module package"
+	case ModuleRegistryKind:
+		return "module registry source"
+	case ModuleRemoteKind:
+		return "remote module source"
+	}
+	if f, ok := registeredFamily(k); ok {
+		return f.Name
+	}
+	return "unknown"
+}
+
+// Kind returns ProviderKind.
+func (pt Provider) Kind() AddressKind {
+	return ProviderKind
+}
+
+// Kind returns ModulePackageKind.
+func (s ModulePackage) Kind() AddressKind {
+	return ModulePackageKind
+}
+
+// Kind returns ModuleRegistryKind.
+func (s Module) Kind() AddressKind {
+	return ModuleRegistryKind
+}
+
+// Kind returns ModuleRemoteKind.
+func (s ModuleSourceRemote) Kind() AddressKind {
+	return ModuleRemoteKind
+}
@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		addr interface{ Kind() AddressKind }
+		want AddressKind
+		str  string
+	}{
+		{MustParseProviderSource("hashicorp/aws"), ProviderKind, "provider"},
+		{MustParseModuleSource("hashicorp/consul/aws").Package, ModulePackageKind, "module package"},
+		{MustParseModuleSource("hashicorp/consul/aws"), ModuleRegistryKind, "module registry source"},
+	}
+
+	for _, test := range tests {
+		got := test.addr.Kind()
+		if got != test.want {
+			t.Errorf("wrong kind for %T: %s", test.addr, got)
+		}
+		if got.String() != test.str {
+			t.Errorf("wrong string for %T: %q", test.addr, got.String())
+		}
+	}
+
+	if got := AddressKind(0).String(); got != "unknown" {
+		t.Errorf("wrong string for zero value: %q", got)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseProviderSourceLenient is like ParseProviderSource, except that it
+// always returns a best-effort result, even for invalid input, along with
+// all of the problems it found.
+//
+// This is intended for tools such as language servers, which need to
+// offer completions and other information while the user is still typing
+// an address. For example, "hashicorp/" produces a result with the
+// namespace set, along with an error for the missing type.
+//
+// Each segment of the result that is invalid is left empty and reported
+// as a *SegmentError. Other errors describe problems with the address as
+// a whole. The result is valid if and only if there are no errors.
+func ParseProviderSourceLenient(str string) (Provider, []error) {
+	return Parser{}.ParseProviderSourceLenient(str)
+}
+
+// ParseProviderSourceLenient is like the package-level function of the
+// same name, but additionally applies the rules configured in the receiver.
+func (p Parser) ParseProviderSourceLenient(str string) (Provider, []error) {
+	if ret, err := p.ParseProviderSource(str); err == nil {
+		return ret, nil
+	} else if !isSegmentProblem(err) {
+		return ret, []error{err}
+	}
+
+	var ret Provider
+	var errs []error
+	parts := strings.Split(str, "/")
+	if len(parts) > 3 {
+		errs = append(errs, fmt.Errorf(`a provider source address must have at most three slash-separated components, in the format "[hostname/][namespace/]name"`))
+		parts = parts[len(parts)-3:]
+	}
+
+	ret.Hostname = p.defaultProviderHost()
+	if len(parts) == 3 {
+		host, err := p.parseHostname(parts[0])
+		if err != nil {
+			errs = append(errs, &SegmentError{Segment: "hostname", Value: parts[0], Err: err})
+		}
+		ret.Hostname = host
+	}
+
+	switch {
+	case len(parts) == 1:
+		ret.Namespace = UnknownProviderNamespace
+	case parts[len(parts)-2] == LegacyProviderNamespace:
+		ret.Namespace = LegacyProviderNamespace
+	default:
+		given := parts[len(parts)-2]
+		namespace, err := parseLenientProviderPart(given)
+		if err != nil {
+			errs = append(errs, &SegmentError{Segment: "namespace", Value: given, Err: err})
+		}
+		ret.Namespace = namespace
+	}
+
+	given := parts[len(parts)-1]
+	typeName, err := parseLenientProviderPart(given)
+	if err != nil {
+		errs = append(errs, &SegmentError{Segment: "type", Value: given, Err: err})
+	}
+	ret.Type = typeName
+
+	if len(errs) == 0 {
+		// The individual segments are valid, so the problem must be with
+		// how they are combined. The strict parser can describe that.
+		_, err := p.ParseProviderSource(str)
+		return ret, []error{err}
+	}
+	for i, err := range errs {
+		errs[i] = p.localize(err)
+	}
+	return ret, errs
+}
+
+func parseLenientProviderPart(given string) (string, error) {
+	if given == "" {
+		return "", fmt.Errorf("must be set")
+	}
+	return ParseProviderPart(given)
+}
+
+// ParseModuleSourceLenient is like ParseModuleSource, except that it always
+// returns a best-effort result, even for invalid input, along with all of
+// the problems it found.
+//
+// This is intended for tools such as language servers, which need to
+// offer completions and other information while the user is still typing
+// an address. For example, "hashicorp/consul/" produces a result with the
+// namespace and name set, along with an error for the missing target
+// system.
+//
+// Each segment of the result that is invalid is left empty and reported
+// as a *SegmentError. Other errors describe problems with the address as
+// a whole. The result is valid if and only if there are no errors, unless
+// the parser's DetectSwappedSegments option is set.
+func ParseModuleSourceLenient(raw string) (Module, []error) {
+	return Parser{}.ParseModuleSourceLenient(raw)
+}
+
+// ParseModuleSourceLenient is like the package-level function of the same
+// name, but additionally applies the rules configured in the receiver.
+func (p Parser) ParseModuleSourceLenient(raw string) (Module, []error) {
+	if ret, err := p.ParseModuleSource(raw); err == nil {
+		if err := p.swappedSegments(raw); err != nil {
+			return ret, []error{p.localize(err)}
+		}
+		return ret, nil
+	} else if !isSegmentProblem(err) {
+		return ret, []error{err}
+	}
+
+	var ret Module
+	var errs []error
+	pkgRaw, givenSubdir := sourceDirSubdir(raw)
+	ret.Subdir = normalizeSubdir(givenSubdir)
+	if ret.Subdir == ".." || strings.HasPrefix(ret.Subdir, "../") {
+		errs = append(errs, &SegmentError{Segment: "subdirectory", Value: givenSubdir, Err: fmt.Errorf("leads outside of the module package")})
+		ret.Subdir = ""
+	}
+
+	parts := strings.Split(pkgRaw, "/")
+	if len(parts) > 4 {
+		errs = append(errs, fmt.Errorf("a module registry source address must have either three or four slash-separated components"))
+		parts = parts[len(parts)-4:]
+	}
+
+	ret.Package.Host = p.defaultModuleHost()
+	if len(parts) == 4 {
+		// The builder applies the same hostname rules as the parser, but
+		// reports problems as a *SegmentError.
+		builder := NewModulePackageBuilder().Host(parts[0])
+		if host, err := p.parseHostname(parts[0]); err == nil && p.allowsSingleLabelModuleHost(host) {
+			ret.Package.Host = host
+		} else if builder.err != nil {
+			errs = append(errs, builder.err)
+			ret.Package.Host = ""
+		} else {
+			ret.Package.Host = builder.addr.Host
+		}
+		parts = parts[1:]
+	}
+
+	segments := []struct {
+		name   string
+		field  *string
+		parse  func(string) (string, error)
+		absent bool
+	}{
+		{"namespace", &ret.Package.Namespace, parseModuleRegistryName, len(parts) < 1},
+		{"module name", &ret.Package.Name, parseModuleRegistryName, len(parts) < 2},
+		{"target system", &ret.Package.TargetSystem, parseModuleRegistryTargetSystem, len(parts) < 3},
+	}
+	for i, segment := range segments {
+		given := ""
+		if !segment.absent {
+			given = parts[i]
+		}
+		if given == "" {
+			errs = append(errs, &SegmentError{Segment: segment.name, Value: given, Err: fmt.Errorf("must be set")})
+			continue
+		}
+		value, err := segment.parse(given)
+		if err != nil {
+			errs = append(errs, &SegmentError{Segment: segment.name, Value: given, Err: err})
+			continue
+		}
+		*segment.field = value
+	}
+
+	if len(errs) == 0 {
+		// The individual segments are valid, so the problem must be with
+		// how they are combined. The strict parser can describe that.
+		_, err := p.ParseModuleSource(raw)
+		return ret, []error{err}
+	}
+	if err := p.swappedSegments(raw); err != nil {
+		errs = append(errs, err)
+	}
+	for i, err := range errs {
+		errs[i] = p.localize(err)
+	}
+	return ret, errs
+}
+
+// swappedSegments returns a *SwappedSegmentsError if the parser's
+// DetectSwappedSegments option is set and the package segments of the given
+// module source address can be reordered into a valid address by moving a
+// known target system to the end, when the target system as written is not
+// known.
+func (p Parser) swappedSegments(raw string) error {
+	if !p.DetectSwappedSegments {
+		return nil
+	}
+	pkgRaw, subdir := sourceDirSubdir(raw)
+	parts := strings.Split(pkgRaw, "/")
+	prefix := ""
+	switch len(parts) {
+	case 3:
+	case 4:
+		prefix = parts[0] + "/"
+		parts = parts[1:]
+	default:
+		return nil
+	}
+	if IsKnownTargetSystem(parts[2]) {
+		return nil
+	}
+
+	// Moving a known target system to the end while keeping the other two
+	// segments in their given order covers the usual mistakes, such as
+	// "aws/terraform-aws-modules/vpc" and "terraform-aws-modules/aws/vpc".
+	for i := 0; i < 2; i++ {
+		system := parts[i]
+		if !IsKnownTargetSystem(system) {
+			continue
+		}
+		namespace, name := parts[1-i], parts[2]
+		if _, err := parseModuleRegistryName(namespace); err != nil {
+			continue
+		}
+		if _, err := parseModuleRegistryName(name); err != nil {
+			continue
+		}
+		suggestion := prefix + namespace + "/" + name + "/" + system
+		if subdir != "" {
+			suggestion += "//" + subdir
+		}
+		return &SwappedSegmentsError{Source: raw, Suggestion: suggestion}
+	}
+	return nil
+}
+
+// isSegmentProblem returns false if the given error from a strict parser
+// is about the source string as a whole, rather than about its segments,
+// in which case looking at the individual segments won't help.
+func isSegmentProblem(err error) bool {
+	var policyErr *HostPolicyError
+	var interpErr *InterpolationError
+	return !errors.As(err, &policyErr) && !errors.As(err, &interpErr)
+}
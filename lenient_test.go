@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProviderSourceLenient(t *testing.T) {
+	tests := map[string]struct {
+		want     Provider
+		wantErrs []string
+	}{
+		"hashicorp/aws": {
+			want: MustParseProviderSource("hashicorp/aws"),
+		},
+		"hashicorp/": {
+			want:     Provider{Hostname: DefaultProviderRegistryHost, Namespace: "hashicorp"},
+			wantErrs: []string{`invalid type "": must be set`},
+		},
+		"example.com/": {
+			want:     Provider{Hostname: DefaultProviderRegistryHost},
+			wantErrs: []string{`invalid namespace "example.com": dots are not allowed`, `invalid type "": must be set`},
+		},
+		"example.com/HashiCorp/": {
+			want:     Provider{Hostname: "example.com", Namespace: "hashicorp"},
+			wantErrs: []string{`invalid type "": must be set`},
+		},
+		"bad!host/hashicorp/aws": {
+			want:     Provider{Namespace: "hashicorp", Type: "aws"},
+			wantErrs: []string{`invalid hostname "bad!host": idna: disallowed rune U+0021`},
+		},
+		"a/b/c/d": {
+			want:     Provider{Hostname: "b", Namespace: "c", Type: "d"},
+			wantErrs: []string{`a provider source address must have at most three slash-separated components, in the format "[hostname/][namespace/]name"`},
+		},
+		"example.com/-/aws": {
+			want:     Provider{Hostname: "example.com", Namespace: LegacyProviderNamespace, Type: "aws"},
+			wantErrs: []string{`Invalid provider namespace: The legacy provider namespace "-" can be used only with hostname registry.terraform.io.`},
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, errs := ParseProviderSourceLenient(input)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantErrs, errorStrings(errs)); diff != "" {
+				t.Errorf("wrong errors\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseModuleSourceLenient(t *testing.T) {
+	tests := map[string]struct {
+		want     Module
+		wantErrs []string
+	}{
+		"hashicorp/consul/aws//modules/foo": {
+			want: MustParseModuleSource("hashicorp/consul/aws//modules/foo"),
+		},
+		"hashicorp/consul/": {
+			want: Module{
+				Package: ModulePackage{Host: DefaultModuleRegistryHost, Namespace: "hashicorp", Name: "consul"},
+			},
+			wantErrs: []string{`invalid target system "": must be set`},
+		},
+		"hashicorp": {
+			want: Module{
+				Package: ModulePackage{Host: DefaultModuleRegistryHost, Namespace: "hashicorp"},
+			},
+			wantErrs: []string{`invalid module name "": must be set`, `invalid target system "": must be set`},
+		},
+		"github.com/hashicorp/consul/aws//modules/foo": {
+			want: Module{
+				Package: ModulePackage{Namespace: "hashicorp", Name: "consul", TargetSystem: "aws"},
+				Subdir:  "modules/foo",
+			},
+			wantErrs: []string{`invalid hostname "github.com": reserved for installing directly from version control repositories`},
+		},
+		"hashicorp/consul_/aws//../foo": {
+			want: Module{
+				Package: ModulePackage{Host: DefaultModuleRegistryHost, Namespace: "hashicorp", TargetSystem: "aws"},
+			},
+			wantErrs: []string{
+				`invalid subdirectory "../foo": leads outside of the module package`,
+				`invalid module name "consul_": must be between one and 64 characters, including ASCII letters, digits, dashes, and underscores, where dashes and underscores may not be the prefix or suffix`,
+			},
+		},
+		"hashicorp/consul/aws?ref=v1": {
+			want: Module{
+				Package: ModulePackage{Host: DefaultModuleRegistryHost, Namespace: "hashicorp", Name: "consul"},
+			},
+			wantErrs: []string{`invalid target system "aws?ref=v1": must be between one and 64 ASCII letters or digits`},
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, errs := ParseModuleSourceLenient(input)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantErrs, errorStrings(errs)); diff != "" {
+				t.Errorf("wrong errors\n%s", diff)
+			}
+		})
+	}
+}
+
+func errorStrings(errs []error) []string {
+	var ret []string
+	for _, err := range errs {
+		ret = append(ret, err.Error())
+	}
+	return ret
+}
+
+func TestParseModuleSourceLenientSwappedSegments(t *testing.T) {
+	tests := map[string]struct {
+		wantSuggestion string
+		wantErrCount   int
+	}{
+		"hashicorp/consul/aws":      {},
+		"hashicorp/network/example": {
+			// The target system isn't known, but no other order makes it so.
+		},
+		"aws/terraform-aws-modules/vpc": {
+			wantSuggestion: "terraform-aws-modules/vpc/aws",
+			wantErrCount:   1,
+		},
+		"example.com/aws/terraform-aws-modules/vpc//modules/foo": {
+			wantSuggestion: "example.com/terraform-aws-modules/vpc/aws//modules/foo",
+			wantErrCount:   1,
+		},
+		"vpc/aws/terraform-aws-modules": {
+			// The strict parser rejects this because the target system can't
+			// contain dashes, so the suggestion accompanies a segment error.
+			wantSuggestion: "vpc/terraform-aws-modules/aws",
+			wantErrCount:   2,
+		},
+		"terraform-aws-modules/aws/vpc": {
+			wantSuggestion: "terraform-aws-modules/vpc/aws",
+			wantErrCount:   1,
+		},
+	}
+
+	p := Parser{DetectSwappedSegments: true}
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, errs := p.ParseModuleSourceLenient(input)
+			if got, want := len(errs), test.wantErrCount; got != want {
+				t.Fatalf("wrong number of errors %d; want %d\n%s", got, want, errorStrings(errs))
+			}
+			var gotSuggestion string
+			for _, err := range errs {
+				var swapErr *SwappedSegmentsError
+				if errors.As(err, &swapErr) {
+					gotSuggestion = swapErr.Suggestion
+				}
+			}
+			if gotSuggestion != test.wantSuggestion {
+				t.Errorf("wrong suggestion %q; want %q", gotSuggestion, test.wantSuggestion)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, errs := ParseModuleSourceLenient("aws/terraform-aws-modules/vpc")
+		if len(errs) != 0 {
+			t.Errorf("unexpected errors: %s", errorStrings(errs))
+		}
+	})
+}
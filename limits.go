@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits describes additional restrictions on the segments of provider and
+// module addresses, beyond the syntax rules that Terraform itself uses,
+// for registries that have stricter rules of their own.
+//
+// Limits can only make parsing stricter: an address that is invalid under
+// the usual rules remains invalid regardless of the limits. The zero value
+// applies no additional restrictions.
+type Limits struct {
+	// MaxNamespaceLength, if greater than zero, is the maximum length in
+	// characters of the namespace of a provider or module address.
+	MaxNamespaceLength int
+
+	// MaxNameLength, if greater than zero, is the maximum length in
+	// characters of the type of a provider address or the name of a
+	// module address.
+	MaxNameLength int
+
+	// MaxSubdirDepth, if greater than zero, is the maximum number of
+	// slash-separated segments in the subdirectory of a module address.
+	MaxSubdirDepth int
+
+	// MaxSubdirLength, if greater than zero, is the maximum length in bytes
+	// of the subdirectory of a module address after normalization.
+	//
+	// This is measured in bytes rather than characters because it's
+	// intended to protect filesystem operations, whose limits on path
+	// length are usually expressed in bytes.
+	MaxSubdirLength int
+
+	// AllowedRune, if set, is called for each character in the namespace,
+	// name, and target system of an address after normalization, and the
+	// address is rejected if it returns false for any of them.
+	//
+	// The legacy provider namespace "-" is not checked.
+	AllowedRune func(r rune) bool
+}
+
+// checkSegment returns an error if the given normalized segment value
+// doesn't conform to the receiver's character policy or, if maxLength is
+// greater than zero, is longer than maxLength characters.
+func (l Limits) checkSegment(value string, maxLength int) error {
+	if maxLength > 0 && utf8.RuneCountInString(value) > maxLength {
+		return fmt.Errorf("must be no longer than %d characters", maxLength)
+	}
+	if l.AllowedRune != nil {
+		for _, r := range value {
+			if !l.AllowedRune(r) {
+				return fmt.Errorf("must not contain %q", r)
+			}
+		}
+	}
+	return nil
+}
+
+// checkSubdir returns a *SubdirLimitError if the given normalized module
+// subdirectory is deeper or longer than permitted by the receiver.
+func (l Limits) checkSubdir(subdir string) error {
+	if subdir == "" {
+		return nil
+	}
+	if l.MaxSubdirDepth > 0 {
+		if depth := strings.Count(subdir, "/") + 1; depth > l.MaxSubdirDepth {
+			return &SubdirLimitError{Subdir: subdir, MaxDepth: l.MaxSubdirDepth}
+		}
+	}
+	if l.MaxSubdirLength > 0 && len(subdir) > l.MaxSubdirLength {
+		return &SubdirLimitError{Subdir: subdir, MaxLength: l.MaxSubdirLength}
+	}
+	return nil
+}
+
+// checkProviderLimits returns an error if the given provider address
+// doesn't conform to the receiver's Limits.
+func (p Parser) checkProviderLimits(addr Provider, str string) error {
+	// The legacy and unknown namespaces are placeholders that the user
+	// didn't write, so they're exempt.
+	if addr.Namespace != LegacyProviderNamespace && addr.Namespace != UnknownProviderNamespace {
+		if err := p.Limits.checkSegment(addr.Namespace, p.Limits.MaxNamespaceLength); err != nil {
+			return &ParserError{
+				Summary: "Invalid provider namespace",
+				Detail:  fmt.Sprintf("Invalid provider namespace %q in source %q: %s.", addr.Namespace, str, err),
+			}
+		}
+	}
+	if err := p.Limits.checkSegment(addr.Type, p.Limits.MaxNameLength); err != nil {
+		return &ParserError{
+			Summary: "Invalid provider type",
+			Detail:  fmt.Sprintf("Invalid provider type %q in source %q: %s.", addr.Type, str, err),
+		}
+	}
+	return nil
+}
+
+// checkModuleLimits returns an error if the given module address doesn't
+// conform to the receiver's Limits.
+func (p Parser) checkModuleLimits(addr Module) error {
+	pkg := addr.Package
+	if err := p.Limits.checkSegment(pkg.Namespace, p.Limits.MaxNamespaceLength); err != nil {
+		return fmt.Errorf("invalid namespace %q: %s", pkg.Namespace, err)
+	}
+	if err := p.Limits.checkSegment(pkg.Name, p.Limits.MaxNameLength); err != nil {
+		return fmt.Errorf("invalid module name %q: %s", pkg.Name, err)
+	}
+	if err := p.Limits.checkSegment(pkg.TargetSystem, 0); err != nil {
+		return fmt.Errorf("invalid target system %q: %s", pkg.TargetSystem, err)
+	}
+	if err := p.Limits.checkSubdir(addr.Subdir); err != nil {
+		return err
+	}
+	return nil
+}
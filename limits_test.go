@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParserLimits(t *testing.T) {
+	asciiOnly := func(r rune) bool { return r < 0x80 }
+	noDashes := func(r rune) bool { return r != '-' }
+
+	tests := map[string]struct {
+		limits          Limits
+		provider        string
+		module          string
+		wantProviderErr string
+		wantModuleErr   string
+	}{
+		"zero value": {
+			provider: "hashicorp/a-very-long-provider-type",
+			module:   "hashicorp/a-very-long-module-name/aws//a/b/c/d",
+		},
+		"within limits": {
+			limits: Limits{
+				MaxNamespaceLength: 9,
+				MaxNameLength:      3,
+				MaxSubdirDepth:     2,
+				AllowedRune:        asciiOnly,
+			},
+			provider: "hashicorp/aws",
+			module:   "hashicorp/vpc/aws//modules/vpc",
+		},
+		"namespace too long": {
+			limits:          Limits{MaxNamespaceLength: 8},
+			provider:        "hashicorp/aws",
+			module:          "hashicorp/vpc/aws",
+			wantProviderErr: `Invalid provider namespace: Invalid provider namespace "hashicorp" in source "hashicorp/aws": must be no longer than 8 characters.`,
+			wantModuleErr:   `invalid namespace "hashicorp": must be no longer than 8 characters`,
+		},
+		"name too long": {
+			limits:          Limits{MaxNameLength: 2},
+			provider:        "hashicorp/aws",
+			module:          "hashicorp/vpc/aws",
+			wantProviderErr: `Invalid provider type: Invalid provider type "aws" in source "hashicorp/aws": must be no longer than 2 characters.`,
+			wantModuleErr:   `invalid module name "vpc": must be no longer than 2 characters`,
+		},
+		"length counts characters": {
+			limits:   Limits{MaxNamespaceLength: 9},
+			provider: "испытание/aws",
+		},
+		"subdir too deep": {
+			limits:        Limits{MaxSubdirDepth: 2},
+			module:        "hashicorp/vpc/aws//modules/vpc/examples",
+			wantModuleErr: `invalid subdirectory path "modules/vpc/examples": must have no more than 2 slash-separated segments`,
+		},
+		"subdir depth after normalization": {
+			limits: Limits{MaxSubdirDepth: 2},
+			module: "hashicorp/vpc/aws//modules/./vpc/",
+		},
+		"subdir too long": {
+			limits:        Limits{MaxSubdirLength: 10},
+			module:        "hashicorp/vpc/aws//modules/vpc",
+			wantModuleErr: `invalid subdirectory path "modules/vpc": must be no longer than 10 bytes`,
+		},
+		"subdir length counts bytes": {
+			limits:        Limits{MaxSubdirLength: 9},
+			module:        "hashicorp/vpc/aws//испытание",
+			wantModuleErr: `invalid subdirectory path "испытание": must be no longer than 9 bytes`,
+		},
+		"subdir length after normalization": {
+			limits: Limits{MaxSubdirLength: 11},
+			module: "hashicorp/vpc/aws//modules/./vpc/",
+		},
+		"disallowed character": {
+			limits:          Limits{AllowedRune: asciiOnly},
+			provider:        "испытание/aws",
+			wantProviderErr: `Invalid provider namespace: Invalid provider namespace "испытание" in source "испытание/aws": must not contain 'и'.`,
+		},
+		"disallowed character in target system": {
+			limits:        Limits{AllowedRune: func(r rune) bool { return r != 'z' }},
+			module:        "hashicorp/vpc/azure",
+			wantModuleErr: `invalid target system "azure": must not contain 'z'`,
+		},
+		"legacy namespace not checked": {
+			limits:   Limits{AllowedRune: noDashes},
+			provider: "-/aws",
+		},
+		"unknown namespace not checked": {
+			limits:   Limits{AllowedRune: func(r rune) bool { return r >= 'a' && r <= 'z' }},
+			provider: "aws",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := Parser{Limits: test.limits}
+			if test.provider != "" {
+				_, err := parser.ParseProviderSource(test.provider)
+				checkLimitsErr(t, "provider", err, test.wantProviderErr)
+			}
+			if test.module != "" {
+				_, err := parser.ParseModuleSource(test.module)
+				checkLimitsErr(t, "module", err, test.wantModuleErr)
+			}
+		})
+	}
+}
+
+func TestParserLimitsSubdirError(t *testing.T) {
+	parser := Parser{Limits: Limits{MaxSubdirDepth: 1, MaxSubdirLength: 4}}
+
+	_, err := parser.ParseModuleSource("hashicorp/vpc/aws//modules/vpc")
+	var limitErr *SubdirLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+	want := &SubdirLimitError{Subdir: "modules/vpc", MaxDepth: 1}
+	if diff := cmp.Diff(want, limitErr); diff != "" {
+		t.Errorf("wrong error\n%s", diff)
+	}
+
+	_, err = parser.ParseModuleSource("hashicorp/vpc/aws//modules")
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("wrong error type %T: %s", err, err)
+	}
+	want = &SubdirLimitError{Subdir: "modules", MaxLength: 4}
+	if diff := cmp.Diff(want, limitErr); diff != "" {
+		t.Errorf("wrong error\n%s", diff)
+	}
+}
+
+func checkLimitsErr(t *testing.T, kind string, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("unexpected %s error: %s", kind, err)
+	case want != "" && err == nil:
+		t.Errorf("unexpected %s success\nwant error: %s", kind, want)
+	case want != "" && err.Error() != want:
+		t.Errorf("wrong %s error\ngot:  %s\nwant: %s", kind, err, want)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// WarningCode is a stable identifier for a kind of Warning, which callers
+// can use to decide how to handle a warning without interpreting its
+// message.
+type WarningCode string
+
+const (
+	// WarningUppercase indicates that a source string contains uppercase
+	// letters in a part of the address that is case-insensitive.
+	WarningUppercase WarningCode = "uppercase"
+
+	// WarningCaseSensitive indicates that a module source string contains
+	// uppercase letters in its namespace or name, which some registries
+	// treat case-sensitively.
+	WarningCaseSensitive WarningCode = "case-sensitive"
+
+	// WarningExplicitDefaultHost indicates that a source string explicitly
+	// includes the default registry hostname, which is usually omitted.
+	WarningExplicitDefaultHost WarningCode = "explicit-default-host"
+
+	// WarningPunycode indicates that a segment of a source string looks
+	// like an internationalized name encoded as punycode, which is probably
+	// a mistake.
+	WarningPunycode WarningCode = "punycode"
+
+	// WarningRedundantPathSegments indicates that a module source string has
+	// a subdirectory path with redundant segments, such as "./", that are
+	// removed during normalization.
+	WarningRedundantPathSegments WarningCode = "redundant-path-segments"
+)
+
+// Warning describes a problem with a valid address or source string, which
+// doesn't prevent it from being parsed but which a formatter or linter may
+// wish to report or fix.
+//
+// Every function in this package that reports non-blocking problems does so
+// using Warning, separately from any error, so that callers can handle
+// warnings from all of them in the same way.
+type Warning struct {
+	Code    WarningCode
+	Message string
+
+	// Span is the part of the source string that the warning is about, or
+	// the whole source string if the warning is about the address as a
+	// whole. Span is the zero value for warnings that aren't about a
+	// source string, such as those from LintProviderMigration.
+	Span Span
+
+	// Suggestion, if non-empty, is a replacement for the whole source string
+	// that is equivalent to the original but resolves this warning, along
+	// with any other warnings for the same source string that also have a
+	// suggestion.
+	//
+	// Suggestion is empty if the warning can't be resolved automatically
+	// without potentially changing the meaning of the address.
+	Suggestion string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// LintProviderSource returns warnings about stylistic problems with the
+// given provider source string, such as unnecessary uppercase letters or
+// an explicit default hostname.
+//
+// LintProviderSource returns no warnings for an invalid source string; use
+// ParseProviderSource to find out why a source string is invalid.
+func LintProviderSource(str string) []Warning {
+	addr, err := ParseProviderSource(str)
+	if err != nil {
+		return nil
+	}
+	return Parser{}.lintProviderSource(str, addr)
+}
+
+// lintProviderSource returns the warnings for LintProviderSource, given
+// the address that the source string was parsed to by the receiver.
+func (p Parser) lintProviderSource(str string, addr Provider) []Warning {
+	defaultHost := p.defaultProviderHost()
+	suggestion := p.ProviderShortestForm(addr)
+	spans := providerSourceSpans(str)
+
+	var warnings []Warning
+	if strings.IndexFunc(str, unicode.IsUpper) != -1 {
+		warnings = append(warnings, Warning{
+			Code:       WarningUppercase,
+			Message:    fmt.Sprintf("provider source %q contains uppercase letters, but provider source addresses are case-insensitive and conventionally written in lowercase", str),
+			Span:       Span{0, len(str)},
+			Suggestion: suggestion,
+		})
+	}
+	if parts := strings.Split(str, "/"); len(parts) == 3 && addr.Hostname == defaultHost {
+		warnings = append(warnings, Warning{
+			Code:       WarningExplicitDefaultHost,
+			Message:    fmt.Sprintf("provider source %q explicitly includes the default hostname %s, which can be omitted", str, defaultHost.ForDisplay()),
+			Span:       spans.Hostname,
+			Suggestion: suggestion,
+		})
+	}
+	return warnings
+}
+
+// LintModuleSource returns warnings about stylistic problems with the given
+// module registry source string, such as an explicit default hostname or
+// redundant segments in the subdirectory path.
+//
+// LintModuleSource returns no warnings for an invalid source string; use
+// ParseModuleSource to find out why a source string is invalid.
+func LintModuleSource(raw string) []Warning {
+	addr, err := ParseModuleSource(raw)
+	if err != nil {
+		return nil
+	}
+	return Parser{}.lintModuleSource(raw, addr)
+}
+
+// lintModuleSource returns the warnings for LintModuleSource, given the
+// address that the source string was parsed to by the receiver.
+func (p Parser) lintModuleSource(raw string, addr Module) []Warning {
+	defaultHost := p.defaultModuleHost()
+	suggestion := p.ModuleShortestForm(addr)
+	spans := moduleSourceSpans(raw)
+
+	var warnings []Warning
+	pkgRaw, givenSubdir := sourceDirSubdir(raw)
+	parts := strings.Split(pkgRaw, "/")
+	if len(parts) == 4 {
+		if strings.IndexFunc(parts[0], unicode.IsUpper) != -1 {
+			warnings = append(warnings, Warning{
+				Code:       WarningUppercase,
+				Message:    fmt.Sprintf("module source %q has uppercase letters in its hostname, but hostnames are case-insensitive and conventionally written in lowercase", raw),
+				Span:       spans.Hostname,
+				Suggestion: suggestion,
+			})
+		}
+		if addr.Package.Host == defaultHost {
+			warnings = append(warnings, Warning{
+				Code:       WarningExplicitDefaultHost,
+				Message:    fmt.Sprintf("module source %q explicitly includes the default hostname %s, which can be omitted", raw, defaultHost.ForDisplay()),
+				Span:       spans.Hostname,
+				Suggestion: suggestion,
+			})
+		}
+	}
+	for _, segment := range []struct {
+		name, value string
+		span        Span
+	}{
+		{"namespace", addr.Package.Namespace, spans.Namespace},
+		{"module name", addr.Package.Name, spans.Name},
+	} {
+		if strings.IndexFunc(segment.value, unicode.IsUpper) != -1 {
+			warnings = append(warnings, Warning{
+				Code:    WarningCaseSensitive,
+				Message: fmt.Sprintf("module source %q has uppercase letters in its %s %q, which some registries match case-sensitively, so it can't be changed automatically", raw, segment.name, segment.value),
+				Span:    segment.span,
+			})
+		}
+		if strings.HasPrefix(strings.ToLower(segment.value), "xn--") {
+			warnings = append(warnings, Warning{
+				Code:    WarningPunycode,
+				Message: fmt.Sprintf("module source %q has a %s %q that looks like a punycode-encoded internationalized name, which is probably a mistake", raw, segment.name, segment.value),
+				Span:    segment.span,
+			})
+		}
+	}
+	if givenSubdir != addr.Subdir {
+		warnings = append(warnings, Warning{
+			Code:       WarningRedundantPathSegments,
+			Message:    fmt.Sprintf("module source %q has subdirectory path %q, which can be written more simply as %q", raw, givenSubdir, addr.Subdir),
+			Span:       spans.Subdir,
+			Suggestion: suggestion,
+		})
+	}
+	return warnings
+}
+
+// ParseProviderSourceWithWarnings is like ParseProviderSource but also
+// returns the warnings that LintProviderSource would return for a valid
+// source string.
+func ParseProviderSourceWithWarnings(str string) (Provider, []Warning, error) {
+	return Parser{}.ParseProviderSourceWithWarnings(str)
+}
+
+// ParseProviderSourceWithWarnings is like the package-level function of
+// the same name, but applies the rules configured in the receiver.
+func (p Parser) ParseProviderSourceWithWarnings(str string) (Provider, []Warning, error) {
+	addr, err := p.ParseProviderSource(str)
+	if err != nil {
+		return Provider{}, nil, err
+	}
+	return addr, p.lintProviderSource(str, addr), nil
+}
+
+// ParseModuleSourceWithWarnings is like ParseModuleSource but also returns
+// the warnings that LintModuleSource and ValidateTargetSystemKnown would
+// return for a valid source string.
+func ParseModuleSourceWithWarnings(raw string) (Module, []Warning, error) {
+	return Parser{}.ParseModuleSourceWithWarnings(raw)
+}
+
+// ParseModuleSourceWithWarnings is like the package-level function of the
+// same name, but applies the rules configured in the receiver.
+func (p Parser) ParseModuleSourceWithWarnings(raw string) (Module, []Warning, error) {
+	addr, err := p.ParseModuleSource(raw)
+	if err != nil {
+		return Module{}, nil, err
+	}
+	warnings := p.lintModuleSource(raw, addr)
+	if targetWarnings := ValidateTargetSystemKnown(addr.Package); len(targetWarnings) != 0 {
+		spans := moduleSourceSpans(raw)
+		for _, w := range targetWarnings {
+			w.Span = spans.TargetSystem
+			warnings = append(warnings, w)
+		}
+	}
+	return addr, warnings, nil
+}
+
+// ParseAnyWithWarnings is like ParseAny but also returns any warnings about
+// the given source string, as returned by ParseProviderSourceWithWarnings
+// or ParseModuleSourceWithWarnings.
+//
+// Other address families currently have no warnings, so for them the
+// result is the same as for ParseAny. ParseAny never returns a
+// VersionedProvider or a ProviderPattern, so to lint those callers can
+// pass the provider part of the string to LintProviderSource instead.
+func ParseAnyWithWarnings(raw string) (any, []Warning, error) {
+	return Parser{}.ParseAnyWithWarnings(raw)
+}
+
+// ParseAnyWithWarnings is like the package-level function of the same name,
+// but applies the rules configured in the receiver.
+func (p Parser) ParseAnyWithWarnings(raw string) (any, []Warning, error) {
+	switch p.DetectSourceKind(raw) {
+	case ModuleRegistryKind:
+		return p.ParseModuleSourceWithWarnings(raw)
+	case ProviderKind:
+		return p.ParseProviderSourceWithWarnings(raw)
+	}
+	addr, err := p.ParseAny(raw)
+	return addr, nil, err
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintProviderSource(t *testing.T) {
+	tests := map[string][]Warning{
+		"hashicorp/aws":             nil,
+		"example.com/hashicorp/aws": nil,
+		"not/a/valid/source":        nil,
+		"HashiCorp/AWS": {
+			{
+				Code:       WarningUppercase,
+				Message:    `provider source "HashiCorp/AWS" contains uppercase letters, but provider source addresses are case-insensitive and conventionally written in lowercase`,
+				Span:       Span{0, 13},
+				Suggestion: "hashicorp/aws",
+			},
+		},
+		"AWS": {
+			{
+				Code:       WarningUppercase,
+				Message:    `provider source "AWS" contains uppercase letters, but provider source addresses are case-insensitive and conventionally written in lowercase`,
+				Span:       Span{0, 3},
+				Suggestion: "aws",
+			},
+		},
+		"registry.terraform.io/hashicorp/aws": {
+			{
+				Code:       WarningExplicitDefaultHost,
+				Message:    `provider source "registry.terraform.io/hashicorp/aws" explicitly includes the default hostname registry.terraform.io, which can be omitted`,
+				Span:       Span{0, 21},
+				Suggestion: "hashicorp/aws",
+			},
+		},
+		"Registry.Terraform.io/hashicorp/aws": {
+			{
+				Code:       WarningUppercase,
+				Message:    `provider source "Registry.Terraform.io/hashicorp/aws" contains uppercase letters, but provider source addresses are case-insensitive and conventionally written in lowercase`,
+				Span:       Span{0, 35},
+				Suggestion: "hashicorp/aws",
+			},
+			{
+				Code:       WarningExplicitDefaultHost,
+				Message:    `provider source "Registry.Terraform.io/hashicorp/aws" explicitly includes the default hostname registry.terraform.io, which can be omitted`,
+				Span:       Span{0, 21},
+				Suggestion: "hashicorp/aws",
+			},
+		},
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			got := LintProviderSource(input)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong warnings\n%s", diff)
+			}
+			for _, w := range got {
+				if w.Suggestion == "" {
+					continue
+				}
+				if _, err := ParseProviderSource(w.Suggestion); err != nil {
+					t.Errorf("suggestion %q is not valid: %s", w.Suggestion, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLintModuleSource(t *testing.T) {
+	tests := map[string][]Warning{
+		"hashicorp/consul/aws":                   nil,
+		"example.com/hashicorp/consul/aws//a/b":  nil,
+		"registry.terraform.io/hashicorp/consul": nil,
+		"Example.com/hashicorp/consul/aws": {
+			{
+				Code:       WarningUppercase,
+				Message:    `module source "Example.com/hashicorp/consul/aws" has uppercase letters in its hostname, but hostnames are case-insensitive and conventionally written in lowercase`,
+				Span:       Span{0, 11},
+				Suggestion: "example.com/hashicorp/consul/aws",
+			},
+		},
+		"registry.terraform.io/hashicorp/consul/aws": {
+			{
+				Code:       WarningExplicitDefaultHost,
+				Message:    `module source "registry.terraform.io/hashicorp/consul/aws" explicitly includes the default hostname registry.terraform.io, which can be omitted`,
+				Span:       Span{0, 21},
+				Suggestion: "hashicorp/consul/aws",
+			},
+		},
+		"HashiCorp/consul/aws": {
+			{
+				Code:    WarningCaseSensitive,
+				Message: `module source "HashiCorp/consul/aws" has uppercase letters in its namespace "HashiCorp", which some registries match case-sensitively, so it can't be changed automatically`,
+				Span:    Span{0, 9},
+			},
+		},
+		"hashicorp/xn--consul/aws": {
+			{
+				Code:    WarningPunycode,
+				Message: `module source "hashicorp/xn--consul/aws" has a module name "xn--consul" that looks like a punycode-encoded internationalized name, which is probably a mistake`,
+				Span:    Span{10, 20},
+			},
+		},
+		"hashicorp/consul/aws//./modules/../examples/": {
+			{
+				Code:       WarningRedundantPathSegments,
+				Message:    `module source "hashicorp/consul/aws//./modules/../examples/" has subdirectory path "./modules/../examples/", which can be written more simply as "examples"`,
+				Span:       Span{22, 44},
+				Suggestion: "hashicorp/consul/aws//examples",
+			},
+		},
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			got := LintModuleSource(input)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong warnings\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAnyWithWarnings(t *testing.T) {
+	tests := map[string]struct {
+		wantCodes []WarningCode
+		wantSpans []string
+	}{
+		"hashicorp/aws":                         {nil, nil},
+		"registry.terraform.io/hashicorp/aws":   {[]WarningCode{WarningExplicitDefaultHost}, []string{"registry.terraform.io"}},
+		"HashiCorp/consul/example":              {[]WarningCode{WarningCaseSensitive, WarningUnknownTargetSystem}, []string{"HashiCorp", "example"}},
+		"git::https://example.com/network.git":  {nil, nil},
+		"hashicorp/consul/aws//./modules/../a/": {[]WarningCode{WarningRedundantPathSegments}, []string{"./modules/../a/"}},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, warnings, err := ParseAnyWithWarnings(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var gotCodes []WarningCode
+			var gotSpans []string
+			for _, w := range warnings {
+				gotCodes = append(gotCodes, w.Code)
+				gotSpans = append(gotSpans, w.Span.In(input))
+			}
+			if diff := cmp.Diff(test.wantCodes, gotCodes); diff != "" {
+				t.Errorf("wrong warning codes\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantSpans, gotSpans); diff != "" {
+				t.Errorf("wrong warning spans\n%s", diff)
+			}
+		})
+	}
+
+	if _, warnings, err := ParseAnyWithWarnings("not a valid address"); err == nil || warnings != nil {
+		t.Errorf("wrong result for invalid address: %v, %v", warnings, err)
+	}
+}
+
+func TestParserLintWithDefaultHost(t *testing.T) {
+	p, err := EnterpriseDefaults("tfe.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		wantCodes      []WarningCode
+		wantSpans      []string
+		wantSuggestion string
+	}{
+		"registry.terraform.io/hashicorp/aws":        {nil, nil, ""},
+		"registry.terraform.io/hashicorp/consul/aws": {nil, nil, ""},
+		"tfe.example.com/hashicorp/aws":              {[]WarningCode{WarningExplicitDefaultHost}, []string{"tfe.example.com"}, "hashicorp/aws"},
+		"tfe.example.com/hashicorp/consul/aws":       {[]WarningCode{WarningExplicitDefaultHost}, []string{"tfe.example.com"}, "hashicorp/consul/aws"},
+		"Registry.Terraform.io/hashicorp/aws":        {[]WarningCode{WarningUppercase}, []string{"Registry.Terraform.io/hashicorp/aws"}, "registry.terraform.io/hashicorp/aws"},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			addr, warnings, err := p.ParseAnyWithWarnings(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var gotCodes []WarningCode
+			var gotSpans []string
+			var gotSuggestion string
+			for _, w := range warnings {
+				gotCodes = append(gotCodes, w.Code)
+				gotSpans = append(gotSpans, w.Span.In(input))
+				gotSuggestion = w.Suggestion
+			}
+			if diff := cmp.Diff(test.wantCodes, gotCodes); diff != "" {
+				t.Errorf("wrong warning codes\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantSpans, gotSpans); diff != "" {
+				t.Errorf("wrong warning spans\n%s", diff)
+			}
+			if gotSuggestion != test.wantSuggestion {
+				t.Errorf("wrong suggestion %q; want %q", gotSuggestion, test.wantSuggestion)
+			}
+			if gotSuggestion != "" {
+				again, err := p.ParseAny(gotSuggestion)
+				if err != nil {
+					t.Fatalf("suggestion %q is not valid: %s", gotSuggestion, err)
+				}
+				if diff := cmp.Diff(addr, again); diff != "" {
+					t.Errorf("suggestion %q has a different meaning\n%s", gotSuggestion, diff)
+				}
+			}
+		})
+	}
+}
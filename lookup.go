@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+)
+
+// LookupProvider returns the value in the given map whose key is equivalent
+// to the given provider address, and true, or the zero value of T and false
+// if there is no such key.
+//
+// An exact match is preferred. Otherwise, a key matches if it has the same
+// hostname and its namespace and type match ignoring case, which covers
+// keys that were constructed without normalization. In addition, a legacy
+// or unknown namespace on DefaultProviderRegistryHost matches the
+// "hashicorp" namespace, because that is how Terraform resolves provider
+// names written without a namespace. The exception is the "terraform"
+// provider, which matches the built-in provider of that type.
+//
+// If several keys match other than exactly, the one whose String result
+// sorts first is used, so that the result is deterministic.
+func LookupProvider[T any](m map[Provider]T, p Provider) (T, bool) {
+	if v, ok := m[p]; ok {
+		return v, true
+	}
+	var best Provider
+	found := false
+	for k := range m {
+		if providersEquivalent(k, p) && (!found || k.String() < best.String()) {
+			best, found = k, true
+		}
+	}
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return m[best], true
+}
+
+// LookupModule returns the value in the given map whose key is equivalent
+// to the given module address, and true, or the zero value of T and false
+// if there is no such key.
+//
+// LookupModule is LookupModuleWithProfile using the DefaultHostProfile of
+// the address's host, so on DefaultModuleRegistryHost keys match
+// case-insensitively and on other hosts they must match exactly.
+func LookupModule[T any](m map[Module]T, mod Module) (T, bool) {
+	return LookupModuleWithProfile(m, mod, DefaultHostProfile(mod.Package.Host))
+}
+
+// LookupModuleWithProfile is like LookupModule, but takes the conventions
+// of the address's host from the given profile, such as the result of
+// Parser.HostProfile.
+//
+// An exact match is preferred. Otherwise, if the profile has
+// CaseInsensitiveModules set, a key matches if its namespace, name, and
+// target system match ignoring case and it has the same host and
+// subdirectory. If several keys match other than exactly, the one whose
+// String result sorts first is used, so that the result is deterministic.
+func LookupModuleWithProfile[T any](m map[Module]T, mod Module, profile HostProfile) (T, bool) {
+	if v, ok := m[mod]; ok {
+		return v, true
+	}
+	var best Module
+	found := false
+	if profile.CaseInsensitiveModules {
+		for k := range m {
+			if modulesEquivalent(k, mod) && (!found || k.String() < best.String()) {
+				best, found = k, true
+			}
+		}
+	}
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return m[best], true
+}
+
+func providersEquivalent(a, b Provider) bool {
+	a, b = impliedProvider(a), impliedProvider(b)
+	return a.Hostname == b.Hostname &&
+		strings.EqualFold(a.Namespace, b.Namespace) &&
+		strings.EqualFold(a.Type, b.Type)
+}
+
+// impliedProvider returns the address that Terraform uses for the given
+// provider when resolving it without a NamespaceResolver, which differs
+// from the given address only for legacy or unknown namespaces on the
+// default registry. The "terraform" provider becomes the built-in provider,
+// and any other type is assumed to be in the "hashicorp" namespace.
+func impliedProvider(p Provider) Provider {
+	if !needsNamespaceResolution(p) {
+		return p
+	}
+	if strings.EqualFold(p.Type, "terraform") {
+		return Provider{Hostname: BuiltInProviderHost, Namespace: BuiltInProviderNamespace, Type: p.Type}
+	}
+	return Provider{Hostname: p.Hostname, Namespace: "hashicorp", Type: p.Type}
+}
+
+func modulesEquivalent(a, b Module) bool {
+	return a.Package.Host == b.Package.Host &&
+		strings.EqualFold(a.Package.Namespace, b.Package.Namespace) &&
+		strings.EqualFold(a.Package.Name, b.Package.Name) &&
+		strings.EqualFold(a.Package.TargetSystem, b.Package.TargetSystem) &&
+		a.Subdir == b.Subdir
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestLookupProvider(t *testing.T) {
+	m := map[Provider]string{
+		MustParseProviderSource("hashicorp/aws"):                                   "aws",
+		MustParseProviderSource("-/google"):                                        "legacy google",
+		MustParseProviderSource("example.com/foo/bar"):                             "bar",
+		MustParseProviderSource("terraform.io/builtin/terraform"):                  "builtin",
+		{Hostname: DefaultProviderRegistryHost, Namespace: "Acme", Type: "Widget"}: "widget",
+	}
+
+	tests := map[string]struct {
+		lookup Provider
+		want   string
+		wantOK bool
+	}{
+		"exact": {
+			MustParseProviderSource("hashicorp/aws"), "aws", true,
+		},
+		"legacy to hashicorp": {
+			MustParseProviderSource("-/aws"), "aws", true,
+		},
+		"unknown namespace to hashicorp": {
+			MustParseProviderSource("aws"), "aws", true,
+		},
+		"hashicorp to legacy": {
+			MustParseProviderSource("hashicorp/google"), "legacy google", true,
+		},
+		"non-normalized key": {
+			MustParseProviderSource("acme/widget"), "widget", true,
+		},
+		"legacy terraform to built-in": {
+			MustParseProviderSource("-/terraform"), "builtin", true,
+		},
+		"unknown namespace terraform to built-in": {
+			MustParseProviderSource("terraform"), "builtin", true,
+		},
+		"hashicorp terraform is not built-in": {
+			MustParseProviderSource("hashicorp/terraform"), "", false,
+		},
+		"different host": {
+			MustParseProviderSource("example.com/hashicorp/aws"), "", false,
+		},
+		"legacy only applies to the default host": {
+			Provider{Hostname: "example.com", Namespace: LegacyProviderNamespace, Type: "bar"}, "", false,
+		},
+		"missing": {
+			MustParseProviderSource("hashicorp/azurerm"), "", false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := LookupProvider(m, test.lookup)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("wrong result\ngot:  %q, %t\nwant: %q, %t", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestLookupModule(t *testing.T) {
+	m := map[Module]int{
+		MustParseModuleSource("hashicorp/consul/aws"):                1,
+		MustParseModuleSource("hashicorp/consul/aws//modules/foo"):   2,
+		MustParseModuleSource("example.com/hashicorp/consul/aws"):    3,
+		MustParseModuleSource("terraform-aws-modules/VPC/aws"):       4,
+		MustParseModuleSource("Terraform-AWS-Modules/vpc/aws//test"): 5,
+	}
+
+	tests := map[string]struct {
+		lookup string
+		want   int
+		wantOK bool
+	}{
+		"exact":                      {"hashicorp/consul/aws", 1, true},
+		"case-insensitive":           {"HashiCorp/Consul/aws", 1, true},
+		"subdir must match":          {"HashiCorp/Consul/aws//modules/foo", 2, true},
+		"case-insensitive other way": {"terraform-aws-modules/vpc/aws", 4, true},
+		"subdir case":                {"terraform-aws-modules/vpc/aws//TEST", 0, false},
+		"other host exact":           {"example.com/hashicorp/consul/aws", 3, true},
+		"other host case-sensitive":  {"example.com/HashiCorp/consul/aws", 0, false},
+		"missing":                    {"hashicorp/vault/aws", 0, false},
+		"missing subdir":             {"hashicorp/consul/aws//modules/bar", 0, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := LookupModule(m, MustParseModuleSource(test.lookup))
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("wrong result\ngot:  %d, %t\nwant: %d, %t", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestLookupModuleWithProfile(t *testing.T) {
+	p := Parser{
+		HostProfiles: map[svchost.Hostname]HostProfile{
+			svchost.Hostname("tfe.example.com"): {CaseInsensitiveModules: true},
+		},
+	}
+	m := map[Module]int{
+		MustParseModuleSource("tfe.example.com/Corp/Net/aws"): 1,
+		MustParseModuleSource("example.com/Corp/Net/aws"):     2,
+	}
+
+	tests := map[string]struct {
+		lookup string
+		want   int
+		wantOK bool
+	}{
+		"case-insensitive host":  {"tfe.example.com/corp/net/aws", 1, true},
+		"case-sensitive host":    {"example.com/corp/net/aws", 0, false},
+		"case-sensitive exact":   {"example.com/Corp/Net/aws", 2, true},
+		"case-insensitive exact": {"tfe.example.com/Corp/Net/aws", 1, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mod := MustParseModuleSource(test.lookup)
+			got, ok := LookupModuleWithProfile(m, mod, p.HostProfile(mod.Package.Host))
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("wrong result\ngot:  %d, %t\nwant: %d, %t", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
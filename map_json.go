@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalProviderMap returns the JSON encoding of the given map as an
+// object whose property names are the provider addresses in the form
+// returned by Provider.MarshalText, which is the form returned by
+// Provider.String except for addresses with an unknown namespace.
+//
+// encoding/json sorts the properties by name, so the result is
+// deterministic.
+func MarshalProviderMap[T any](m map[Provider]T) ([]byte, error) {
+	raw := make(map[string]T, len(m))
+	for addr, v := range m {
+		if addr.IsZero() {
+			return nil, fmt.Errorf("map contains the zero value of Provider")
+		}
+		key, err := addr.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		raw[string(key)] = v
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalProviderMap decodes a JSON object whose property names are
+// provider source strings, as produced by MarshalProviderMap.
+//
+// Property names are parsed with ParseProviderSource, so any valid source
+// string is accepted, but it's an error for two property names to refer
+// to the same provider.
+func UnmarshalProviderMap[T any](b []byte) (map[Provider]T, error) {
+	var raw map[string]T
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	ret := make(map[Provider]T, len(raw))
+	for k, v := range raw {
+		addr, err := ParseProviderSource(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider address %q: %s", k, err)
+		}
+		if _, exists := ret[addr]; exists {
+			return nil, fmt.Errorf("duplicate provider address %s", addr.ForDisplay())
+		}
+		ret[addr] = v
+	}
+	return ret, nil
+}
+
+// MarshalModuleMap returns the JSON encoding of the given map as an object
+// whose property names are the module addresses in the form returned by
+// Module.String.
+//
+// encoding/json sorts the properties by name, so the result is
+// deterministic.
+func MarshalModuleMap[T any](m map[Module]T) ([]byte, error) {
+	raw := make(map[string]T, len(m))
+	for addr, v := range m {
+		if addr.IsZero() {
+			return nil, fmt.Errorf("map contains the zero value of Module")
+		}
+		raw[addr.String()] = v
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalModuleMap decodes a JSON object whose property names are module
+// registry source strings, as produced by MarshalModuleMap.
+//
+// Property names are parsed with ParseModuleSource, so any valid source
+// string is accepted, but it's an error for two property names to refer
+// to the same module.
+func UnmarshalModuleMap[T any](b []byte) (map[Module]T, error) {
+	var raw map[string]T
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	ret := make(map[Module]T, len(raw))
+	for k, v := range raw {
+		addr, err := ParseModuleSource(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module address %q: %s", k, err)
+		}
+		if _, exists := ret[addr]; exists {
+			return nil, fmt.Errorf("duplicate module address %s", addr.ForDisplay())
+		}
+		ret[addr] = v
+	}
+	return ret, nil
+}
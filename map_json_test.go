@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderMapJSON(t *testing.T) {
+	m := map[Provider]string{
+		MustParseProviderSource("hashicorp/google"):     "1.2.3",
+		MustParseProviderSource("example.com/acme/foo"): "1.0.0",
+		MustParseProviderSource("hashicorp/aws"):        "5.0.0",
+	}
+	got, err := MarshalProviderMap(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"example.com/acme/foo":"1.0.0","registry.terraform.io/hashicorp/aws":"5.0.0","registry.terraform.io/hashicorp/google":"1.2.3"}`
+	if string(got) != want {
+		t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	back, err := UnmarshalProviderMap[string](got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(m, back); diff != "" {
+		t.Errorf("wrong result after round-trip\n%s", diff)
+	}
+
+	_, err = UnmarshalProviderMap[int]([]byte(`{"hashicorp/aws":1,"registry.terraform.io/HashiCorp/AWS":2}`))
+	if want := "duplicate provider address hashicorp/aws"; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
+
+func TestProviderMapJSONUnknownNamespace(t *testing.T) {
+	m := map[Provider]int{
+		MustParseProviderSource("aws"):           1,
+		MustParseProviderSource("hashicorp/aws"): 2,
+	}
+	got, err := MarshalProviderMap(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"aws":1,"registry.terraform.io/hashicorp/aws":2}`
+	if string(got) != want {
+		t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	back, err := UnmarshalProviderMap[int](got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(m, back); diff != "" {
+		t.Errorf("wrong result after round-trip\n%s", diff)
+	}
+}
+
+func TestModuleMapJSON(t *testing.T) {
+	m := map[Module][]int{
+		MustParseModuleSource("hashicorp/consul/aws//modules/foo"): {2},
+		MustParseModuleSource("hashicorp/consul/aws"):              {1},
+	}
+	got, err := MarshalModuleMap(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"registry.terraform.io/hashicorp/consul/aws":[1],"registry.terraform.io/hashicorp/consul/aws//modules/foo":[2]}`
+	if string(got) != want {
+		t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	back, err := UnmarshalModuleMap[[]int](got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(m, back); diff != "" {
+		t.Errorf("wrong result after round-trip\n%s", diff)
+	}
+
+	_, err = UnmarshalModuleMap[int]([]byte(`{"github.com/hashicorp/consul":1}`))
+	if want := `invalid module address "github.com/hashicorp/consul": source address must have three more components after the hostname: the namespace, the name, and the target system`; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+)
+
+const (
+	// WarningLegacyNamespace indicates that a provider address uses the
+	// legacy namespace "-", which only appears in state snapshots and plans
+	// created by Terraform v0.12 and earlier.
+	WarningLegacyNamespace WarningCode = "legacy-namespace"
+
+	// WarningUnqualifiedProvider indicates that a provider was given only
+	// by its type, such as "aws", which Terraform v0.13 and later interpret
+	// as a provider in the "hashicorp" namespace, which may not be the
+	// intended provider.
+	WarningUnqualifiedProvider WarningCode = "unqualified-provider"
+)
+
+// LintProviderMigration returns warnings about the given provider addresses
+// that use constructs from Terraform v0.12 and earlier, which current
+// Terraform versions no longer accept or interpret differently, for tools
+// that help users upgrade their configurations and state.
+//
+// The Suggestion of each warning is the fully-qualified address to use
+// instead, using the given resolver as described for
+// SameProviderAllowingLegacy, which also replaces the legacy "terraform"
+// provider with the built-in provider of the same type, as Terraform does.
+//
+// Each distinct address produces at most one warning, in the order the
+// addresses were given. LintProviderMigration returns an error if the
+// resolver fails.
+func LintProviderMigration(providers []Provider, resolver NamespaceResolver) ([]Warning, error) {
+	var warnings []Warning
+	seen := make(map[Provider]struct{}, len(providers))
+	for _, p := range providers {
+		if _, ok := seen[p]; ok || !needsNamespaceResolution(p) {
+			continue
+		}
+		seen[p] = struct{}{}
+
+		replacement, err := resolveLegacyProvider(p, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if p.IsLegacy() {
+			warnings = append(warnings, Warning{
+				Code:       WarningLegacyNamespace,
+				Message:    fmt.Sprintf("provider %q uses the legacy namespace from Terraform v0.12 and earlier, and should be replaced with %q", p.ForDisplay(), replacement.ForDisplay()),
+				Suggestion: replacement.ForDisplay(),
+			})
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Code:       WarningUnqualifiedProvider,
+			Message:    fmt.Sprintf("provider %q is given only by its type, as in Terraform v0.12 and earlier, and should be written in full as %q", p.Type, replacement.ForDisplay()),
+			Suggestion: replacement.ForDisplay(),
+		})
+	}
+	return warnings, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintProviderMigration(t *testing.T) {
+	resolver := NamespaceResolverFunc(func(typeName string) (string, error) {
+		switch typeName {
+		case "datadog":
+			return "DataDog", nil
+		case "broken":
+			return "", fmt.Errorf("registry unavailable")
+		default:
+			return "hashicorp", nil
+		}
+	})
+
+	tests := map[string]struct {
+		providers []Provider
+		want      []Warning
+		wantErr   string
+	}{
+		"no problems": {
+			providers: []Provider{
+				MustParseProviderSource("hashicorp/aws"),
+				MustParseProviderSource("example.com/foo/bar"),
+				MustParseProviderSource("terraform.io/builtin/terraform"),
+			},
+		},
+		"legacy and unqualified": {
+			providers: []Provider{
+				MustParseProviderSource("-/aws"),
+				MustParseProviderSource("datadog"),
+				MustParseProviderSource("-/aws"),
+				MustParseProviderSource("-/terraform"),
+			},
+			want: []Warning{
+				{
+					Code:       WarningLegacyNamespace,
+					Message:    `provider "-/aws" uses the legacy namespace from Terraform v0.12 and earlier, and should be replaced with "hashicorp/aws"`,
+					Suggestion: "hashicorp/aws",
+				},
+				{
+					Code:       WarningUnqualifiedProvider,
+					Message:    `provider "datadog" is given only by its type, as in Terraform v0.12 and earlier, and should be written in full as "datadog/datadog"`,
+					Suggestion: "datadog/datadog",
+				},
+				{
+					Code:       WarningLegacyNamespace,
+					Message:    `provider "-/terraform" uses the legacy namespace from Terraform v0.12 and earlier, and should be replaced with "terraform.io/builtin/terraform"`,
+					Suggestion: "terraform.io/builtin/terraform",
+				},
+			},
+		},
+		"resolver failure": {
+			providers: []Provider{MustParseProviderSource("-/broken")},
+			wantErr:   `failed to resolve namespace for legacy provider "broken": registry unavailable`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LintProviderMigration(test.providers, resolver)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	svchost "github.com/hashicorp/terraform-svchost"
 )
@@ -28,7 +29,9 @@ type Module struct {
 	//
 	// Subdir uses a normalized forward-slash-based path syntax within the
 	// virtual filesystem represented by the final package. It will never
-	// include `../` or `./` sequences.
+	// include `../` or `./` sequences. Any backslashes in the given
+	// subdirectory, as Windows users might write, are normalized to forward
+	// slashes during parsing.
 	Subdir string
 }
 
@@ -42,24 +45,72 @@ var moduleRegistryTargetSystemPattern = regexp.MustCompile("^[0-9a-z]{1,64}$")
 // ParseModuleSource only accepts module registry addresses, and
 // will reject any other address type.
 func ParseModuleSource(raw string) (Module, error) {
+	return Parser{}.ParseModuleSource(raw)
+}
+
+// ParseModuleSource is like the package-level function of the same name,
+// but additionally applies the rules configured in the receiver.
+func (p Parser) ParseModuleSource(raw string) (ret Module, err error) {
+	if p.Observer != nil {
+		start := time.Now()
+		defer func() { p.observe(ModuleRegistryKind, start, ret.Package.Host, err) }()
+	}
+	p.tracef("parsing %q as a module registry source address", raw)
+	if err := p.checkLength(raw); err != nil {
+		return Module{}, p.localize(fmt.Errorf("invalid module source address: %s", err))
+	}
+
+	if err := p.checkInterpolation(raw); err != nil {
+		return Module{}, p.localize(err)
+	}
+
+	ret, err = p.parseModuleSource(raw)
+	if err != nil {
+		return ret, p.localize(err)
+	}
+	if err := p.checkModuleLimits(ret); err != nil {
+		return Module{}, p.localize(err)
+	}
+	if err := p.checkHostname(ret.Package.Host); err != nil {
+		return Module{}, p.localize(err)
+	}
+	if err := p.checkModuleHostProfile(ret); err != nil {
+		return Module{}, p.localize(err)
+	}
+	return ret, nil
+}
+
+func (p Parser) parseModuleSource(raw string) (Module, error) {
 	var err error
 
-	var subDir string
-	raw, subDir = splitPackageSubdir(raw)
-	if strings.HasPrefix(subDir, "../") {
+	raw, givenSubDir := sourceDirSubdir(raw)
+	subDir := normalizeSubdir(givenSubDir)
+	if subDir != givenSubDir {
+		p.tracef("normalized subdirectory %q to %q", givenSubDir, subDir)
+	} else if subDir != "" {
+		p.tracef("found subdirectory %q", subDir)
+	}
+	if subDir == ".." || strings.HasPrefix(subDir, "../") {
 		return Module{}, fmt.Errorf("subdirectory path %q leads outside of the module package", subDir)
 	}
 
 	parts := strings.Split(raw, "/")
 	// A valid registry address has either three or four parts, because the
 	// leading hostname part is optional.
+	p.tracef("found %d slash-separated components in package address %q", len(parts), raw)
 	if len(parts) != 3 && len(parts) != 4 {
+		if len(parts) == 2 && subDir == "" && looksLikeProviderSource(parts) {
+			return Module{}, fmt.Errorf("a module registry source address must have either three or four slash-separated components; %q looks like a provider source address, which belongs in a required_providers block instead", raw)
+		}
 		return Module{}, fmt.Errorf("a module registry source address must have either three or four slash-separated components")
 	}
 
-	host := DefaultModuleRegistryHost
+	host := p.defaultModuleHost()
+	if len(parts) == 3 {
+		p.tracef("no hostname given, so using default hostname %s", host.ForDisplay())
+	}
 	if len(parts) == 4 {
-		host, err = svchost.ForComparison(parts[0])
+		host, err = p.parseHostname(parts[0])
 		if err != nil {
 			// The svchost library doesn't produce very good error messages to
 			// return to an end-user, so we'll use some custom ones here.
@@ -72,7 +123,8 @@ func ParseModuleSource(raw string) (Module, error) {
 				return Module{}, fmt.Errorf("invalid module registry hostname %q", parts[0])
 			}
 		}
-		if !strings.Contains(host.String(), ".") {
+		p.tracef("normalized hostname %q to %s", parts[0], host.ForDisplay())
+		if !strings.Contains(host.String(), ".") && !p.allowsSingleLabelModuleHost(host) {
 			return Module{}, fmt.Errorf("invalid module registry hostname: must contain at least one dot")
 		}
 		// Discard the hostname prefix now that we've processed it
@@ -109,10 +161,23 @@ func ParseModuleSource(raw string) (Module, error) {
 		}
 		return ret, fmt.Errorf("invalid target system %q: %s", parts[2], err)
 	}
+	p.tracef("accepted namespace %q, module name %q, and target system %q", ret.Package.Namespace, ret.Package.Name, ret.Package.TargetSystem)
 
 	return ret, nil
 }
 
+// looksLikeProviderSource returns true if the given slash-separated parts
+// of a module source string are a valid provider namespace and type, to
+// help explain the mistake of using a provider address as a module source.
+func looksLikeProviderSource(parts []string) bool {
+	for _, part := range parts {
+		if _, err := ParseProviderPart(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 // MustParseModuleSource is a wrapper around ParseModuleSource that panics if
 // it returns an error.
 func MustParseModuleSource(raw string) (Module) {
@@ -169,6 +234,14 @@ func parseModuleRegistryTargetSystem(given string) (string, error) {
 	return given, nil
 }
 
+// IsZero returns true if the receiver is the zero value of Module.
+//
+// The zero value is not a valid module address, and so it can't be
+// marshaled as text.
+func (s Module) IsZero() bool {
+	return s == Module{}
+}
+
 // String returns a full representation of the address, including any
 // additional components that are typically implied by omission in
 // user-written addresses.
@@ -197,18 +270,84 @@ func (s Module) ForDisplay() string {
 	return s.Package.ForDisplay()
 }
 
-// splitPackageSubdir detects whether the given address string has a
-// subdirectory portion, and if so returns a non-empty subDir string
-// along with the trimmed package address.
+// ShortestForm returns the most concise source string that
+// ParseModuleSource would parse to the receiver, omitting the default
+// hostname.
+//
+// The subdirectory is already normalized during parsing, so redundant
+// segments such as "./" never appear in the result. This is intended for
+// tools that rewrite source strings into their most idiomatic form.
+func (s Module) ShortestForm() string {
+	return s.ForDisplay()
+}
+
+// WithHost returns a copy of the receiver with its package hostname
+// replaced by the given hostname, after validating and normalizing it, or
+// an error if the given hostname is not valid for a module registry.
+func (s Module) WithHost(host string) (Module, error) {
+	pkg, err := (&ModulePackageBuilder{addr: s.Package}).Host(host).Build()
+	if err != nil {
+		return Module{}, err
+	}
+	s.Package = pkg
+	return s, nil
+}
+
+// SamePackage returns true if the two given module addresses belong to the
+// same module package, regardless of their subdirectories.
+//
+// This is useful for grouping addresses of a package's submodules and
+// examples together with the package's root module.
+func SamePackage(a, b Module) bool {
+	return a.Package == b.Package
+}
+
+// RelativeSubdir returns the path of the subdirectory of module address to
+// relative to the subdirectory of module address from, such as "../foo",
+// or "." if both have the same subdirectory.
 //
-// If the given string doesn't have a subdirectory portion then it'll
-// just be returned verbatim in packageAddr, with an empty subDir value.
-func splitPackageSubdir(given string) (packageAddr, subDir string) {
-	packageAddr, subDir = sourceDirSubdir(given)
-	if subDir != "" {
-		subDir = path.Clean(subDir)
-	}
-	return packageAddr, subDir
+// RelativeSubdir returns an error if the two addresses don't belong to the
+// same module package, because then there is no relative path between
+// them.
+func RelativeSubdir(from, to Module) (string, error) {
+	if !SamePackage(from, to) {
+		return "", fmt.Errorf("%s and %s belong to different module packages", from.ForDisplay(), to.ForDisplay())
+	}
+
+	var fromParts, toParts []string
+	if from.Subdir != "" {
+		fromParts = strings.Split(from.Subdir, "/")
+	}
+	if to.Subdir != "" {
+		toParts = strings.Split(to.Subdir, "/")
+	}
+	common := 0
+	for common < len(fromParts) && common < len(toParts) && fromParts[common] == toParts[common] {
+		common++
+	}
+
+	var parts []string
+	for range fromParts[common:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, toParts[common:]...)
+	if len(parts) == 0 {
+		return ".", nil
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// normalizeSubdir normalizes the subdirectory portion of an address, as
+// returned by sourceDirSubdir, into the form used in Module.Subdir.
+//
+// Backslashes are treated as path separators, in the same way as
+// Windows-style local paths, so that e.g. "examples\foo" and "examples/foo"
+// both produce the same normalized result.
+func normalizeSubdir(given string) string {
+	if given == "" {
+		return ""
+	}
+	return path.Clean(strings.ReplaceAll(given, `\`, "/"))
 }
 
 // sourceDirSubdir takes a source URL and returns a tuple of the URL without
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// ModuleSourceBitbucket is a module source address using the shorthand
+// that Terraform accepts for repositories on Bitbucket Cloud, such as
+// "bitbucket.org/hashicorp/example//modules/vpc?ref=v1.2.3".
+//
+// ParseModuleSource rejects these addresses, because "bitbucket.org" can't
+// be used as a module registry host, so callers that need to inspect them
+// must use ParseModuleSourceBitbucket instead.
+type ModuleSourceBitbucket struct {
+	// Owner is the workspace that owns the repository.
+	Owner string
+
+	// Repository is the name of the repository, without any ".git"
+	// suffix.
+	Repository string
+
+	// Subdir is the normalized path of the subdirectory within the
+	// repository that contains the module, or empty for the repository's
+	// root directory.
+	Subdir string
+
+	// Ref is the value of the "ref" query argument, which selects a
+	// branch, tag, or commit, or empty if the default branch is used.
+	Ref string
+
+	// Query holds any query arguments other than "ref", which go-getter
+	// interprets as options for git.
+	Query url.Values
+}
+
+var (
+	bitbucketOwnerPattern      = regexp.MustCompile(`^[A-Za-z0-9_-]{1,62}$`)
+	bitbucketRepositoryPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,62}$`)
+)
+
+// ParseModuleSourceBitbucket parses a module source string using the
+// Bitbucket shorthand, which starts with "bitbucket.org/" followed by the
+// workspace and repository names, into a ModuleSourceBitbucket.
+//
+// As in go-getter, a subdirectory may be given either after a "//"
+// separator or as additional path segments after the repository name.
+func ParseModuleSourceBitbucket(raw string) (ModuleSourceBitbucket, error) {
+	parsed, err := parseHostedGitShorthand(raw, "Bitbucket", "bitbucket.org", bitbucketOwnerPattern, bitbucketRepositoryPattern)
+	if err != nil {
+		return ModuleSourceBitbucket{}, err
+	}
+	return ModuleSourceBitbucket(parsed), nil
+}
+
+// String returns the source string for the address, in the shorthand
+// form.
+func (s ModuleSourceBitbucket) String() string {
+	return hostedGitShorthand(s).String("bitbucket.org")
+}
+
+// IsZero returns true if the address is the zero value of
+// ModuleSourceBitbucket.
+func (s ModuleSourceBitbucket) IsZero() bool {
+	return s.Owner == "" && s.Repository == "" && s.Subdir == "" && s.Ref == "" && s.Query == nil
+}
+
+// ForDisplay returns the same result as String.
+func (s ModuleSourceBitbucket) ForDisplay() string {
+	return s.String()
+}
+
+// Remote returns the equivalent address using an explicit git getter, such
+// as "git::https://bitbucket.org/hashicorp/example.git?ref=v1.2.3".
+//
+// go-getter asks the Bitbucket API whether a repository uses git or
+// Mercurial, but Bitbucket Cloud has only hosted git repositories since
+// 2020, so Remote always uses git.
+func (s ModuleSourceBitbucket) Remote() ModuleSourceRemote {
+	return hostedGitShorthand(s).Remote("bitbucket.org")
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseModuleSourceBitbucket(t *testing.T) {
+	tests := map[string]struct {
+		want       ModuleSourceBitbucket
+		wantString string
+		wantRemote string
+		wantErr    string
+	}{
+		"bitbucket.org/hashicorp/terraform-consul-aws": {
+			want:       ModuleSourceBitbucket{Owner: "hashicorp", Repository: "terraform-consul-aws"},
+			wantString: "bitbucket.org/hashicorp/terraform-consul-aws",
+			wantRemote: "git::https://bitbucket.org/hashicorp/terraform-consul-aws.git",
+		},
+		"bitbucket.org/my_team/example.git//modules/vpc?ref=v1.2.3&depth=1": {
+			want: ModuleSourceBitbucket{
+				Owner:      "my_team",
+				Repository: "example",
+				Subdir:     "modules/vpc",
+				Ref:        "v1.2.3",
+				Query:      url.Values{"depth": {"1"}},
+			},
+			wantString: "bitbucket.org/my_team/example//modules/vpc?depth=1&ref=v1.2.3",
+			wantRemote: "git::https://bitbucket.org/my_team/example.git//modules/vpc?depth=1&ref=v1.2.3",
+		},
+		"bitbucket.org/hashicorp": {
+			wantErr: `Bitbucket module source address "bitbucket.org/hashicorp" must have the form "bitbucket.org/owner/repository"`,
+		},
+		"bitbucket.org/hashi corp/example": {
+			wantErr: `invalid Bitbucket owner "hashi corp"`,
+		},
+		"github.com/hashicorp/example": {
+			wantErr: `Bitbucket module source address "github.com/hashicorp/example" must start with "bitbucket.org/"`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseModuleSourceBitbucket(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := got.String(); got != test.wantString {
+				t.Errorf("wrong string\ngot:  %s\nwant: %s", got, test.wantString)
+			}
+			if got := got.Remote().String(); got != test.wantRemote {
+				t.Errorf("wrong remote address\ngot:  %s\nwant: %s", got, test.wantRemote)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// moduleVersionPattern matches the exact version numbers that module
+// registries report, such as "0.11.0" or "1.0.0-beta1".
+var moduleVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ContentKey returns a string that identifies the content of the receiver
+// at the given exact version of its package, for use as a key in caches of
+// module content that are shared between tools, such as
+// "registry.terraform.io/hashicorp/consul/aws@0.11.0//modules/consul-cluster".
+//
+// The key always includes the hostname, and the subdirectory if any, so
+// two addresses have the same key only if they refer to the same directory
+// of the same package version. Namespaces and names are not case-folded,
+// because some registries match them case-sensitively. Use
+// ParseModuleContentKey to recover the address and version from a key.
+func (s Module) ContentKey(version string) string {
+	if s.IsZero() {
+		panic("called ContentKey on zero-value Module")
+	}
+	ret := s.Package.String() + "@" + version
+	if s.Subdir != "" {
+		ret += "//" + s.Subdir
+	}
+	return ret
+}
+
+// ParseModuleContentKey parses a key returned by Module.ContentKey, returning
+// the module address and the package version that it identifies.
+//
+// ParseModuleContentKey returns an error if the given string is not a key in
+// exactly the form that ContentKey returns, so that each address and
+// version has only one valid key.
+func ParseModuleContentKey(key string) (Module, string, error) {
+	pkgRaw, rest, ok := strings.Cut(key, "@")
+	if !ok {
+		return Module{}, "", fmt.Errorf("invalid module content key %q: must include a version after \"@\"", key)
+	}
+	version, subdir, _ := strings.Cut(rest, "//")
+	if !moduleVersionPattern.MatchString(version) {
+		return Module{}, "", fmt.Errorf("invalid module content key %q: invalid version %q", key, version)
+	}
+	raw := pkgRaw
+	if subdir != "" {
+		raw += "//" + subdir
+	}
+	mod, err := ParseModuleSource(raw)
+	if err != nil {
+		return Module{}, "", fmt.Errorf("invalid module content key %q: %s", key, err)
+	}
+	if want := mod.ContentKey(version); key != want {
+		return Module{}, "", fmt.Errorf("invalid module content key %q: must be written as %q", key, want)
+	}
+	return mod, version, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestModuleContentKey(t *testing.T) {
+	tests := map[string]string{
+		"hashicorp/consul/aws":                          "registry.terraform.io/hashicorp/consul/aws@0.11.0",
+		"hashicorp/consul/aws//modules/consul-cluster":  "registry.terraform.io/hashicorp/consul/aws@0.11.0//modules/consul-cluster",
+		"Example.com/HashiCorp/Consul/aws//./examples/": "example.com/HashiCorp/Consul/aws@0.11.0//examples",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			mod := MustParseModuleSource(input)
+			got := mod.ContentKey("0.11.0")
+			if got != want {
+				t.Fatalf("wrong key\ngot:  %s\nwant: %s", got, want)
+			}
+
+			gotMod, gotVersion, err := ParseModuleContentKey(got)
+			if err != nil {
+				t.Fatalf("unexpected error parsing key: %s", err)
+			}
+			if gotMod != mod || gotVersion != "0.11.0" {
+				t.Errorf("wrong result parsing key: %s, %s", gotMod, gotVersion)
+			}
+		})
+	}
+}
+
+func TestParseModuleContentKeyErrors(t *testing.T) {
+	tests := map[string]string{
+		"registry.terraform.io/hashicorp/consul/aws":              `invalid module content key "registry.terraform.io/hashicorp/consul/aws": must include a version after "@"`,
+		"registry.terraform.io/hashicorp/consul/aws@v0.11.0":      `invalid module content key "registry.terraform.io/hashicorp/consul/aws@v0.11.0": invalid version "v0.11.0"`,
+		"registry.terraform.io/hashicorp/consul@0.11.0":           `invalid module content key "registry.terraform.io/hashicorp/consul@0.11.0": source address must have three more components after the hostname: the namespace, the name, and the target system`,
+		"hashicorp/consul/aws@0.11.0":                             `invalid module content key "hashicorp/consul/aws@0.11.0": must be written as "registry.terraform.io/hashicorp/consul/aws@0.11.0"`,
+		"registry.terraform.io/hashicorp/consul/aws@0.11.0//./a/": `invalid module content key "registry.terraform.io/hashicorp/consul/aws@0.11.0//./a/": must be written as "registry.terraform.io/hashicorp/consul/aws@0.11.0//a"`,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, _, err := ParseModuleContentKey(input)
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got := err.Error(); got != want {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// ModuleSourceGitHub is a module source address using the shorthand that
+// Terraform accepts for git repositories on GitHub, such as
+// "github.com/hashicorp/example//modules/vpc?ref=v1.2.3".
+//
+// ParseModuleSource rejects these addresses, because "github.com" can't be
+// used as a module registry host, so callers that need to inspect them
+// must use ParseModuleSourceGitHub instead.
+type ModuleSourceGitHub struct {
+	// Owner is the user or organization that owns the repository.
+	Owner string
+
+	// Repository is the name of the repository, without any ".git"
+	// suffix.
+	Repository string
+
+	// Subdir is the normalized path of the subdirectory within the
+	// repository that contains the module, or empty for the repository's
+	// root directory.
+	Subdir string
+
+	// Ref is the value of the "ref" query argument, which selects a
+	// branch, tag, or commit, or empty if the default branch is used.
+	Ref string
+
+	// Query holds any query arguments other than "ref", which go-getter
+	// interprets as options for git.
+	Query url.Values
+}
+
+var (
+	gitHubOwnerPattern      = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	gitHubRepositoryPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+)
+
+// ParseModuleSourceGitHub parses a module source string using the GitHub
+// shorthand, which starts with "github.com/" followed by the owner and
+// repository names, into a ModuleSourceGitHub.
+//
+// As in go-getter, a subdirectory may be given either after a "//"
+// separator or as additional path segments after the repository name.
+func ParseModuleSourceGitHub(raw string) (ModuleSourceGitHub, error) {
+	parsed, err := parseHostedGitShorthand(raw, "GitHub", "github.com", gitHubOwnerPattern, gitHubRepositoryPattern)
+	if err != nil {
+		return ModuleSourceGitHub{}, err
+	}
+	return ModuleSourceGitHub(parsed), nil
+}
+
+// String returns the source string for the address, in the shorthand
+// form.
+func (s ModuleSourceGitHub) String() string {
+	return hostedGitShorthand(s).String("github.com")
+}
+
+// IsZero returns true if the address is the zero value of
+// ModuleSourceGitHub.
+func (s ModuleSourceGitHub) IsZero() bool {
+	return s.Owner == "" && s.Repository == "" && s.Subdir == "" && s.Ref == "" && s.Query == nil
+}
+
+// ForDisplay returns the same result as String.
+func (s ModuleSourceGitHub) ForDisplay() string {
+	return s.String()
+}
+
+// Remote returns the equivalent address using an explicit git getter, as
+// go-getter would expand the shorthand, such as
+// "git::https://github.com/hashicorp/example.git?ref=v1.2.3".
+func (s ModuleSourceGitHub) Remote() ModuleSourceRemote {
+	return hostedGitShorthand(s).Remote("github.com")
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseModuleSourceGitHub(t *testing.T) {
+	tests := map[string]struct {
+		want       ModuleSourceGitHub
+		wantString string
+		wantRemote string
+		wantErr    string
+	}{
+		"github.com/hashicorp/example": {
+			want:       ModuleSourceGitHub{Owner: "hashicorp", Repository: "example"},
+			wantString: "github.com/hashicorp/example",
+			wantRemote: "git::https://github.com/hashicorp/example.git",
+		},
+		"github.com/hashicorp/example.git//modules/vpc?ref=v1.2.3": {
+			want: ModuleSourceGitHub{
+				Owner:      "hashicorp",
+				Repository: "example",
+				Subdir:     "modules/vpc",
+				Ref:        "v1.2.3",
+			},
+			wantString: "github.com/hashicorp/example//modules/vpc?ref=v1.2.3",
+			wantRemote: "git::https://github.com/hashicorp/example.git//modules/vpc?ref=v1.2.3",
+		},
+		"github.com/hashicorp/example/modules/vpc?ref=main&depth=1": {
+			want: ModuleSourceGitHub{
+				Owner:      "hashicorp",
+				Repository: "example",
+				Subdir:     "modules/vpc",
+				Ref:        "main",
+				Query:      url.Values{"depth": {"1"}},
+			},
+			wantString: "github.com/hashicorp/example//modules/vpc?depth=1&ref=main",
+			wantRemote: "git::https://github.com/hashicorp/example.git//modules/vpc?depth=1&ref=main",
+		},
+		"github.com/hashicorp/example?depth=many": {
+			wantErr: `invalid git depth "many": must be a non-negative whole number`,
+		},
+		"github.com/hashicorp": {
+			wantErr: `GitHub module source address "github.com/hashicorp" must have the form "github.com/owner/repository"`,
+		},
+		"github.com/-bad/example": {
+			wantErr: `invalid GitHub owner "-bad"`,
+		},
+		"github.com/hashicorp/ex ample": {
+			wantErr: `invalid GitHub repository name "ex ample"`,
+		},
+		"github.com/hashicorp/example//../foo": {
+			wantErr: `subdirectory path "../foo" leads outside of the module package`,
+		},
+		"hashicorp/consul/aws": {
+			wantErr: `GitHub module source address "hashicorp/consul/aws" must start with "github.com/"`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseModuleSourceGitHub(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := got.String(); got != test.wantString {
+				t.Errorf("wrong string\ngot:  %s\nwant: %s", got, test.wantString)
+			}
+			remote := got.Remote()
+			if got := remote.String(); got != test.wantRemote {
+				t.Errorf("wrong remote address\ngot:  %s\nwant: %s", got, test.wantRemote)
+			}
+			if remote.Git == nil || remote.Git.Ref != test.want.Ref {
+				t.Errorf("wrong git arguments in remote address %#v", remote.Git)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"path"
+)
+
+// ModulesDir is the directory, relative to a Terraform working directory,
+// where "terraform init" installs module packages, and where it writes the
+// module manifest described by ModuleManifest.
+const ModulesDir = ".terraform/modules"
+
+// ModuleInstallDir returns the slash-separated directory, relative to the
+// working directory, where "terraform init" extracts the module package
+// for the module call with the given key, such as
+// ".terraform/modules/network.vpc".
+//
+// The key is a dot-separated sequence of module call names, as in
+// ModuleManifestRecord.Key. The root module is never installed, so
+// ModuleInstallDir returns an error if the key is empty.
+func ModuleInstallDir(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("the root module has no installation directory")
+	}
+	return ModulesDir + "/" + key, nil
+}
+
+// InstallDir returns the slash-separated directory, relative to the working
+// directory, that contains the receiver once "terraform init" has
+// installed it for the module call with the given key. This is the package
+// directory from ModuleInstallDir followed by the subdirectory, if any, and
+// is what Terraform records as the "Dir" of the module in its module
+// manifest.
+//
+// InstallDir returns an error under the same conditions as
+// ModuleInstallDir. Callers should use filepath.FromSlash to convert the
+// result for use with the local filesystem.
+func (s Module) InstallDir(key string) (string, error) {
+	dir, err := ModuleInstallDir(key)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, s.Subdir), nil
+}
+
+// ArchivePath returns a slash-separated relative path for a gzipped tar
+// archive of the given version of the receiver, such as
+// "registry.terraform.io/hashicorp/consul/aws/0.11.0.tar.gz", for tools
+// that vendor module packages.
+//
+// Each part of the address is a separate directory, following the layout
+// of a provider filesystem mirror, so that distinct packages never share a
+// path. The version must be an exact version such as "0.11.0", and
+// ArchivePath returns an error otherwise. Callers should use
+// filepath.FromSlash to convert the result for use with the local
+// filesystem.
+func (s ModulePackage) ArchivePath(version string) (string, error) {
+	if s.IsZero() {
+		panic("called ArchivePath on zero-value ModulePackage")
+	}
+	if !moduleVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid module version %q: must be an exact version such as \"1.0.0\"", version)
+	}
+	return s.Host.ForDisplay() + "/" + s.ForRegistryProtocol() + "/" + version + ".tar.gz", nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestModuleInstallDir(t *testing.T) {
+	tests := map[string]struct {
+		source string
+		key    string
+		want   string
+	}{
+		"package root": {
+			"hashicorp/consul/aws",
+			"consul",
+			".terraform/modules/consul",
+		},
+		"nested call with subdirectory": {
+			"hashicorp/consul/aws//modules/consul-cluster",
+			"consul.consul_clients",
+			".terraform/modules/consul.consul_clients/modules/consul-cluster",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := MustParseModuleSource(test.source).InstallDir(test.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestModuleInstallDirRoot(t *testing.T) {
+	_, err := MustParseModuleSource("hashicorp/consul/aws").InstallDir("")
+	if want := "the root module has no installation directory"; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
+
+func TestModulePackageArchivePath(t *testing.T) {
+	tests := map[string]string{
+		"hashicorp/consul/aws":                            "registry.terraform.io/hashicorp/consul/aws/0.11.0.tar.gz",
+		"example.com/awesomecorp/network/happycloud":      "example.com/awesomecorp/network/happycloud/0.11.0.tar.gz",
+		"example.com:8443/awesomecorp/network/happycloud": "example.com:8443/awesomecorp/network/happycloud/0.11.0.tar.gz",
+		"a-b/c/d": "registry.terraform.io/a-b/c/d/0.11.0.tar.gz",
+		"a/b-c/d": "registry.terraform.io/a/b-c/d/0.11.0.tar.gz",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := MustParseModuleSource(input).Package.ArchivePath("0.11.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+
+	_, err := MustParseModuleSource("hashicorp/consul/aws").Package.ArchivePath("../../evil")
+	if want := `invalid module version "../../evil": must be an exact version such as "1.0.0"`; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ModuleManifest is the content of the module manifest file that
+// "terraform init" writes to .terraform/modules/modules.json in an
+// initialized working directory, describing where each module call's
+// source was installed.
+type ModuleManifest struct {
+	Modules []ModuleManifestRecord `json:"Modules"`
+}
+
+// ModuleManifestRecord describes one installed module in a ModuleManifest.
+type ModuleManifestRecord struct {
+	// Key identifies the module call, as a dot-separated sequence of
+	// module call names such as "network.vpc". The root module has an
+	// empty key.
+	Key string `json:"Key"`
+
+	// Source is the source address of the module, as Terraform recorded
+	// it. This may be a module registry address, a local path, or a
+	// remote source such as a Git repository.
+	Source string `json:"Source"`
+
+	// Version is the selected version of a module from a module registry,
+	// or empty for any other kind of source.
+	Version string `json:"Version,omitempty"`
+
+	// Dir is the directory containing the module, relative to the
+	// working directory.
+	Dir string `json:"Dir"`
+}
+
+// DecodeModuleManifest decodes the content of a module manifest file.
+func DecodeModuleManifest(r io.Reader) (*ModuleManifest, error) {
+	var ret ModuleManifest
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("invalid module manifest: %s", err)
+	}
+	return &ret, nil
+}
+
+// RegistrySource returns the module registry address that the module was
+// installed from, or false if it was installed from some other kind of
+// source, such as a local path or a Git repository.
+func (r ModuleManifestRecord) RegistrySource() (Module, bool) {
+	if r.Source == "" {
+		return Module{}, false
+	}
+	mod, err := ParseModuleSource(r.Source)
+	if err != nil {
+		return Module{}, false
+	}
+	return mod, true
+}
+
+// Record returns the record for the module call with the given key, or
+// false if there is no such record.
+func (m *ModuleManifest) Record(key string) (ModuleManifestRecord, bool) {
+	for _, record := range m.Modules {
+		if record.Key == key {
+			return record, true
+		}
+	}
+	return ModuleManifestRecord{}, false
+}
+
+// RegistrySources returns the module registry address of each module call
+// that was installed from a module registry, keyed by its module key.
+//
+// Module calls with other kinds of source are not included.
+func (m *ModuleManifest) RegistrySources() map[string]Module {
+	ret := make(map[string]Module)
+	for _, record := range m.Modules {
+		if mod, ok := record.RegistrySource(); ok {
+			ret[record.Key] = mod
+		}
+	}
+	return ret
+}
+
+// KeysBySource returns the keys of the module calls that were installed
+// from each module registry address, in the order they appear in the
+// manifest.
+//
+// Module calls with other kinds of source are not included.
+func (m *ModuleManifest) KeysBySource() map[Module][]string {
+	ret := make(map[Module][]string)
+	for _, record := range m.Modules {
+		if mod, ok := record.RegistrySource(); ok {
+			ret[mod] = append(ret[mod], record.Key)
+		}
+	}
+	return ret
+}
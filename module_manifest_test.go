@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeModuleManifest(t *testing.T) {
+	f, err := os.Open("testdata/modules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	manifest, err := DecodeModuleManifest(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	consul := MustParseModuleSource("hashicorp/consul/aws")
+	cluster := MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster")
+
+	wantSources := map[string]Module{
+		"consul":  consul,
+		"servers": cluster,
+		"clients": cluster,
+	}
+	if diff := cmp.Diff(wantSources, manifest.RegistrySources()); diff != "" {
+		t.Errorf("wrong registry sources\n%s", diff)
+	}
+
+	wantKeys := map[Module][]string{
+		consul:  {"consul"},
+		cluster: {"servers", "clients"},
+	}
+	if diff := cmp.Diff(wantKeys, manifest.KeysBySource()); diff != "" {
+		t.Errorf("wrong keys by source\n%s", diff)
+	}
+
+	record, ok := manifest.Record("consul.consul_clients")
+	if !ok {
+		t.Fatal("no record for consul.consul_clients")
+	}
+	if _, ok := record.RegistrySource(); ok {
+		t.Errorf("local module has a registry source")
+	}
+	if got, want := record.Dir, ".terraform/modules/consul/modules/consul-cluster"; got != want {
+		t.Errorf("wrong dir %q; want %q", got, want)
+	}
+
+	if _, ok := manifest.Record("nonexistent"); ok {
+		t.Errorf("found record for nonexistent key")
+	}
+}
+
+func TestDecodeModuleManifestInvalid(t *testing.T) {
+	_, err := DecodeModuleManifest(strings.NewReader(`{"Modules":`))
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+	if got, want := err.Error(), "invalid module manifest: unexpected EOF"; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
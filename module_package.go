@@ -26,6 +26,14 @@ type ModulePackage struct {
 	TargetSystem string
 }
 
+// IsZero returns true if the receiver is the zero value of ModulePackage.
+//
+// The zero value is not a valid module package address, and so it can't be
+// marshaled as text.
+func (s ModulePackage) IsZero() bool {
+	return s == ModulePackage{}
+}
+
 func (s ModulePackage) String() string {
 	// Note: we're using the "display" form of the hostname here because
 	// for our service hostnames "for display" means something different:
@@ -50,6 +58,8 @@ func (s ModulePackage) ForDisplay() string {
 // This is primarily intended for generating addresses to send to the
 // registry in question via the registry protocol, since the protocol
 // skips sending the registry its own hostname as part of identifiers.
+// Provider.ForRegistryProtocol is the equivalent for the provider registry
+// protocol.
 func (s ModulePackage) ForRegistryProtocol() string {
 	var buf strings.Builder
 	buf.WriteString(s.Namespace)
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// ModuleVersionsResponse is the response from the module registry
+// protocol's "versions" operation.
+type ModuleVersionsResponse struct {
+	Modules []ModuleVersionsEntry `json:"modules"`
+}
+
+// ModuleVersionsEntry describes the available versions of one module
+// package in a ModuleVersionsResponse.
+type ModuleVersionsEntry struct {
+	// Source is the "namespace/name/system" address of the module package,
+	// without the registry hostname.
+	Source string `json:"source"`
+
+	Versions []ModuleVersion `json:"versions"`
+}
+
+// ModuleVersion describes one available version of a module package.
+type ModuleVersion struct {
+	Version string `json:"version"`
+}
+
+// DecodeModuleVersionsResponse decodes a JSON response from the module
+// registry protocol's "versions" operation.
+func DecodeModuleVersionsResponse(r io.Reader) (*ModuleVersionsResponse, error) {
+	var ret ModuleVersionsResponse
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("invalid module versions response: %s", err)
+	}
+	return &ret, nil
+}
+
+// VersionsByPackage returns the versions listed in the response for each
+// module package, given the hostname of the registry that returned it.
+// The versions are in the order that the registry returned them.
+func (r *ModuleVersionsResponse) VersionsByPackage(host svchost.Hostname) (map[ModulePackage][]string, error) {
+	ret := make(map[ModulePackage][]string, len(r.Modules))
+	for _, entry := range r.Modules {
+		mod, err := ParseModuleSource(host.ForDisplay() + "/" + entry.Source)
+		if err != nil {
+			return nil, fmt.Errorf("registry returned invalid module source %q: %s", entry.Source, err)
+		}
+		if mod.Subdir != "" {
+			return nil, fmt.Errorf("registry returned module source %q with a subdirectory", entry.Source)
+		}
+		for _, v := range entry.Versions {
+			ret[mod.Package] = append(ret[mod.Package], v.Version)
+		}
+	}
+	return ret, nil
+}
+
+// ModuleDownloadHeader is the HTTP response header that a module registry
+// uses to return the location of a module package from the "download"
+// operation.
+const ModuleDownloadHeader = "X-Terraform-Get"
+
+// ModuleDownloadResponse is the response from the module registry
+// protocol's "download" operation.
+type ModuleDownloadResponse struct {
+	// Location is the address of the module package, in the syntax
+	// Terraform accepts for remote module sources, such as
+	// "git::https://example.com/vpc.git?ref=v1.2.0". It may also be a
+	// URL relative to the download URL.
+	Location string `json:"location"`
+}
+
+// DecodeModuleDownloadResponse decodes a response from the module registry
+// protocol's "download" operation, given its HTTP header and body.
+//
+// The location is taken from ModuleDownloadHeader if present, or otherwise
+// from the "location" property of a JSON body.
+func DecodeModuleDownloadResponse(header http.Header, body io.Reader) (*ModuleDownloadResponse, error) {
+	if location := header.Get(ModuleDownloadHeader); location != "" {
+		return &ModuleDownloadResponse{Location: location}, nil
+	}
+	var ret ModuleDownloadResponse
+	if err := json.NewDecoder(body).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("invalid module download response: %s", err)
+	}
+	if ret.Location == "" {
+		return nil, fmt.Errorf("invalid module download response: no location given")
+	}
+	return &ret, nil
+}
+
+// InstallableSource combines the download location in the receiver with
+// the module address that was requested, returning the source address of
+// the module in the syntax Terraform accepts for remote module sources.
+//
+// downloadURL is the URL that the response was returned from, which is
+// used to resolve a relative location. The module's subdirectory, if any,
+// is appended to any subdirectory already given in the location.
+func (r *ModuleDownloadResponse) InstallableSource(mod Module, downloadURL *url.URL) (string, error) {
+	location := r.Location
+	if strings.HasPrefix(location, "/") || strings.HasPrefix(location, "./") || strings.HasPrefix(location, "../") {
+		rel, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("registry returned invalid relative module location %q: %s", location, err)
+		}
+		location = downloadURL.ResolveReference(rel).String()
+	}
+	if mod.Subdir == "" {
+		return location, nil
+	}
+
+	pkg, subdir := sourceDirSubdir(location)
+	subdir = path.Join(subdir, mod.Subdir)
+	query := ""
+	if i := strings.Index(pkg, "?"); i != -1 {
+		pkg, query = pkg[:i], pkg[i:]
+	}
+	return pkg + "//" + subdir + query, nil
+}
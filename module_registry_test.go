@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModuleVersionsResponse(t *testing.T) {
+	body := `{"modules":[{"source":"hashicorp/consul/aws","versions":[{"version":"0.1.0"},{"version":"0.2.0"}]}]}`
+	resp, err := DecodeModuleVersionsResponse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := resp.VersionsByPackage("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[ModulePackage][]string{
+		MustParseModuleSource("example.com/hashicorp/consul/aws").Package: {"0.1.0", "0.2.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	resp = &ModuleVersionsResponse{Modules: []ModuleVersionsEntry{{Source: "github.com/hashicorp/consul"}}}
+	_, err = resp.VersionsByPackage(DefaultModuleRegistryHost)
+	if want := `registry returned invalid module source "github.com/hashicorp/consul": source address must have three more components after the hostname: the namespace, the name, and the target system`; err == nil || err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %s", err, want)
+	}
+}
+
+func TestModuleDownloadResponse(t *testing.T) {
+	downloadURL, err := url.Parse("https://example.com/v1/modules/hashicorp/consul/aws/0.1.0/download")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		header  http.Header
+		body    string
+		module  string
+		want    string
+		wantErr string
+	}{
+		"header": {
+			header: http.Header{ModuleDownloadHeader: {"git::https://example.com/consul.git?ref=v0.1.0"}},
+			module: "example.com/hashicorp/consul/aws",
+			want:   "git::https://example.com/consul.git?ref=v0.1.0",
+		},
+		"json body": {
+			body:   `{"location":"https://example.com/consul.tar.gz"}`,
+			module: "example.com/hashicorp/consul/aws",
+			want:   "https://example.com/consul.tar.gz",
+		},
+		"relative location": {
+			header: http.Header{ModuleDownloadHeader: {"../archive.tar.gz"}},
+			module: "example.com/hashicorp/consul/aws",
+			want:   "https://example.com/v1/modules/hashicorp/consul/aws/archive.tar.gz",
+		},
+		"module subdir": {
+			header: http.Header{ModuleDownloadHeader: {"git::https://example.com/consul.git?ref=v0.1.0"}},
+			module: "example.com/hashicorp/consul/aws//modules/consul-cluster",
+			want:   "git::https://example.com/consul.git//modules/consul-cluster?ref=v0.1.0",
+		},
+		"both subdirs": {
+			header: http.Header{ModuleDownloadHeader: {"https://example.com/monorepo.zip//consul"}},
+			module: "example.com/hashicorp/consul/aws//modules/consul-cluster",
+			want:   "https://example.com/monorepo.zip//consul/modules/consul-cluster",
+		},
+		"no location": {
+			body:    `{}`,
+			module:  "example.com/hashicorp/consul/aws",
+			wantErr: "invalid module download response: no location given",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := DecodeModuleDownloadResponse(test.header, strings.NewReader(test.body))
+			var got string
+			if err == nil {
+				got, err = resp.InstallableSource(MustParseModuleSource(test.module), downloadURL)
+			}
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ModuleSourceRemote is a module source address that refers to a package
+// retrieved directly from a remote location, rather than via a module
+// registry, using a source string with an explicit getter prefix such as
+// "git::https://example.com/network.git//modules/vpc?ref=v1.2.0".
+//
+// Terraform delegates the installation of these packages to the go-getter
+// library, and ModuleSourceRemote follows go-getter's address grammar, but
+// only for the getters listed in RemoteModuleGetters.
+type ModuleSourceRemote struct {
+	// Getter is the name of the go-getter getter that retrieves the
+	// package, given as a prefix followed by "::" in the source string.
+	Getter string
+
+	// URL is the location of the package, excluding the getter prefix,
+	// subdirectory, and query string.
+	URL *url.URL
+
+	// Subdir is the normalized path of the subdirectory within the package
+	// that contains the module, or empty for the package's root directory.
+	Subdir string
+
+	// Query holds the arguments from the query string of the source
+	// string, which go-getter interprets as options for the getter, such as
+	// "ref" for git.
+	Query url.Values
+
+	// Git holds the arguments from Query that the git getter understands,
+	// if Getter is "git", or is nil otherwise.
+	Git *GitSourceArguments
+}
+
+// RemoteModuleGetters lists the getter prefixes that
+// ParseModuleSourceRemote accepts.
+var RemoteModuleGetters = []string{"git", "hg", "http", "https", "s3", "gcs"}
+
+// scpLikeGitPattern matches the "scp-like" syntax for git repositories
+// accessed over SSH, such as "git@example.com:org/repo.git".
+var scpLikeGitPattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+@)?([A-Za-z0-9.-]+):([^/].*)$`)
+
+// ParseModuleSourceRemote parses a module source string with an explicit
+// getter prefix, such as "git::https://example.com/network.git", into a
+// ModuleSourceRemote.
+//
+// Source strings without a getter prefix, including the shorthand forms for
+// well-known hosts that go-getter detects, are rejected. As with go-getter,
+// git repositories may also be given in the "scp-like" syntax, such as
+// "git::git@example.com:org/repo.git", which is converted to an equivalent
+// ssh:// URL.
+func ParseModuleSourceRemote(raw string) (ModuleSourceRemote, error) {
+	var ret ModuleSourceRemote
+
+	getter, rest, ok := strings.Cut(raw, "::")
+	if !ok {
+		return ret, fmt.Errorf("remote module source address %q must start with a getter prefix, such as \"git::\"", raw)
+	}
+	if !isRemoteModuleGetter(getter) {
+		return ret, fmt.Errorf("unsupported getter %q in remote module source address; must be one of %s", getter, strings.Join(RemoteModuleGetters, ", "))
+	}
+	ret.Getter = getter
+
+	pkgRaw, givenSubdir := sourceDirSubdir(rest)
+	ret.Subdir = normalizeSubdir(givenSubdir)
+	if ret.Subdir == "." {
+		ret.Subdir = ""
+	}
+	if ret.Subdir == ".." || strings.HasPrefix(ret.Subdir, "../") {
+		return ModuleSourceRemote{}, fmt.Errorf("subdirectory path %q leads outside of the module package", ret.Subdir)
+	}
+
+	if getter == "git" {
+		if m := scpLikeGitPattern.FindStringSubmatch(pkgRaw); m != nil && !strings.Contains(pkgRaw, "://") {
+			pkgRaw = "ssh://" + m[1] + m[2] + "/" + m[3]
+		}
+	}
+
+	u, err := url.Parse(pkgRaw)
+	if err != nil {
+		return ModuleSourceRemote{}, fmt.Errorf("invalid package URL in remote module source address: %s", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return ModuleSourceRemote{}, fmt.Errorf("invalid package URL %q in remote module source address: must be an absolute URL with a hostname", pkgRaw)
+	}
+	if u.Fragment != "" {
+		return ModuleSourceRemote{}, fmt.Errorf("remote module source addresses may not include a fragment")
+	}
+	ret.Query = u.Query()
+	if len(ret.Query) == 0 {
+		ret.Query = nil
+	}
+	u.RawQuery = ""
+	u.ForceQuery = false
+	ret.URL = u
+
+	if getter == "git" {
+		ret.Git, err = parseGitSourceArguments(ret.Query)
+		if err != nil {
+			return ModuleSourceRemote{}, err
+		}
+	}
+
+	return ret, nil
+}
+
+func isRemoteModuleGetter(getter string) bool {
+	for _, known := range RemoteModuleGetters {
+		if getter == known {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageURL returns the location of the package including its query
+// string, as go-getter would retrieve it, but excluding the getter prefix
+// and subdirectory.
+func (s ModuleSourceRemote) PackageURL() string {
+	if s.URL == nil {
+		return ""
+	}
+	u := *s.URL
+	u.RawQuery = s.Query.Encode()
+	return u.String()
+}
+
+// String returns the source string for the address, with the getter
+// prefix, the package URL, the subdirectory, and the query string, in the
+// order that go-getter expects.
+func (s ModuleSourceRemote) String() string {
+	if s.URL == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(s.Getter)
+	b.WriteString("::")
+	b.WriteString(s.URL.String())
+	if s.Subdir != "" {
+		b.WriteString("//")
+		b.WriteString(s.Subdir)
+	}
+	if len(s.Query) != 0 {
+		b.WriteString("?")
+		b.WriteString(s.Query.Encode())
+	}
+	return b.String()
+}
+
+// ForDisplay returns the same result as String, because remote source
+// addresses have no shorter form.
+func (s ModuleSourceRemote) ForDisplay() string {
+	return s.String()
+}
+
+// IsZero returns true if the address is the zero value of
+// ModuleSourceRemote.
+func (s ModuleSourceRemote) IsZero() bool {
+	return s.Getter == "" && s.URL == nil && s.Subdir == "" && s.Query == nil && s.Git == nil
+}
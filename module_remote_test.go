@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// userinfoComparer allows comparing URLs with user information, which has
+// unexported fields.
+var userinfoComparer = cmp.Comparer(func(a, b *url.Userinfo) bool {
+	return a.String() == b.String()
+})
+
+func TestParseModuleSourceRemote(t *testing.T) {
+	tests := map[string]struct {
+		want       ModuleSourceRemote
+		wantString string
+		wantErr    string
+	}{
+		"git::https://example.com/network.git": {
+			want: ModuleSourceRemote{
+				Getter: "git",
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/network.git"},
+				Git:    &GitSourceArguments{},
+			},
+			wantString: "git::https://example.com/network.git",
+		},
+		"git::https://example.com/network.git//modules/vpc?ref=v1.2.0": {
+			want: ModuleSourceRemote{
+				Getter: "git",
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/network.git"},
+				Subdir: "modules/vpc",
+				Query:  url.Values{"ref": {"v1.2.0"}},
+				Git:    &GitSourceArguments{Ref: "v1.2.0"},
+			},
+			wantString: "git::https://example.com/network.git//modules/vpc?ref=v1.2.0",
+		},
+		"git::https://example.com/network.git?ref=v1.2.0//modules/./vpc": {
+			// As in go-getter, a "//" after the start of the query string is
+			// part of the query rather than a subdirectory separator.
+			want: ModuleSourceRemote{
+				Getter: "git",
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/network.git"},
+				Query:  url.Values{"ref": {"v1.2.0//modules/./vpc"}},
+				Git:    &GitSourceArguments{Ref: "v1.2.0//modules/./vpc"},
+			},
+			wantString: "git::https://example.com/network.git?ref=v1.2.0%2F%2Fmodules%2F.%2Fvpc",
+		},
+		"git::git@example.com:org/network.git": {
+			want: ModuleSourceRemote{
+				Getter: "git",
+				URL:    &url.URL{Scheme: "ssh", User: url.User("git"), Host: "example.com", Path: "/org/network.git"},
+				Git:    &GitSourceArguments{},
+			},
+			wantString: "git::ssh://git@example.com/org/network.git",
+		},
+		"git::ssh://git@example.com/network.git?ref=main&depth=1&sshkey=a2V5": {
+			want: ModuleSourceRemote{
+				Getter: "git",
+				URL:    &url.URL{Scheme: "ssh", User: url.User("git"), Host: "example.com", Path: "/network.git"},
+				Query:  url.Values{"ref": {"main"}, "depth": {"1"}, "sshkey": {"a2V5"}},
+				Git:    &GitSourceArguments{Ref: "main", Depth: 1, SSHKey: "a2V5"},
+			},
+			wantString: "git::ssh://git@example.com/network.git?depth=1&ref=main&sshkey=a2V5",
+		},
+		"git::https://example.com/network.git?depth=-1": {
+			wantErr: `invalid git depth "-1": must be a non-negative whole number`,
+		},
+		"git::https://example.com/network.git?sshkey=not+base64": {
+			wantErr: `invalid git sshkey: must be base64-encoded`,
+		},
+		"hg::http://example.com/vpc.hg": {
+			want: ModuleSourceRemote{
+				Getter: "hg",
+				URL:    &url.URL{Scheme: "http", Host: "example.com", Path: "/vpc.hg"},
+			},
+			wantString: "hg::http://example.com/vpc.hg",
+		},
+		"s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip": {
+			want: ModuleSourceRemote{
+				Getter: "s3",
+				URL:    &url.URL{Scheme: "https", Host: "s3-eu-west-1.amazonaws.com", Path: "/examplecorp-terraform-modules/vpc.zip"},
+			},
+			wantString: "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip",
+		},
+		"gcs::https://www.googleapis.com/storage/v1/modules/foomodule.zip//sub?archive=zip": {
+			want: ModuleSourceRemote{
+				Getter: "gcs",
+				URL:    &url.URL{Scheme: "https", Host: "www.googleapis.com", Path: "/storage/v1/modules/foomodule.zip"},
+				Subdir: "sub",
+				Query:  url.Values{"archive": {"zip"}},
+			},
+			wantString: "gcs::https://www.googleapis.com/storage/v1/modules/foomodule.zip//sub?archive=zip",
+		},
+		"https::https://example.com/vpc-module.zip": {
+			want: ModuleSourceRemote{
+				Getter: "https",
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/vpc-module.zip"},
+			},
+			wantString: "https::https://example.com/vpc-module.zip",
+		},
+		"https://example.com/vpc-module.zip": {
+			wantErr: `remote module source address "https://example.com/vpc-module.zip" must start with a getter prefix, such as "git::"`,
+		},
+		"svn::https://example.com/vpc": {
+			wantErr: `unsupported getter "svn" in remote module source address; must be one of git, hg, http, https, s3, gcs`,
+		},
+		"git::network.git": {
+			wantErr: `invalid package URL "network.git" in remote module source address: must be an absolute URL with a hostname`,
+		},
+		"git::https://example.com/network.git//../foo": {
+			wantErr: `subdirectory path "../foo" leads outside of the module package`,
+		},
+		"git::https://example.com/network.git#main": {
+			wantErr: `remote module source addresses may not include a fragment`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseModuleSourceRemote(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got, userinfoComparer); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := got.String(); got != test.wantString {
+				t.Errorf("wrong string\ngot:  %s\nwant: %s", got, test.wantString)
+			}
+
+			// The normalized string must parse to the same result.
+			again, err := ParseModuleSourceRemote(got.String())
+			if err != nil {
+				t.Fatalf("failed to parse normalized string: %s", err)
+			}
+			if diff := cmp.Diff(got, again, userinfoComparer); diff != "" {
+				t.Errorf("normalized string doesn't round-trip\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// hostedGitShorthand is the common representation of the shorthand module
+// source addresses for git hosting services, such as ModuleSourceGitHub.
+type hostedGitShorthand struct {
+	Owner      string
+	Repository string
+	Subdir     string
+	Ref        string
+	Query      url.Values
+}
+
+// parseHostedGitShorthand parses a shorthand module source address for
+// the git hosting service with the given name and hostname, in the form
+// "hostname/owner/repository", followed by an optional subdirectory and
+// query string.
+//
+// As in go-getter, a subdirectory may be given either after a "//"
+// separator or as additional path segments after the repository name.
+func parseHostedGitShorthand(raw, service, host string, ownerPattern, repoPattern *regexp.Regexp) (hostedGitShorthand, error) {
+	var ret hostedGitShorthand
+
+	if !strings.HasPrefix(raw, host+"/") {
+		return ret, fmt.Errorf("%s module source address %q must start with \"%s/\"", service, raw, host)
+	}
+	pkgRaw, givenSubdir := sourceDirSubdir(raw)
+
+	var query url.Values
+	if idx := strings.Index(pkgRaw, "?"); idx != -1 {
+		var err error
+		query, err = url.ParseQuery(pkgRaw[idx+1:])
+		if err != nil {
+			return ret, fmt.Errorf("invalid query string in %s module source address: %s", service, err)
+		}
+		pkgRaw = pkgRaw[:idx]
+	}
+
+	parts := strings.Split(pkgRaw, "/")
+	if len(parts) < 3 {
+		return ret, fmt.Errorf("%s module source address %q must have the form \"%s/owner/repository\"", service, raw, host)
+	}
+	if !ownerPattern.MatchString(parts[1]) {
+		return ret, fmt.Errorf("invalid %s owner %q", service, parts[1])
+	}
+	ret.Owner = parts[1]
+	repo := strings.TrimSuffix(parts[2], ".git")
+	if !repoPattern.MatchString(repo) {
+		return ret, fmt.Errorf("invalid %s repository name %q", service, parts[2])
+	}
+	ret.Repository = repo
+
+	subdir := strings.Join(parts[3:], "/")
+	if givenSubdir != "" {
+		if subdir != "" {
+			subdir += "/"
+		}
+		subdir += givenSubdir
+	}
+	ret.Subdir = normalizeSubdir(subdir)
+	if ret.Subdir == "." {
+		ret.Subdir = ""
+	}
+	if ret.Subdir == ".." || strings.HasPrefix(ret.Subdir, "../") {
+		return hostedGitShorthand{}, fmt.Errorf("subdirectory path %q leads outside of the module package", ret.Subdir)
+	}
+
+	if _, err := parseGitSourceArguments(query); err != nil {
+		return hostedGitShorthand{}, err
+	}
+	ret.Ref = query.Get("ref")
+	query.Del("ref")
+	if len(query) != 0 {
+		ret.Query = query
+	}
+	return ret, nil
+}
+
+// query returns all of the query arguments of the address, including
+// "ref".
+func (s hostedGitShorthand) query() url.Values {
+	ret := make(url.Values, len(s.Query)+1)
+	for k, v := range s.Query {
+		ret[k] = v
+	}
+	if s.Ref != "" {
+		ret.Set("ref", s.Ref)
+	}
+	return ret
+}
+
+// String returns the shorthand source string for the address on the given
+// host.
+func (s hostedGitShorthand) String(host string) string {
+	var b strings.Builder
+	b.WriteString(host)
+	b.WriteString("/")
+	b.WriteString(s.Owner)
+	b.WriteString("/")
+	b.WriteString(s.Repository)
+	if s.Subdir != "" {
+		b.WriteString("//")
+		b.WriteString(s.Subdir)
+	}
+	if query := s.query(); len(query) != 0 {
+		b.WriteString("?")
+		b.WriteString(query.Encode())
+	}
+	return b.String()
+}
+
+// Remote returns the equivalent address using an explicit git getter and
+// an HTTPS URL on the given host.
+func (s hostedGitShorthand) Remote(host string) ModuleSourceRemote {
+	ret := ModuleSourceRemote{
+		Getter: "git",
+		URL: &url.URL{
+			Scheme: "https",
+			Host:   host,
+			Path:   "/" + s.Owner + "/" + s.Repository + ".git",
+		},
+		Subdir: s.Subdir,
+	}
+	if query := s.query(); len(query) != 0 {
+		ret.Query = query
+	}
+	// The arguments were already validated during parsing, so an error
+	// here means that the caller has since changed them.
+	ret.Git, _ = parseGitSourceArguments(ret.Query)
+	return ret
+}
@@ -6,6 +6,7 @@ package tfaddr
 import (
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -148,6 +149,10 @@ func TestParseModuleSource(t *testing.T) {
 			input:   `---.com/HashiCorp/Consul/aws`,
 			wantErr: `invalid module registry hostname "---.com"; internationalized domain names must be given as direct unicode characters, not in punycode`,
 		},
+		"hostname too long": {
+			input:   strings.Repeat("a.", 127) + "com/HashiCorp/Consul/aws",
+			wantErr: `invalid module registry hostname "` + strings.Repeat("a.", 127) + `com"`,
+		},
 		"hostname with only one label": {
 			// This was historically forbidden in our initial implementation,
 			// so we keep it forbidden to avoid newly interpreting such
@@ -206,9 +211,29 @@ func TestParseModuleSource(t *testing.T) {
 			input:   "hashicorp/subnets/cidr//../nope",
 			wantErr: `subdirectory path "../nope" leads outside of the module package`,
 		},
+		"main registry implied, escaping subdir to parent only": {
+			input:   "hashicorp/subnets/cidr//foo/../..",
+			wantErr: `subdirectory path ".." leads outside of the module package`,
+		},
+		"public registry with backslash subdir": {
+			input:           `hashicorp/consul/aws//examples\foo`,
+			wantString:      `registry.terraform.io/hashicorp/consul/aws//examples/foo`,
+			wantForDisplay:  `hashicorp/consul/aws//examples/foo`,
+			wantForProtocol: `hashicorp/consul/aws`,
+		},
+		"public registry with mixed separators in subdir": {
+			input:           `hashicorp/consul/aws//examples\foo/bar\.\baz`,
+			wantString:      `registry.terraform.io/hashicorp/consul/aws//examples/foo/bar/baz`,
+			wantForDisplay:  `hashicorp/consul/aws//examples/foo/bar/baz`,
+			wantForProtocol: `hashicorp/consul/aws`,
+		},
+		"main registry implied, escaping backslash subdir": {
+			input:   `hashicorp/subnets/cidr//..\nope`,
+			wantErr: `subdirectory path "../nope" leads outside of the module package`,
+		},
 		"relative path without the needed prefix": {
 			input:   "boop/bloop",
-			wantErr: "a module registry source address must have either three or four slash-separated components",
+			wantErr: `a module registry source address must have either three or four slash-separated components; "boop/bloop" looks like a provider source address, which belongs in a required_providers block instead`,
 		},
 	}
 
@@ -249,5 +274,118 @@ func ExampleParseModuleSource() {
 		log.Fatal(err)
 	}
 	fmt.Printf("%#v", mAddr)
-	// Output: tfaddr.Module{Package:tfaddr.ModulePackage{Host:svchost.Hostname("registry.terraform.io"), Namespace:"hashicorp", Name:"consul", TargetSystem:"aws"}, Subdir:"modules/consul-cluster"}
+	// Output: tfaddr.MustParseModuleSource("registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster")
+}
+
+func FuzzParseModuleSource(f *testing.F) {
+	f.Add("hashicorp/consul/aws")
+	f.Add("hashicorp/consul/aws//modules/consul-cluster")
+	f.Add("Испытание.com:1234/HashiCorp/Consul/aws//Foo")
+	f.Add(`hashicorp/consul/aws//examples\foo`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		got, err := ParseModuleSource(input)
+		if err != nil {
+			return
+		}
+
+		// Any valid address must round-trip through its string
+		// representation.
+		again, err := ParseModuleSource(got.String())
+		if err != nil {
+			t.Fatalf("failed to re-parse %q from %q: %s", got.String(), input, err)
+		}
+		if again != got {
+			t.Fatalf("wrong result re-parsing %q from %q\ngot:  %#v\nwant: %#v", got.String(), input, again, got)
+		}
+	})
+}
+
+func TestModuleWithHost(t *testing.T) {
+	base := MustParseModuleSource("hashicorp/consul/aws//modules/foo")
+
+	got, err := base.WithHost("Example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := got.String(), "example.com/hashicorp/consul/aws//modules/foo"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := base.String(), "registry.terraform.io/hashicorp/consul/aws//modules/foo"; got != want {
+		t.Errorf("receiver was modified\ngot:  %s\nwant: %s", got, want)
+	}
+
+	_, err = base.WithHost("github.com")
+	if err == nil {
+		t.Fatalf("unexpected success with github.com")
+	}
+	if got, want := err.Error(), `invalid hostname "github.com": reserved for installing directly from version control repositories`; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestRelativeSubdir(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string
+		wantErr  string
+	}{
+		{"hashicorp/consul/aws", "hashicorp/consul/aws", ".", ""},
+		{"hashicorp/consul/aws", "hashicorp/consul/aws//modules/foo", "modules/foo", ""},
+		{"hashicorp/consul/aws//modules/foo", "hashicorp/consul/aws", "../..", ""},
+		{"hashicorp/consul/aws//modules/foo", "hashicorp/consul/aws//modules/bar", "../bar", ""},
+		{"hashicorp/consul/aws//modules/foo", "hashicorp/consul/aws//examples/foo", "../../examples/foo", ""},
+		{"hashicorp/consul/aws//modules", "hashicorp/consul/aws//modules-extra", "../modules-extra", ""},
+		{"hashicorp/consul/aws", "hashicorp/consul/azurerm", "", "hashicorp/consul/aws and hashicorp/consul/azurerm belong to different module packages"},
+		{"hashicorp/consul/aws", "example.com/hashicorp/consul/aws", "", "hashicorp/consul/aws and example.com/hashicorp/consul/aws belong to different module packages"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			from, to := MustParseModuleSource(test.from), MustParseModuleSource(test.to)
+			if got, want := SamePackage(from, to), test.wantErr == ""; got != want {
+				t.Errorf("wrong SamePackage result %t", got)
+			}
+
+			got, err := RelativeSubdir(from, to)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestModuleShortestForm(t *testing.T) {
+	tests := map[string]string{
+		"hashicorp/consul/aws":                               "hashicorp/consul/aws",
+		"registry.terraform.io/hashicorp/consul/aws":         "hashicorp/consul/aws",
+		"Example.com/HashiCorp/Consul/aws":                   "example.com/HashiCorp/Consul/aws",
+		"hashicorp/consul/aws//./modules//consul-cluster/":   "hashicorp/consul/aws//modules/consul-cluster",
+		"hashicorp/consul/aws//modules/../examples\\default": "hashicorp/consul/aws//examples/default",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			addr := MustParseModuleSource(input)
+			got := addr.ShortestForm()
+			if got != want {
+				t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+			if again := MustParseModuleSource(got); again != addr {
+				t.Errorf("result doesn't round-trip\ngot:  %#v\nwant: %#v", again, addr)
+			}
+		})
+	}
 }
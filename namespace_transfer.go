@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// NamespaceTransfer records that the providers in one namespace on a
+// registry moved to another namespace on the same registry, such as after
+// a company was renamed or acquired, so that callers can maintain such
+// handovers as data rather than code.
+type NamespaceTransfer struct {
+	Host         svchost.Hostname `json:"host"`
+	OldNamespace string           `json:"old_namespace"`
+	NewNamespace string           `json:"new_namespace"`
+
+	// EffectiveDate is when the transfer took effect. Addresses are only
+	// rewritten for transfers that took effect at or before the time given
+	// to NamespaceTransfers.Rewrite.
+	EffectiveDate time.Time `json:"effective_date"`
+}
+
+func (t NamespaceTransfer) String() string {
+	return fmt.Sprintf("%s/%s moved to %s/%s on %s", t.Host.ForDisplay(), t.OldNamespace, t.Host.ForDisplay(), t.NewNamespace, t.EffectiveDate.Format("2006-01-02"))
+}
+
+// Validate returns an error if either namespace is not a valid, normalized
+// provider namespace, or if the two namespaces are the same.
+func (t NamespaceTransfer) Validate() error {
+	if err := ProviderNamespace(t.OldNamespace).Validate(); err != nil {
+		return fmt.Errorf("invalid old namespace %q: %s", t.OldNamespace, err)
+	}
+	if err := ProviderNamespace(t.NewNamespace).Validate(); err != nil {
+		return fmt.Errorf("invalid new namespace %q: %s", t.NewNamespace, err)
+	}
+	if t.OldNamespace == t.NewNamespace {
+		return fmt.Errorf("namespace %q can't be transferred to itself", t.OldNamespace)
+	}
+	return nil
+}
+
+// Rewrite returns the given address with its namespace replaced by
+// NewNamespace and true, if the address is in OldNamespace on Host, or
+// returns the address unchanged and false otherwise.
+//
+// Rewrite doesn't consider EffectiveDate.
+func (t NamespaceTransfer) Rewrite(p Provider) (Provider, bool) {
+	if p.Hostname != t.Host || p.Namespace != t.OldNamespace {
+		return p, false
+	}
+	p.Namespace = t.NewNamespace
+	return p, true
+}
+
+// NamespaceTransfers is a set of namespace transfers, which may form
+// chains where a namespace is transferred more than once.
+type NamespaceTransfers []NamespaceTransfer
+
+// Validate returns an error if any of the transfers is invalid, or if the
+// transfers include a cycle, where a namespace eventually moves back to
+// itself.
+func (ts NamespaceTransfers) Validate() error {
+	for _, t := range ts {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("invalid transfer of %s/%s: %s", t.Host.ForDisplay(), t.OldNamespace, err)
+		}
+	}
+	for _, t := range ts {
+		seen := map[string]bool{t.OldNamespace: true}
+		ns := t.NewNamespace
+		for {
+			if seen[ns] {
+				return fmt.Errorf("namespace transfers for %s form a cycle involving %q", t.Host.ForDisplay(), ns)
+			}
+			seen[ns] = true
+			next, ok := ts.from(t.Host, ns)
+			if !ok {
+				break
+			}
+			ns = next.NewNamespace
+		}
+	}
+	return nil
+}
+
+// from returns the first transfer out of the given namespace.
+func (ts NamespaceTransfers) from(host svchost.Hostname, namespace string) (NamespaceTransfer, bool) {
+	for _, t := range ts {
+		if t.Host == host && t.OldNamespace == namespace {
+			return t, true
+		}
+	}
+	return NamespaceTransfer{}, false
+}
+
+// Rewrite returns the given address after applying each transfer that took
+// effect at or before the given time, in order of their effective dates,
+// so that an address moved more than once ends up in its latest namespace.
+//
+// The transfers must have been validated with Validate, to rule out
+// cycles.
+func (ts NamespaceTransfers) Rewrite(p Provider, at time.Time) Provider {
+	for _, t := range ts.effective(at) {
+		p, _ = t.Rewrite(p)
+	}
+	return p
+}
+
+// RewriteAll returns a new slice containing the result of Rewrite for each
+// of the given addresses.
+func (ts NamespaceTransfers) RewriteAll(providers []Provider, at time.Time) []Provider {
+	effective := ts.effective(at)
+	ret := make([]Provider, len(providers))
+	for i, p := range providers {
+		for _, t := range effective {
+			p, _ = t.Rewrite(p)
+		}
+		ret[i] = p
+	}
+	return ret
+}
+
+// effective returns the transfers that took effect at or before the given
+// time, sorted by effective date.
+func (ts NamespaceTransfers) effective(at time.Time) []NamespaceTransfer {
+	var ret []NamespaceTransfer
+	for _, t := range ts {
+		if !t.EffectiveDate.After(at) {
+			ret = append(ret, t)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].EffectiveDate.Before(ret[j].EffectiveDate)
+	})
+	return ret
+}
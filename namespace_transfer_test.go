@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNamespaceTransfers(t *testing.T) {
+	date := func(s string) time.Time {
+		ret, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+	transfers := NamespaceTransfers{
+		// Given out of order to check that they're applied by date.
+		{Host: DefaultProviderRegistryHost, OldNamespace: "newco", NewNamespace: "megacorp", EffectiveDate: date("2024-06-01")},
+		{Host: DefaultProviderRegistryHost, OldNamespace: "oldco", NewNamespace: "newco", EffectiveDate: date("2023-01-01")},
+	}
+	if err := transfers.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	providers := []Provider{
+		MustParseProviderSource("oldco/widget"),
+		MustParseProviderSource("newco/gadget"),
+		MustParseProviderSource("example.com/oldco/widget"),
+		MustParseProviderSource("hashicorp/aws"),
+	}
+
+	tests := map[string]struct {
+		at   time.Time
+		want []string
+	}{
+		"before any transfer": {
+			date("2022-01-01"),
+			[]string{"oldco/widget", "newco/gadget", "example.com/oldco/widget", "hashicorp/aws"},
+		},
+		"after the first transfer": {
+			date("2023-01-01"),
+			[]string{"newco/widget", "newco/gadget", "example.com/oldco/widget", "hashicorp/aws"},
+		},
+		"after both transfers": {
+			date("2025-01-01"),
+			[]string{"megacorp/widget", "megacorp/gadget", "example.com/oldco/widget", "hashicorp/aws"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, p := range transfers.RewriteAll(providers, test.at) {
+				got = append(got, p.ForDisplay())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got, want := transfers.Rewrite(providers[0], test.at).ForDisplay(), test.want[0]; got != want {
+				t.Errorf("wrong result from Rewrite %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNamespaceTransfersValidate(t *testing.T) {
+	tests := map[string]struct {
+		transfers NamespaceTransfers
+		wantErr   string
+	}{
+		"invalid namespace": {
+			NamespaceTransfers{{Host: DefaultProviderRegistryHost, OldNamespace: "old co", NewNamespace: "new"}},
+			`invalid transfer of registry.terraform.io/old co: invalid old namespace "old co": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"self transfer": {
+			NamespaceTransfers{{Host: DefaultProviderRegistryHost, OldNamespace: "a", NewNamespace: "a"}},
+			`invalid transfer of registry.terraform.io/a: namespace "a" can't be transferred to itself`,
+		},
+		"cycle": {
+			NamespaceTransfers{
+				{Host: DefaultProviderRegistryHost, OldNamespace: "a", NewNamespace: "b"},
+				{Host: DefaultProviderRegistryHost, OldNamespace: "b", NewNamespace: "a"},
+			},
+			`namespace transfers for registry.terraform.io form a cycle involving "a"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.transfers.Validate()
+			if err == nil {
+				t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+			}
+			if got := err.Error(); got != test.wantErr {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+			}
+		})
+	}
+}
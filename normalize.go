@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+)
+
+// Edit is a replacement of one span of a source string with new text.
+type Edit struct {
+	Span        Span
+	Replacement string
+}
+
+// NormalizeWithDiff parses the given module registry source string and
+// returns its canonical form, as returned by Module.ForDisplay, along with
+// the edits that transform the given string into the canonical form.
+//
+// The edits are minimal in that each covers only a segment that differs
+// from its canonical form, and so a tool that rewrites source strings in
+// a file can apply them without disturbing the rest of the string. The
+// edits don't overlap and are in order of position. The result is nil if
+// the given string is already canonical.
+//
+// NormalizeWithDiff returns an error if the given string is not a valid
+// module registry source string.
+func NormalizeWithDiff(input string) (string, []Edit, error) {
+	return Parser{}.NormalizeWithDiff(input)
+}
+
+// NormalizeWithDiff is like the package-level function of the same name,
+// but applies the rules configured in the receiver, and so the canonical
+// form is as returned by Parser.ModuleShortestForm, omitting the
+// receiver's default module hostname.
+func (p Parser) NormalizeWithDiff(input string) (string, []Edit, error) {
+	mod, spans, err := p.ParseModuleSourceSpans(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var edits []Edit
+	if spans.Hostname.Len() > 0 {
+		switch {
+		case mod.Package.Host == p.defaultModuleHost():
+			// The default hostname is omitted in the canonical form,
+			// along with the slash that follows it.
+			edits = append(edits, Edit{Span: Span{spans.Hostname.Start, spans.Namespace.Start}})
+		case spans.Hostname.In(input) != mod.Package.Host.ForDisplay():
+			edits = append(edits, Edit{Span: spans.Hostname, Replacement: mod.Package.Host.ForDisplay()})
+		}
+	}
+	if strings.Contains(input, "//") {
+		switch {
+		case mod.Subdir == "":
+			// A subdirectory that normalizes to nothing is omitted,
+			// along with the "//" separator.
+			edits = append(edits, Edit{Span: Span{spans.Subdir.Start - 2, spans.Subdir.End}})
+		case spans.Subdir.In(input) != mod.Subdir:
+			edits = append(edits, Edit{Span: spans.Subdir, Replacement: mod.Subdir})
+		}
+	}
+	return p.ModuleShortestForm(mod), edits, nil
+}
+
+// ApplyEdits returns the result of applying the given edits to the given
+// string. The edits must not overlap and must be in order of position, as
+// returned by NormalizeWithDiff.
+func ApplyEdits(input string, edits []Edit) string {
+	var buf strings.Builder
+	pos := 0
+	for _, edit := range edits {
+		buf.WriteString(input[pos:edit.Span.Start])
+		buf.WriteString(edit.Replacement)
+		pos = edit.Span.End
+	}
+	buf.WriteString(input[pos:])
+	return buf.String()
+}
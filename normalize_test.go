@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeWithDiff(t *testing.T) {
+	tests := map[string]struct {
+		want      string
+		wantEdits []Edit
+	}{
+		"hashicorp/consul/aws": {
+			want: "hashicorp/consul/aws",
+		},
+		"registry.terraform.io/hashicorp/consul/aws": {
+			want:      "hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 22}}},
+		},
+		"Registry.Terraform.IO/hashicorp/consul/aws": {
+			want:      "hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 22}}},
+		},
+		"Example.COM/hashicorp/consul/aws": {
+			want:      "example.com/hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 11}, Replacement: "example.com"}},
+		},
+		"ИСПЫТАНИЕ.com/hashicorp/consul/aws": {
+			want:      "испытание.com/hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 22}, Replacement: "испытание.com"}},
+		},
+		"hashicorp/consul/aws//modules/./consul-cluster/": {
+			want:      "hashicorp/consul/aws//modules/consul-cluster",
+			wantEdits: []Edit{{Span: Span{22, 47}, Replacement: "modules/consul-cluster"}},
+		},
+		"hashicorp/consul/aws//": {
+			want:      "hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{20, 22}}},
+		},
+		"example.com:443/hashicorp/consul/aws": {
+			want:      "example.com/hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 15}, Replacement: "example.com"}},
+		},
+		"registry.terraform.io/hashicorp/consul/aws//modules//x": {
+			want: "hashicorp/consul/aws//modules/x",
+			wantEdits: []Edit{
+				{Span: Span{0, 22}},
+				{Span: Span{44, 54}, Replacement: "modules/x"},
+			},
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, edits, err := NormalizeWithDiff(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong canonical form %q; want %q", got, test.want)
+			}
+			if diff := cmp.Diff(test.wantEdits, edits); diff != "" {
+				t.Errorf("wrong edits\n%s", diff)
+			}
+			if applied := ApplyEdits(input, edits); applied != got {
+				t.Errorf("applying edits produced %q; want %q", applied, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeWithDiffInvalid(t *testing.T) {
+	if _, _, err := NormalizeWithDiff("./local"); err == nil {
+		t.Error("unexpected success for local path")
+	}
+}
+
+func TestParserNormalizeWithDiff(t *testing.T) {
+	p, err := EnterpriseDefaults("tfe.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		want      string
+		wantEdits []Edit
+	}{
+		"hashicorp/consul/aws": {
+			want: "hashicorp/consul/aws",
+		},
+		"TFE.example.com/hashicorp/consul/aws": {
+			want:      "hashicorp/consul/aws",
+			wantEdits: []Edit{{Span: Span{0, 16}}},
+		},
+		"registry.terraform.io/hashicorp/consul/aws": {
+			want: "registry.terraform.io/hashicorp/consul/aws",
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, edits, err := p.NormalizeWithDiff(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong canonical form %q; want %q", got, test.want)
+			}
+			if diff := cmp.Diff(test.wantEdits, edits); diff != "" {
+				t.Errorf("wrong edits\n%s", diff)
+			}
+			if applied := ApplyEdits(input, edits); applied != got {
+				t.Errorf("applying edits produced %q; want %q", applied, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"time"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// ParseEvent describes a single call to one of the parsing methods of a
+// Parser, as reported to a ParseObserver.
+type ParseEvent struct {
+	// Kind is the kind of address the parser attempted to parse, which is
+	// either ProviderKind or ModuleRegistryKind.
+	Kind AddressKind
+
+	// Duration is how long the parse took.
+	Duration time.Duration
+
+	// Host is the registry hostname of the parsed address, which may have
+	// been implied by omission, or empty if parsing failed.
+	Host svchost.Hostname
+
+	// Err is the error returned by the parser, or nil if parsing succeeded.
+	Err error
+}
+
+// ParseObserver is implemented by callers that want to be notified of each
+// parse performed by a Parser, such as to record metrics.
+//
+// ObserveParse is called synchronously before the parsing method returns,
+// so it should return quickly. It may be called concurrently if the Parser
+// is used concurrently.
+type ParseObserver interface {
+	ObserveParse(ev ParseEvent)
+}
+
+// ParseObserverFunc is an adapter to allow the use of an ordinary function
+// as a ParseObserver.
+type ParseObserverFunc func(ev ParseEvent)
+
+// ObserveParse calls f(ev).
+func (f ParseObserverFunc) ObserveParse(ev ParseEvent) {
+	f(ev)
+}
+
+// observe reports a parse to the receiver's Observer.
+func (p Parser) observe(kind AddressKind, start time.Time, host svchost.Hostname, err error) {
+	ev := ParseEvent{
+		Kind:     kind,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if err == nil {
+		ev.Host = host
+	}
+	p.Observer.ObserveParse(ev)
+}
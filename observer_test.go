@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestParserObserver(t *testing.T) {
+	var events []ParseEvent
+	parser := Parser{
+		Observer: ParseObserverFunc(func(ev ParseEvent) {
+			if ev.Duration < 0 {
+				t.Errorf("negative duration %s", ev.Duration)
+			}
+			events = append(events, ev)
+		}),
+	}
+
+	parser.ParseProviderSource("hashicorp/aws")
+	parser.ParseModuleSource("example.com/hashicorp/consul/aws")
+	parser.ParseModuleSource("github.com/hashicorp/consul/aws")
+
+	type summary struct {
+		Kind AddressKind
+		Host svchost.Hostname
+		Err  bool
+	}
+	var got []summary
+	for _, ev := range events {
+		got = append(got, summary{ev.Kind, ev.Host, ev.Err != nil})
+	}
+	want := []summary{
+		{ProviderKind, DefaultProviderRegistryHost, false},
+		{ModuleRegistryKind, "example.com", false},
+		{ModuleRegistryKind, "", true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong events\n%s", diff)
+	}
+}
+
+func TestParserObserverParseAny(t *testing.T) {
+	var events []ParseEvent
+	parser := Parser{
+		Observer: ParseObserverFunc(func(ev ParseEvent) {
+			events = append(events, ev)
+		}),
+	}
+
+	parser.DetectSourceKind("hashicorp/aws")
+	if len(events) != 0 {
+		t.Errorf("DetectSourceKind notified the observer of %d events", len(events))
+	}
+
+	if _, err := parser.ParseAny("hashicorp/aws"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("ParseAny notified the observer of %d events; want 1", len(events))
+	}
+	if ev := events[0]; ev.Kind != ProviderKind || ev.Err != nil {
+		t.Errorf("wrong event kind %s with error %v", ev.Kind, ev.Err)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// Parser allows customizing the rules used when parsing provider and module
+// source addresses, for callers that need to enforce additional policy on
+// top of the syntax rules that Terraform itself uses.
+//
+// The zero value of Parser applies exactly the same rules as the
+// package-level parsing functions, such as ParseProviderSource and
+// ParseModuleSource.
+//
+// The work done to parse an address is bounded by a linear function of the
+// length of the given string, and the parsing functions never panic
+// regardless of input. Both properties are covered by fuzz tests. Callers
+// parsing strings from untrusted sources, such as a registry server
+// handling requests, should nonetheless set MaxSourceLength, or use
+// SafeParseProviderSource and SafeParseModuleSource, to also bound the
+// absolute amount of work and memory used per address.
+//
+// The package-level defaults, such as DefaultProviderRegistryHost, are
+// constants, so there is no global configuration to change. Instead, each
+// caller that needs different rules uses its own Parser, such as one per
+// tenant in a multi-tenant service. The parsing methods never modify the
+// Parser or the slices it refers to, so a single Parser can be used by
+// multiple goroutines concurrently, as long as its Trace, Localize, and
+// Observer callbacks are also safe for concurrent use and the Parser is
+// not modified while in use.
+type Parser struct {
+	// AllowedHosts, if non-empty, lists the only registry hostnames that
+	// parsed addresses may use. An address with any other hostname, whether
+	// written explicitly or implied by omission, is rejected with a
+	// *HostPolicyError.
+	//
+	// Hostnames must be given in their normalized form, as returned by
+	// svchost.ForComparison.
+	AllowedHosts []svchost.Hostname
+
+	// DeniedHosts lists registry hostnames that parsed addresses may not
+	// use. An address with one of these hostnames is rejected with a
+	// *HostPolicyError even if the hostname also appears in AllowedHosts.
+	//
+	// Hostnames must be given in their normalized form, as returned by
+	// svchost.ForComparison.
+	DeniedHosts []svchost.Hostname
+
+	// MaxSourceLength, if greater than zero, is the maximum length in bytes
+	// of a source string. Longer strings are rejected before doing any other
+	// work.
+	MaxSourceLength int
+
+	// DefaultProviderHost, if set, is the registry hostname used for
+	// provider addresses that don't specify one, instead of
+	// DefaultProviderRegistryHost.
+	//
+	// Because legacy provider addresses are only valid for
+	// DefaultProviderRegistryHost, setting this to any other hostname
+	// causes addresses like "-/aws" to be rejected.
+	DefaultProviderHost svchost.Hostname
+
+	// DefaultModuleHost, if set, is the registry hostname used for module
+	// registry addresses that don't specify one, instead of
+	// DefaultModuleRegistryHost.
+	//
+	// Module.ForDisplay only omits DefaultModuleRegistryHost, so addresses
+	// using a different default host are still displayed with their
+	// hostname.
+	DefaultModuleHost svchost.Hostname
+
+	// AllowSingleLabelModuleHosts, if set, allows module registry addresses
+	// to use a hostname without any dots, such as "registry:8443", as long
+	// as it includes an explicit port number. This is for registries on
+	// private networks that are reachable only by a single-label name.
+	//
+	// A port number is still required so that an address like
+	// "foo/bar/baz/qux" continues to be rejected rather than being taken as
+	// a registry address on a host named "foo". Terraform itself doesn't
+	// accept these addresses, so this should be used only by tools that
+	// install modules in some other way.
+	AllowSingleLabelModuleHosts bool
+
+	// AllowPunycodeHosts, if set, allows hostnames in addresses to be given
+	// in their ASCII-compatible "punycode" form, such as
+	// "xn--80akhbyknj4f.example.com", which Terraform otherwise rejects in
+	// favor of the Unicode form. The result is the same as if the hostname
+	// had been given in Unicode form.
+	//
+	// This is for addresses that have passed through systems that only
+	// support ASCII, such as some DNS and certificate tooling.
+	AllowPunycodeHosts bool
+
+	// HostProfiles describes the conventions of particular registry hosts,
+	// overriding the results of DefaultHostProfile for those hosts. See
+	// HostProfile for details.
+	//
+	// Hostnames must be given in their normalized form, as returned by
+	// svchost.ForComparison.
+	HostProfiles map[svchost.Hostname]HostProfile
+
+	// Limits describes additional restrictions on the segments of parsed
+	// addresses. The zero value applies no additional restrictions.
+	Limits Limits
+
+	// Trace, if set, is called with a short description of each significant
+	// decision made while parsing, such as how the given string was split
+	// into segments, which defaults were used, and which normalizations
+	// were applied.
+	//
+	// This is intended for diagnosing why a particular string was parsed
+	// unexpectedly. The messages are for humans and may change in future
+	// versions, so callers should not try to interpret them.
+	Trace func(msg string)
+
+	// Localize, if set, is called with each error that the parser is about
+	// to return, and should return a message describing that error in the
+	// user's language, or an empty string to keep the original message.
+	//
+	// A translated error is returned as a *LocalizedError wrapping the
+	// original error, so callers can still use errors.As to access the
+	// details of the original error, such as the segment of a
+	// *SegmentError.
+	Localize func(err error) string
+
+	// Observer, if set, is notified after each call to ParseProviderSource
+	// or ParseModuleSource, including calls made internally by other
+	// parsing methods, so that callers can record metrics about the
+	// addresses they handle. DetectSourceKind doesn't notify it, so
+	// ParseAny reports only the parse of the kind it detects.
+	Observer ParseObserver
+
+	// DetectSwappedSegments, if set, causes ParseModuleSourceLenient to
+	// report a *SwappedSegmentsError when the namespace, name, and target
+	// system of a module address seem to have been written in the wrong
+	// order, such as in "aws/terraform-aws-modules/vpc".
+	//
+	// The check is a heuristic based on the list of known target systems,
+	// so it may report addresses that are valid and correct. For that
+	// reason it only affects lenient parsing, where it may report an
+	// error for an address that the strict parser accepts.
+	DetectSwappedSegments bool
+}
+
+// MaxSafeSourceLength is the maximum length in bytes of a source string
+// accepted by SafeParseProviderSource and SafeParseModuleSource.
+//
+// This is long enough for any address that would be reasonable to write
+// in a Terraform configuration, including a module subdirectory path.
+const MaxSafeSourceLength = 512
+
+// SafeParseProviderSource is like ParseProviderSource but rejects source
+// strings longer than MaxSafeSourceLength, for situations where the given
+// string comes from an untrusted source.
+func SafeParseProviderSource(str string) (Provider, error) {
+	return Parser{MaxSourceLength: MaxSafeSourceLength}.ParseProviderSource(str)
+}
+
+// SafeParseModuleSource is like ParseModuleSource but rejects source
+// strings longer than MaxSafeSourceLength, for situations where the given
+// string comes from an untrusted source.
+func SafeParseModuleSource(raw string) (Module, error) {
+	return Parser{MaxSourceLength: MaxSafeSourceLength}.ParseModuleSource(raw)
+}
+
+func (p Parser) defaultProviderHost() svchost.Hostname {
+	if p.DefaultProviderHost != "" {
+		return p.DefaultProviderHost
+	}
+	return DefaultProviderRegistryHost
+}
+
+func (p Parser) defaultModuleHost() svchost.Hostname {
+	if p.DefaultModuleHost != "" {
+		return p.DefaultModuleHost
+	}
+	return DefaultModuleRegistryHost
+}
+
+// ProviderShortestForm is like Provider.ShortestForm, but omits the
+// receiver's default provider hostname rather than
+// DefaultProviderRegistryHost, so that the result parses to the same
+// address with the receiver.
+func (p Parser) ProviderShortestForm(addr Provider) string {
+	if addr.IsZero() {
+		panic("called ProviderShortestForm with zero-value addrs.Provider")
+	}
+	switch {
+	case addr.Hostname != p.defaultProviderHost():
+		return addr.Hostname.ForDisplay() + "/" + addr.Namespace + "/" + addr.Type
+	case addr.Namespace == UnknownProviderNamespace:
+		return addr.Type
+	default:
+		return addr.Namespace + "/" + addr.Type
+	}
+}
+
+// ModuleShortestForm is like Module.ShortestForm, but omits the receiver's
+// default module hostname rather than DefaultModuleRegistryHost, so that
+// the result parses to the same address with the receiver.
+func (p Parser) ModuleShortestForm(addr Module) string {
+	ret := addr.Package.ForRegistryProtocol()
+	if addr.Package.Host != p.defaultModuleHost() {
+		ret = addr.Package.Host.ForDisplay() + "/" + ret
+	}
+	if addr.Subdir != "" {
+		ret += "//" + addr.Subdir
+	}
+	return ret
+}
+
+// checkLength returns an error if the given source string is longer than
+// permitted by the receiver's MaxSourceLength.
+func (p Parser) checkLength(raw string) error {
+	if p.MaxSourceLength > 0 {
+		p.tracef("checking length %d against the limit of %d bytes", len(raw), p.MaxSourceLength)
+	}
+	if p.MaxSourceLength > 0 && len(raw) > p.MaxSourceLength {
+		return fmt.Errorf("must be no longer than %d bytes", p.MaxSourceLength)
+	}
+	return nil
+}
+
+// checkInterpolation returns an *InterpolationError if the given source
+// string contains a template sequence.
+func (p Parser) checkInterpolation(raw string) error {
+	for i := 0; i+1 < len(raw); i++ {
+		if (raw[i] == '$' || raw[i] == '%') && raw[i+1] == '{' {
+			p.tracef("found template sequence %q at offset %d", raw[i:i+2], i)
+			return &InterpolationError{Source: raw, Offset: i}
+		}
+	}
+	return nil
+}
+
+// tracef formats a message and passes it to the receiver's Trace function,
+// if any.
+func (p Parser) tracef(format string, args ...any) {
+	if p.Trace != nil {
+		p.Trace(fmt.Sprintf(format, args...))
+	}
+}
+
+// checkHostname returns a *HostPolicyError if the given hostname is not
+// permitted by the receiver's host policy.
+func (p Parser) checkHostname(host svchost.Hostname) error {
+	if len(p.DeniedHosts) != 0 || len(p.AllowedHosts) != 0 {
+		p.tracef("checking hostname %s against the host policy", host.ForDisplay())
+	}
+	for _, denied := range p.DeniedHosts {
+		if host == denied {
+			return &HostPolicyError{Hostname: host, Denied: true}
+		}
+	}
+	if len(p.AllowedHosts) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedHosts {
+		if host == allowed {
+			return nil
+		}
+	}
+	return &HostPolicyError{Hostname: host}
+}
+
+// localize returns the given error with its message translated by the
+// receiver's Localize function, if any.
+func (p Parser) localize(err error) error {
+	if p.Localize == nil {
+		return err
+	}
+	msg := p.Localize(err)
+	if msg == "" {
+		return err
+	}
+	return &LocalizedError{Message: msg, Err: err}
+}
+
+// allowsSingleLabelModuleHost returns true if the given module registry
+// hostname has no dots but is acceptable under the receiver's
+// AllowSingleLabelModuleHosts setting.
+func (p Parser) allowsSingleLabelModuleHost(host svchost.Hostname) bool {
+	name := host.String()
+	return p.AllowSingleLabelModuleHosts && !strings.Contains(name, ".") && strings.Contains(name, ":")
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// The environment variables that NewParserFromEnv reads.
+const (
+	// EnvDefaultHost is the name of an environment variable giving the
+	// registry hostname to use for both provider and module addresses that
+	// don't specify one.
+	EnvDefaultHost = "TFADDR_DEFAULT_HOST"
+
+	// EnvAllowedHosts is the name of an environment variable giving a
+	// comma-separated list of the only registry hostnames that addresses
+	// may use.
+	EnvAllowedHosts = "TFADDR_ALLOWED_HOSTS"
+
+	// EnvDeniedHosts is the name of an environment variable giving a
+	// comma-separated list of registry hostnames that addresses may not use.
+	EnvDeniedHosts = "TFADDR_DENIED_HOSTS"
+
+	// EnvStrict is the name of an environment variable which, if set to a
+	// true value such as "1" or "true", limits source strings to
+	// MaxSafeSourceLength bytes.
+	EnvStrict = "TFADDR_STRICT"
+)
+
+// NewParserFromEnv returns a Parser configured from the environment
+// variables EnvDefaultHost, EnvAllowedHosts, EnvDeniedHosts, and EnvStrict,
+// so that command line tools can be reconfigured for a private registry
+// without code changes.
+//
+// Any variable that is unset or empty leaves the corresponding setting at
+// its default. Hostnames are given as they would be written in a source
+// string. NewParserFromEnv returns an error if any of the variables has an
+// invalid value.
+func NewParserFromEnv() (Parser, error) {
+	var p Parser
+
+	if raw := os.Getenv(EnvDefaultHost); raw != "" {
+		host, err := parseHostname(raw)
+		if err != nil {
+			return Parser{}, fmt.Errorf("invalid %s %q: %s", EnvDefaultHost, raw, err)
+		}
+		p.DefaultProviderHost = host
+		p.DefaultModuleHost = host
+	}
+
+	var err error
+	if p.AllowedHosts, err = hostsFromEnv(EnvAllowedHosts); err != nil {
+		return Parser{}, err
+	}
+	if p.DeniedHosts, err = hostsFromEnv(EnvDeniedHosts); err != nil {
+		return Parser{}, err
+	}
+
+	if raw := os.Getenv(EnvStrict); raw != "" {
+		strict, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Parser{}, fmt.Errorf("invalid %s %q: must be a boolean value such as \"true\" or \"false\"", EnvStrict, raw)
+		}
+		if strict {
+			p.MaxSourceLength = MaxSafeSourceLength
+		}
+	}
+
+	return p, nil
+}
+
+// hostsFromEnv parses a comma-separated list of hostnames from the given
+// environment variable.
+func hostsFromEnv(name string) ([]svchost.Hostname, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil, nil
+	}
+	var ret []svchost.Hostname
+	for _, given := range strings.Split(raw, ",") {
+		given = strings.TrimSpace(given)
+		if given == "" {
+			continue
+		}
+		host, err := parseHostname(given)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostname %q in %s: %s", given, name, err)
+		}
+		ret = append(ret, host)
+	}
+	return ret, nil
+}
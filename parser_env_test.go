@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestNewParserFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		env     map[string]string
+		want    Parser
+		wantErr string
+	}{
+		"empty": {
+			env:  nil,
+			want: Parser{},
+		},
+		"everything": {
+			env: map[string]string{
+				EnvDefaultHost:  "Registry.Example.com",
+				EnvAllowedHosts: "registry.example.com, испытание.com,",
+				EnvDeniedHosts:  "registry.terraform.io",
+				EnvStrict:       "true",
+			},
+			want: Parser{
+				DefaultProviderHost: "registry.example.com",
+				DefaultModuleHost:   "registry.example.com",
+				AllowedHosts:        []svchost.Hostname{"registry.example.com", "xn--80akhbyknj4f.com"},
+				DeniedHosts:         []svchost.Hostname{"registry.terraform.io"},
+				MaxSourceLength:     MaxSafeSourceLength,
+			},
+		},
+		"not strict": {
+			env:  map[string]string{EnvStrict: "0"},
+			want: Parser{},
+		},
+		"invalid default host": {
+			env:     map[string]string{EnvDefaultHost: "bad!host"},
+			wantErr: `invalid TFADDR_DEFAULT_HOST "bad!host": idna: disallowed rune U+0021`,
+		},
+		"invalid allowed host": {
+			env:     map[string]string{EnvAllowedHosts: "example.com,bad!host"},
+			wantErr: `invalid hostname "bad!host" in TFADDR_ALLOWED_HOSTS: idna: disallowed rune U+0021`,
+		},
+		"invalid strict": {
+			env:     map[string]string{EnvStrict: "very"},
+			wantErr: `invalid TFADDR_STRICT "very": must be a boolean value such as "true" or "false"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, name := range []string{EnvDefaultHost, EnvAllowedHosts, EnvDeniedHosts, EnvStrict} {
+				t.Setenv(name, test.env[name])
+			}
+
+			got, err := NewParserFromEnv()
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got, cmp.FilterPath(isFuncField, cmp.Ignore())); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+// isFuncField matches the function-typed fields of Parser, which cmp can't
+// compare.
+func isFuncField(p cmp.Path) bool {
+	switch p.String() {
+	case "Trace", "Localize", "Limits.AllowedRune":
+		return true
+	}
+	return false
+}
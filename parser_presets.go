@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// PublicRegistryOnly returns a Parser that accepts only addresses on the
+// public Terraform Registry, and the built-in provider addresses that
+// Terraform itself treats as always available, with source strings limited
+// to MaxSafeSourceLength bytes.
+//
+// This is intended for services that handle addresses from untrusted
+// sources and that are only concerned with the public registry. Callers
+// may adjust the other fields of the result before using it.
+func PublicRegistryOnly() Parser {
+	return Parser{
+		AllowedHosts: []svchost.Hostname{
+			DefaultProviderRegistryHost,
+			BuiltInProviderHost,
+		},
+		MaxSourceLength: MaxSafeSourceLength,
+	}
+}
+
+// EnterpriseDefaults returns a Parser for use with a private registry, such
+// as a Terraform Enterprise instance, at the given hostname, which is used
+// for provider and module addresses that don't specify a hostname.
+//
+// Addresses that specify another hostname are still accepted, as they are
+// by Terraform. The result has AllowPunycodeHosts set, so hostnames may be
+// given in either their Unicode or punycode form, both in addresses and in
+// the argument to EnterpriseDefaults. EnterpriseDefaults returns an error
+// if the given hostname is not valid.
+func EnterpriseDefaults(host string) (Parser, error) {
+	p := Parser{AllowPunycodeHosts: true}
+	hostname, err := p.parseHostname(host)
+	if err != nil {
+		return Parser{}, fmt.Errorf("invalid registry hostname %q: %s", host, err)
+	}
+	p.DefaultProviderHost = hostname
+	p.DefaultModuleHost = hostname
+	return p, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPublicRegistryOnly(t *testing.T) {
+	p := PublicRegistryOnly()
+
+	for _, input := range []string{"hashicorp/aws", "registry.terraform.io/hashicorp/aws", "terraform"} {
+		if _, err := p.ParseProviderSource(input); err != nil {
+			t.Errorf("unexpected error for provider %q: %s", input, err)
+		}
+	}
+	if _, err := p.ParseModuleSource("hashicorp/consul/aws"); err != nil {
+		t.Errorf("unexpected error for module: %s", err)
+	}
+
+	var hostErr *HostPolicyError
+	if _, err := p.ParseProviderSource("example.com/awesomecorp/happycloud"); !errors.As(err, &hostErr) {
+		t.Errorf("wrong error for provider on another host: %v", err)
+	}
+	if _, err := p.ParseModuleSource("example.com/awesomecorp/network/happycloud"); !errors.As(err, &hostErr) {
+		t.Errorf("wrong error for module on another host: %v", err)
+	}
+	if _, err := p.ParseModuleSource("hashicorp/consul/aws//" + strings.Repeat("a", MaxSafeSourceLength)); err == nil {
+		t.Errorf("unexpected success for overlong module source")
+	}
+}
+
+func TestEnterpriseDefaults(t *testing.T) {
+	for _, host := range []string{"испытание.example.com", "xn--80akhbyknj4f.example.com"} {
+		t.Run(host, func(t *testing.T) {
+			p, err := EnterpriseDefaults(host)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			provider, err := p.ParseProviderSource("awesomecorp/happycloud")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got, want := provider.String(), "испытание.example.com/awesomecorp/happycloud"; got != want {
+				t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+			}
+
+			mod, err := p.ParseModuleSource("awesomecorp/network/happycloud")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got, want := mod.String(), "испытание.example.com/awesomecorp/network/happycloud"; got != want {
+				t.Errorf("wrong module\ngot:  %s\nwant: %s", got, want)
+			}
+
+			provider, err = p.ParseProviderSource("xn--80akhbyknj4f.example.com/awesomecorp/happycloud")
+			if err != nil {
+				t.Fatalf("unexpected error for punycode address: %s", err)
+			}
+			if got, want := provider.String(), "испытание.example.com/awesomecorp/happycloud"; got != want {
+				t.Errorf("wrong provider for punycode address\ngot:  %s\nwant: %s", got, want)
+			}
+
+			if _, err := p.ParseModuleSource("hashicorp/consul/aws/foo"); err == nil {
+				t.Errorf("unexpected success for four-part address")
+			}
+		})
+	}
+
+	if _, err := ParseProviderSource("xn--80akhbyknj4f.example.com/awesomecorp/happycloud"); err == nil {
+		t.Errorf("unexpected success for punycode address without AllowPunycodeHosts")
+	}
+	if _, err := EnterpriseDefaults("not a hostname"); err == nil {
+		t.Errorf("unexpected success with invalid hostname")
+	}
+}
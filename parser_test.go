@@ -0,0 +1,510 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestParserHostPolicy(t *testing.T) {
+	tests := map[string]struct {
+		parser  Parser
+		input   string
+		wantErr string
+	}{
+		"zero value": {
+			parser: Parser{},
+			input:  "example.com/hashicorp/aws",
+		},
+		"allowed explicit host": {
+			parser: Parser{
+				AllowedHosts: []svchost.Hostname{"example.com"},
+			},
+			input: "example.com/hashicorp/aws",
+		},
+		"allowed implied host": {
+			parser: Parser{
+				AllowedHosts: []svchost.Hostname{DefaultProviderRegistryHost},
+			},
+			input: "hashicorp/aws",
+		},
+		"not allowed explicit host": {
+			parser: Parser{
+				AllowedHosts: []svchost.Hostname{"example.com"},
+			},
+			input:   "example.net/hashicorp/aws",
+			wantErr: `registry hostname "example.net" is not in the list of allowed hosts`,
+		},
+		"not allowed implied host": {
+			parser: Parser{
+				AllowedHosts: []svchost.Hostname{"example.com"},
+			},
+			input:   "hashicorp/aws",
+			wantErr: `registry hostname "registry.terraform.io" is not in the list of allowed hosts`,
+		},
+		"denied host": {
+			parser: Parser{
+				DeniedHosts: []svchost.Hostname{"example.com"},
+			},
+			input:   "Example.COM/hashicorp/aws",
+			wantErr: `registry hostname "example.com" is not allowed`,
+		},
+		"denied host takes priority": {
+			parser: Parser{
+				AllowedHosts: []svchost.Hostname{"example.com"},
+				DeniedHosts:  []svchost.Hostname{"example.com"},
+			},
+			input:   "example.com/hashicorp/aws",
+			wantErr: `registry hostname "example.com" is not allowed`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We test both address families with the same inputs by
+			// deriving a module address from the provider address.
+			inputs := map[string]func() error{
+				"provider": func() error {
+					_, err := test.parser.ParseProviderSource(test.input)
+					return err
+				},
+				"module": func() error {
+					_, err := test.parser.ParseModuleSource(test.input + "/target")
+					return err
+				},
+			}
+			for family, parse := range inputs {
+				err := parse()
+				if test.wantErr == "" {
+					if err != nil {
+						t.Errorf("unexpected %s error: %s", family, err)
+					}
+					continue
+				}
+				if err == nil {
+					t.Errorf("unexpected %s success\nwant error: %s", family, test.wantErr)
+					continue
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Errorf("wrong %s error\ngot:  %s\nwant: %s", family, got, test.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestParserHostPolicy_errorType(t *testing.T) {
+	p := Parser{
+		DeniedHosts: []svchost.Hostname{"example.com"},
+	}
+	_, err := p.ParseModuleSource("example.com/hashicorp/consul/aws")
+
+	var policyErr *HostPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("wrong error type %T; want *HostPolicyError", err)
+	}
+	if got, want := policyErr.Hostname, svchost.Hostname("example.com"); got != want {
+		t.Errorf("wrong hostname\ngot:  %s\nwant: %s", got, want)
+	}
+	if !policyErr.Denied {
+		t.Errorf("Denied is false; want true")
+	}
+
+	// Syntax errors take priority over the host policy
+	_, err = p.ParseModuleSource("example.com/hashicorp/consul/aws?ref=main")
+	if err == nil || errors.As(err, &policyErr) {
+		t.Errorf("wrong error %#v; want syntax error", err)
+	}
+}
+
+func TestSafeParse(t *testing.T) {
+	long := "example.com/hashicorp/" + strings.Repeat("a", MaxSafeSourceLength)
+	longModule := "hashicorp/consul/aws//" + strings.Repeat("a", MaxSafeSourceLength)
+
+	if _, err := ParseProviderSource(long); err != nil {
+		t.Errorf("unexpected error from ParseProviderSource: %s", err)
+	}
+	_, err := SafeParseProviderSource(long)
+	if err == nil {
+		t.Errorf("unexpected success from SafeParseProviderSource")
+	} else if got, want := err.Error(), `Invalid provider source string: The provider source string must be no longer than 512 bytes.`; got != want {
+		t.Errorf("wrong error from SafeParseProviderSource\ngot:  %s\nwant: %s", got, want)
+	}
+
+	if _, err := ParseModuleSource(longModule); err != nil {
+		t.Errorf("unexpected error from ParseModuleSource: %s", err)
+	}
+	_, err = SafeParseModuleSource(longModule)
+	if err == nil {
+		t.Errorf("unexpected success from SafeParseModuleSource")
+	} else if got, want := err.Error(), `invalid module source address: must be no longer than 512 bytes`; got != want {
+		t.Errorf("wrong error from SafeParseModuleSource\ngot:  %s\nwant: %s", got, want)
+	}
+
+	if _, err := SafeParseProviderSource("hashicorp/aws"); err != nil {
+		t.Errorf("unexpected error from SafeParseProviderSource: %s", err)
+	}
+	if _, err := SafeParseModuleSource("hashicorp/consul/aws"); err != nil {
+		t.Errorf("unexpected error from SafeParseModuleSource: %s", err)
+	}
+}
+
+func TestParserDefaultHosts(t *testing.T) {
+	p := Parser{
+		DefaultProviderHost: svchost.Hostname("providers.example.com"),
+		DefaultModuleHost:   svchost.Hostname("modules.example.com"),
+	}
+
+	tests := map[string]struct {
+		parse   func() (string, error)
+		want    string
+		wantErr string
+	}{
+		"provider with implied host": {
+			parse: func() (string, error) {
+				addr, err := p.ParseProviderSource("hashicorp/aws")
+				return addr.String(), err
+			},
+			want: "providers.example.com/hashicorp/aws",
+		},
+		"provider with explicit host": {
+			parse: func() (string, error) {
+				addr, err := p.ParseProviderSource("registry.terraform.io/hashicorp/aws")
+				return addr.String(), err
+			},
+			want: "registry.terraform.io/hashicorp/aws",
+		},
+		"provider with unknown namespace": {
+			parse: func() (string, error) {
+				addr, err := p.ParseProviderSource("aws")
+				return addr.String(), err
+			},
+			want: "providers.example.com/?/aws",
+		},
+		"provider with legacy namespace": {
+			parse: func() (string, error) {
+				_, err := p.ParseProviderSource("-/aws")
+				return "", err
+			},
+			wantErr: `Invalid provider namespace: The legacy provider namespace "-" can be used only with hostname registry.terraform.io.`,
+		},
+		"module with implied host": {
+			parse: func() (string, error) {
+				addr, err := p.ParseModuleSource("hashicorp/consul/aws//foo")
+				return addr.ForDisplay(), err
+			},
+			want: "modules.example.com/hashicorp/consul/aws//foo",
+		},
+		"module with explicit host": {
+			parse: func() (string, error) {
+				addr, err := p.ParseModuleSource("registry.terraform.io/hashicorp/consul/aws")
+				return addr.String(), err
+			},
+			want: "registry.terraform.io/hashicorp/consul/aws",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.parse()
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParserShortestForm(t *testing.T) {
+	p := Parser{
+		DefaultProviderHost: svchost.Hostname("providers.example.com"),
+		DefaultModuleHost:   svchost.Hostname("modules.example.com"),
+	}
+
+	providers := map[string]string{
+		"hashicorp/aws":                       "hashicorp/aws",
+		"aws":                                 "aws",
+		"providers.example.com/hashicorp/aws": "hashicorp/aws",
+		"registry.terraform.io/hashicorp/aws": "registry.terraform.io/hashicorp/aws",
+		"испытание.example.com/hashicorp/aws": "испытание.example.com/hashicorp/aws",
+	}
+	for input, want := range providers {
+		t.Run(input, func(t *testing.T) {
+			addr, err := p.ParseProviderSource(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := p.ProviderShortestForm(addr)
+			if got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+			if again, err := p.ParseProviderSource(got); err != nil || again != addr {
+				t.Errorf("%q parses to %s, %v; want %s", got, again, err, addr)
+			}
+		})
+	}
+
+	modules := map[string]string{
+		"hashicorp/consul/aws//foo":                  "hashicorp/consul/aws//foo",
+		"modules.example.com/hashicorp/consul/aws":   "hashicorp/consul/aws",
+		"registry.terraform.io/hashicorp/consul/aws": "registry.terraform.io/hashicorp/consul/aws",
+	}
+	for input, want := range modules {
+		t.Run(input, func(t *testing.T) {
+			addr, err := p.ParseModuleSource(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := p.ModuleShortestForm(addr)
+			if got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+			if again, err := p.ParseModuleSource(got); err != nil || again != addr {
+				t.Errorf("%q parses to %s, %v; want %s", got, again, err, addr)
+			}
+		})
+	}
+}
+
+func TestParserTrace(t *testing.T) {
+	var got []string
+	p := Parser{
+		AllowedHosts: []svchost.Hostname{"example.com", DefaultModuleRegistryHost},
+		Trace: func(msg string) {
+			got = append(got, msg)
+		},
+	}
+
+	if _, err := p.ParseProviderSource("Example.com/HashiCorp/AWS"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := p.ParseModuleSource(`hashicorp/consul/aws//examples\foo/`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{
+		`parsing "Example.com/HashiCorp/AWS" as a provider source address`,
+		`found 3 slash-separated parts`,
+		`normalized type "AWS" to "aws"`,
+		`normalized namespace "HashiCorp" to "hashicorp"`,
+		`normalized hostname "Example.com" to example.com`,
+		`checking hostname example.com against the host policy`,
+		`parsing "hashicorp/consul/aws//examples\\foo/" as a module registry source address`,
+		`normalized subdirectory "examples\\foo/" to "examples/foo"`,
+		`found 3 slash-separated components in package address "hashicorp/consul/aws"`,
+		`no hostname given, so using default hostname registry.terraform.io`,
+		`accepted namespace "hashicorp", module name "consul", and target system "aws"`,
+		`checking hostname registry.terraform.io against the host policy`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong trace messages\n%s", diff)
+	}
+}
+
+func TestParserLocalize(t *testing.T) {
+	parser := Parser{
+		DeniedHosts: []svchost.Hostname{"example.com"},
+		Localize: func(err error) string {
+			var policyErr *HostPolicyError
+			var segErr *SegmentError
+			switch {
+			case errors.As(err, &policyErr):
+				return "Registrierungs-Hostname " + policyErr.Hostname.ForDisplay() + " ist nicht erlaubt"
+			case errors.As(err, &segErr):
+				return "Ungültiges Segment: " + segErr.Segment
+			default:
+				return ""
+			}
+		},
+	}
+
+	_, err := parser.ParseModuleSource("example.com/hashicorp/consul/aws")
+	if got, want := err.Error(), "Registrierungs-Hostname example.com ist nicht erlaubt"; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+	var policyErr *HostPolicyError
+	if !errors.As(err, &policyErr) || !policyErr.Denied {
+		t.Errorf("original error is not available: %#v", err)
+	}
+
+	// Errors that the function doesn't translate are returned unchanged.
+	_, err = parser.ParseProviderSource("a/b/c/d")
+	if _, ok := err.(*ParserError); !ok {
+		t.Errorf("untranslated error was wrapped: %#v", err)
+	}
+
+	_, errs := parser.ParseProviderSourceLenient("hashicorp/")
+	if got, want := errorStrings(errs), []string{"Ungültiges Segment: type"}; !cmp.Equal(got, want) {
+		t.Errorf("wrong lenient errors\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestParseInterpolation(t *testing.T) {
+	tests := map[string]string{
+		"${var.namespace}/aws":          `source address "${var.namespace}/aws" contains the template sequence "${", but source addresses must be static strings that don't refer to variables or other dynamic values`,
+		"hashicorp/%{if x}aws%{endif}":  `source address "hashicorp/%{if x}aws%{endif}" contains the template sequence "%{", but source addresses must be static strings that don't refer to variables or other dynamic values`,
+		"hashicorp/consul/${var.cloud}": `source address "hashicorp/consul/${var.cloud}" contains the template sequence "${", but source addresses must be static strings that don't refer to variables or other dynamic values`,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, provErr := ParseProviderSource(input)
+			_, modErr := ParseModuleSource(input)
+			for _, err := range []error{provErr, modErr} {
+				var interpErr *InterpolationError
+				if !errors.As(err, &interpErr) {
+					t.Fatalf("wrong error type %T: %v", err, err)
+				}
+				if got := err.Error(); got != want {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+			}
+		})
+	}
+
+	// Dollar and percent signs alone are not template sequences, and are
+	// reported by the usual rules.
+	_, err := ParseProviderSource("hashicorp/$aws")
+	var interpErr *InterpolationError
+	if errors.As(err, &interpErr) {
+		t.Errorf("unexpected interpolation error: %s", err)
+	}
+}
+
+// TestParserConcurrentTenants runs parsers with different configurations
+// concurrently, including one shared between goroutines, so that running
+// the tests with the race detector checks that parsing uses no shared
+// mutable state.
+func TestParserConcurrentTenants(t *testing.T) {
+	tenants := map[string]Parser{
+		"registry.terraform.io": {},
+		"a.example.com": {
+			DefaultProviderHost: svchost.Hostname("a.example.com"),
+			DefaultModuleHost:   svchost.Hostname("a.example.com"),
+			AllowedHosts:        []svchost.Hostname{"a.example.com"},
+		},
+		"b.example.com": {
+			DefaultProviderHost: svchost.Hostname("b.example.com"),
+			DefaultModuleHost:   svchost.Hostname("b.example.com"),
+			DeniedHosts:         []svchost.Hostname{"a.example.com"},
+			Limits:              Limits{MaxSubdirDepth: 2},
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(tenants)*8)
+	for wantHost, p := range tenants {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(wantHost string, p Parser) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					provider, err := p.ParseProviderSource("hashicorp/aws")
+					if err != nil {
+						errs <- err
+						return
+					}
+					if got := provider.Hostname.String(); got != wantHost {
+						errs <- fmt.Errorf("provider host is %q; want %q", got, wantHost)
+						return
+					}
+					module, err := p.ParseModuleSource("hashicorp/consul/aws//modules/foo")
+					if err != nil {
+						errs <- err
+						return
+					}
+					if got := module.Package.Host.String(); got != wantHost {
+						errs <- fmt.Errorf("module host is %q; want %q", got, wantHost)
+						return
+					}
+				}
+			}(wantHost, p)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestParserAllowSingleLabelModuleHosts(t *testing.T) {
+	p := Parser{AllowSingleLabelModuleHosts: true}
+
+	tests := map[string]struct {
+		want    string
+		wantErr string
+	}{
+		"registry:8443/acme/net/aws": {
+			want: "registry:8443/acme/net/aws",
+		},
+		"registry:8443/acme/net/aws//modules/foo": {
+			want: "registry:8443/acme/net/aws//modules/foo",
+		},
+		"example.com/acme/net/aws": {
+			want: "example.com/acme/net/aws",
+		},
+		"registry/acme/net/aws": {
+			wantErr: `invalid module registry hostname: must contain at least one dot`,
+		},
+		"foo/var/baz/qux": {
+			wantErr: `invalid module registry hostname: must contain at least one dot`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := p.ParseModuleSource(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := got.ForDisplay(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		got, errs := p.ParseModuleSourceLenient("registry:8443/acme/net/")
+		if got, want := got.Package.Host, svchost.Hostname("registry:8443"); got != want {
+			t.Errorf("wrong host %q; want %q", got, want)
+		}
+		if diff := cmp.Diff([]string{`invalid target system "": must be set`}, errorStrings(errs)); diff != "" {
+			t.Errorf("wrong errors\n%s", diff)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		if _, err := ParseModuleSource("registry:8443/acme/net/aws"); err == nil {
+			t.Error("single-label hostname accepted by default")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed popular_modules.txt
+var popularModulesRaw string
+
+// popularModules is the parsed form of popularModulesRaw, in the same
+// order as in the file, populated by loadPopularModules on first use so
+// that programs that never consult the table don't pay for parsing it.
+var (
+	popularModules     []ModulePackage
+	popularModulesOnce sync.Once
+)
+
+// loadPopularModules returns popularModules, parsing it first if needed.
+// The caller must not modify the result.
+func loadPopularModules() []ModulePackage {
+	popularModulesOnce.Do(func() {
+		popularModules = mustParsePopularModules(popularModulesRaw)
+	})
+	return popularModules
+}
+
+func mustParsePopularModules(raw string) []ModulePackage {
+	var ret []ModulePackage
+	sc := bufio.NewScanner(strings.NewReader(raw))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mod, err := ParseModuleSource(line)
+		if err != nil || mod.Subdir != "" || mod.Package.Host != DefaultModuleRegistryHost {
+			panic(fmt.Sprintf("invalid entry %q in popular_modules.txt", line))
+		}
+		ret = append(ret, mod.Package)
+	}
+	return ret
+}
+
+// PopularModulePackages returns a table of widely-used module packages on
+// the public Terraform registry, such as "terraform-aws-modules/vpc/aws".
+//
+// The table is a snapshot embedded in this package and is not exhaustive.
+// It is intended for heuristics such as warning about packages whose
+// addresses are suspiciously similar to a popular one; see
+// LookalikePopularModulePackages.
+//
+// The result is a new slice on each call, so the caller may modify it.
+func PopularModulePackages() []ModulePackage {
+	popular := loadPopularModules()
+	ret := make([]ModulePackage, len(popular))
+	copy(ret, popular)
+	return ret
+}
+
+// IsPopularModulePackage returns true if the given package is on the
+// public registry and appears in the table returned by
+// PopularModulePackages.
+//
+// The comparison is case-insensitive, because the public registry treats
+// module addresses case-insensitively.
+func IsPopularModulePackage(pkg ModulePackage) bool {
+	if pkg.Host != DefaultModuleRegistryHost {
+		return false
+	}
+	given := strings.ToLower(pkg.ForRegistryProtocol())
+	for _, popular := range loadPopularModules() {
+		if strings.ToLower(popular.ForRegistryProtocol()) == given {
+			return true
+		}
+	}
+	return false
+}
+
+// LookalikePopularModulePackages returns the popular module packages, as
+// returned by PopularModulePackages, whose namespace, name, and target
+// system differ from those of the given package by exactly one inserted,
+// deleted, or substituted character, ignoring case.
+//
+// The hostname of the given package is not considered, because a
+// lookalike of a popular package is suspicious regardless of which
+// registry it's published on.
+//
+// A non-empty result suggests that the given package might be a
+// typosquatting attempt, or a typo by the user.
+func LookalikePopularModulePackages(pkg ModulePackage) []ModulePackage {
+	given := strings.ToLower(pkg.ForRegistryProtocol())
+	var ret []ModulePackage
+	for _, popular := range loadPopularModules() {
+		if editDistance(given, strings.ToLower(popular.ForRegistryProtocol())) == 1 {
+			ret = append(ret, popular)
+		}
+	}
+	return ret
+}
+
+// editDistance returns the Levenshtein distance between the given strings,
+// counting characters rather than bytes.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
+
+func minInt(first int, rest ...int) int {
+	ret := first
+	for _, v := range rest {
+		if v < ret {
+			ret = v
+		}
+	}
+	return ret
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPopularModulePackages(t *testing.T) {
+	pkgs := PopularModulePackages()
+	if len(pkgs) == 0 {
+		t.Fatal("no popular module packages")
+	}
+
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if seen[pkg.String()] {
+			t.Errorf("duplicate entry %s", pkg)
+		}
+		seen[pkg.String()] = true
+	}
+
+	// Modifying the result must not modify the table
+	pkgs[0].Name = "modified"
+	if got := PopularModulePackages()[0].Name; got == "modified" {
+		t.Errorf("modifying the result modified the table")
+	}
+}
+
+func TestIsPopularModulePackage(t *testing.T) {
+	tests := map[string]bool{
+		"terraform-aws-modules/vpc/aws":                       true,
+		"Terraform-AWS-Modules/VPC/aws":                       true,
+		"registry.terraform.io/terraform-aws-modules/vpc/aws": true,
+		"example.com/terraform-aws-modules/vpc/aws":           false,
+		"terraform-aws-modules/vpc/google":                    false,
+		"hashicorp/subnets/cidr":                              false,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			got := IsPopularModulePackage(MustParseModuleSource(input).Package)
+			if got != want {
+				t.Errorf("wrong result\ngot:  %t\nwant: %t", got, want)
+			}
+		})
+	}
+}
+
+func TestLookalikePopularModulePackages(t *testing.T) {
+	tests := map[string][]string{
+		"terraform-aws-modules/vpc/aws":             nil,
+		"terraform-aws-module/vpc/aws":              {"registry.terraform.io/terraform-aws-modules/vpc/aws"},
+		"terraform-aws-modules/vcp/aws":             nil, // transposition counts as two edits
+		"terraform-aws-modules/vpcc/aws":            {"registry.terraform.io/terraform-aws-modules/vpc/aws"},
+		"example.com/terraform-aws-modules/vpc/aw5": {"registry.terraform.io/terraform-aws-modules/vpc/aws"},
+		"terraform-aws-modules/sn/aws":              {"registry.terraform.io/terraform-aws-modules/sns/aws"},
+		"hashicorp/subnets/cidr":                    nil,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			var got []string
+			for _, pkg := range LookalikePopularModulePackages(MustParseModuleSource(input).Package) {
+				got = append(got, pkg.String())
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is a stable identifier for a kind of error returned by this
+// package, which services can include in their responses so that clients
+// can react to particular problems without matching error messages.
+type ErrorCode string
+
+const (
+	// ErrorInvalidAddress is the code for any error that doesn't have a
+	// more specific code, such as a source string with the wrong number of
+	// segments.
+	ErrorInvalidAddress ErrorCode = "invalid-address"
+
+	// ErrorInvalidSegment is the code for a *SegmentError.
+	ErrorInvalidSegment ErrorCode = "invalid-segment"
+
+	// ErrorHostNotAllowed is the code for a *HostPolicyError.
+	ErrorHostNotAllowed ErrorCode = "host-not-allowed"
+
+	// ErrorDynamicAddress is the code for an *InterpolationError.
+	ErrorDynamicAddress ErrorCode = "dynamic-address"
+
+	// ErrorLimitExceeded is the code for a *SubdirLimitError.
+	ErrorLimitExceeded ErrorCode = "limit-exceeded"
+)
+
+// ErrorCodeOf returns the code for the given error returned by this
+// package, which may be wrapped in other errors, including a
+// *LocalizedError.
+func ErrorCodeOf(err error) ErrorCode {
+	var segmentErr *SegmentError
+	var policyErr *HostPolicyError
+	var interpErr *InterpolationError
+	var limitErr *SubdirLimitError
+	switch {
+	case errors.As(err, &policyErr):
+		return ErrorHostNotAllowed
+	case errors.As(err, &interpErr):
+		return ErrorDynamicAddress
+	case errors.As(err, &limitErr):
+		return ErrorLimitExceeded
+	case errors.As(err, &segmentErr):
+		return ErrorInvalidSegment
+	default:
+		return ErrorInvalidAddress
+	}
+}
+
+// ProblemContentType is the media type of a JSON-encoded ProblemDetails,
+// for use in the Content-Type header of an HTTP response.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 "problem details" object describing an error
+// returned by this package, for registry services that report invalid
+// addresses over HTTP.
+//
+// Type is always "about:blank", as RFC 7807 recommends when a problem has no
+// further semantics than its HTTP status code. Code and Segment are
+// extension members giving more detail.
+type ProblemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// Code is the result of ErrorCodeOf for the error.
+	Code ErrorCode `json:"code"`
+
+	// Segment is the Segment of a *SegmentError, if the error is one.
+	Segment string `json:"segment,omitempty"`
+}
+
+// NewProblemDetails returns a ProblemDetails describing the given error.
+//
+// The status is 403 Forbidden for a *HostPolicyError, since the address is
+// valid but not permitted, or 400 Bad Request for any other error. If the
+// error is a *ParserError then its summary and detail are used as the title
+// and detail, and otherwise the title describes the status and the detail is
+// the error message.
+func NewProblemDetails(err error) ProblemDetails {
+	ret := ProblemDetails{
+		Type:   "about:blank",
+		Status: HTTPStatusCode(err),
+		Detail: err.Error(),
+		Code:   ErrorCodeOf(err),
+	}
+	ret.Title = http.StatusText(ret.Status)
+
+	var parserErr *ParserError
+	if errors.As(err, &parserErr) {
+		ret.Title = parserErr.Summary
+		ret.Detail = parserErr.Detail
+	}
+	var segmentErr *SegmentError
+	if errors.As(err, &segmentErr) {
+		ret.Segment = segmentErr.Segment
+	}
+	return ret
+}
+
+// HTTPStatusCode returns the HTTP status code that a service should use to
+// report the given error, as described for NewProblemDetails.
+func HTTPStatusCode(err error) int {
+	if ErrorCodeOf(err) == ErrorHostNotAllowed {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// The gRPC status codes returned by GRPCCode, which have the same values as
+// the corresponding constants in the google.golang.org/grpc/codes package.
+const (
+	grpcInvalidArgument  = 3
+	grpcPermissionDenied = 7
+)
+
+// GRPCCode returns the gRPC status code that a service should use to report
+// the given error: PermissionDenied for a *HostPolicyError, or
+// InvalidArgument for any other error.
+//
+// The result can be converted to a codes.Code from the
+// google.golang.org/grpc/codes package, which this package doesn't depend
+// on, to build a status:
+//
+//	status.New(codes.Code(tfaddr.GRPCCode(err)), err.Error())
+func GRPCCode(err error) uint32 {
+	if ErrorCodeOf(err) == ErrorHostNotAllowed {
+		return grpcPermissionDenied
+	}
+	return grpcInvalidArgument
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestNewProblemDetails(t *testing.T) {
+	denying := Parser{DeniedHosts: []svchost.Hostname{DefaultProviderRegistryHost}}
+	localizing := Parser{Localize: func(error) string { return "übersetzt" }}
+
+	tests := map[string]struct {
+		err      error
+		want     ProblemDetails
+		wantGRPC uint32
+	}{
+		"provider parser error": {
+			err: func() error { _, err := ParseProviderSource("a/b/c/d"); return err }(),
+			want: ProblemDetails{
+				Type:   "about:blank",
+				Title:  "Invalid provider source string",
+				Status: 400,
+				Detail: `The "source" attribute must be in the format "[hostname/][namespace/]name". The given string "a/b/c/d" looks like a module source address, which belongs in the "source" argument of a module block instead.`,
+				Code:   ErrorInvalidAddress,
+			},
+			wantGRPC: 3,
+		},
+		"module error": {
+			err: func() error { _, err := ParseModuleSource("a/b"); return err }(),
+			want: ProblemDetails{
+				Type:   "about:blank",
+				Title:  "Bad Request",
+				Status: 400,
+				Detail: `a module registry source address must have either three or four slash-separated components; "a/b" looks like a provider source address, which belongs in a required_providers block instead`,
+				Code:   ErrorInvalidAddress,
+			},
+			wantGRPC: 3,
+		},
+		"segment error": {
+			err: NewModulePackageBuilder().Host("localhost").err,
+			want: ProblemDetails{
+				Type:    "about:blank",
+				Title:   "Bad Request",
+				Status:  400,
+				Detail:  `invalid hostname "localhost": must contain at least one dot`,
+				Code:    ErrorInvalidSegment,
+				Segment: "hostname",
+			},
+			wantGRPC: 3,
+		},
+		"host policy error": {
+			err: func() error { _, err := denying.ParseProviderSource("hashicorp/aws"); return err }(),
+			want: ProblemDetails{
+				Type:   "about:blank",
+				Title:  "Forbidden",
+				Status: 403,
+				Detail: `registry hostname "registry.terraform.io" is not allowed`,
+				Code:   ErrorHostNotAllowed,
+			},
+			wantGRPC: 7,
+		},
+		"localized interpolation error": {
+			err: func() error { _, err := localizing.ParseModuleSource("${var.x}/b/c"); return err }(),
+			want: ProblemDetails{
+				Type:   "about:blank",
+				Title:  "Bad Request",
+				Status: 400,
+				Detail: "übersetzt",
+				Code:   ErrorDynamicAddress,
+			},
+			wantGRPC: 3,
+		},
+		"limit error": {
+			err: &SubdirLimitError{Subdir: "a/b/c", MaxDepth: 2},
+			want: ProblemDetails{
+				Type:   "about:blank",
+				Title:  "Bad Request",
+				Status: 400,
+				Detail: `invalid subdirectory path "a/b/c": must have no more than 2 slash-separated segments`,
+				Code:   ErrorLimitExceeded,
+			},
+			wantGRPC: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.err == nil {
+				t.Fatal("test case has no error")
+			}
+			if diff := cmp.Diff(test.want, NewProblemDetails(test.err)); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := GRPCCode(test.err); got != test.wantGRPC {
+				t.Errorf("wrong gRPC code %d; want %d", got, test.wantGRPC)
+			}
+		})
+	}
+}
+
+func TestProblemDetailsJSON(t *testing.T) {
+	_, err := ParseModuleSource("a/b")
+	got, jsonErr := json.Marshal(NewProblemDetails(err))
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	want := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"a module registry source address must have either three or four slash-separated components; \"a/b\" looks like a provider source address, which belongs in a required_providers block instead","code":"invalid-address"}`
+	if string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+}
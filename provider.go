@@ -5,7 +5,10 @@ package tfaddr
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	svchost "github.com/hashicorp/terraform-svchost"
 	"golang.org/x/net/idna"
@@ -72,6 +75,48 @@ func (pt Provider) ForDisplay() string {
 	return pt.Hostname.ForDisplay() + "/" + pt.Namespace + "/" + pt.Type
 }
 
+// ForRegistryProtocol returns a string representation of just the namespace
+// and type portions of the address, always omitting the registry hostname.
+//
+// This is primarily intended for generating addresses to send to the
+// registry in question via the provider registry protocol, since the
+// protocol skips sending the registry its own hostname as part of
+// identifiers. ModulePackage.ForRegistryProtocol is the equivalent for the
+// module registry protocol.
+func (pt Provider) ForRegistryProtocol() string {
+	if pt.IsZero() {
+		panic("called ForRegistryProtocol on zero-value addrs.Provider")
+	}
+	return pt.Namespace + "/" + pt.Type
+}
+
+// ShortestForm returns the most concise source string that
+// ParseProviderSource would parse to the receiver, omitting the default
+// hostname and, for an address with an unknown namespace, the namespace
+// placeholder.
+//
+// This is intended for tools that rewrite source strings into their most
+// idiomatic form.
+func (pt Provider) ShortestForm() string {
+	if pt.IsZero() {
+		panic("called ShortestForm on zero-value addrs.Provider")
+	}
+	if pt.Namespace == UnknownProviderNamespace && pt.Hostname == DefaultProviderRegistryHost {
+		return pt.Type
+	}
+	return pt.ForDisplay()
+}
+
+// sourceString returns the fully-qualified source string of the receiver,
+// or its ShortestForm if it has an unknown namespace, because the
+// placeholder for an unknown namespace can't be parsed.
+func (pt Provider) sourceString() string {
+	if pt.Namespace == UnknownProviderNamespace {
+		return pt.ShortestForm()
+	}
+	return pt.String()
+}
+
 // NewProvider constructs a provider address from its parts, and normalizes
 // the namespace and type parts to lowercase using unicode case folding rules
 // so that resulting addrs.Provider values can be compared using standard
@@ -106,6 +151,29 @@ func NewProvider(hostname svchost.Hostname, namespace, typeName string) Provider
 	}
 }
 
+// WithHostname returns a copy of the receiver with its hostname replaced by
+// the given hostname, after validating and normalizing it, or an error if
+// the given hostname is invalid.
+func (pt Provider) WithHostname(hostname string) (Provider, error) {
+	return (&ProviderBuilder{addr: pt}).Hostname(hostname).Build()
+}
+
+// WithNamespace returns a copy of the receiver with its namespace replaced
+// by the given namespace, after validating and normalizing it, or an error
+// if the given namespace is invalid.
+//
+// The special legacy and unknown namespaces are not accepted.
+func (pt Provider) WithNamespace(namespace string) (Provider, error) {
+	return (&ProviderBuilder{addr: pt}).Namespace(namespace).Build()
+}
+
+// WithType returns a copy of the receiver with its type replaced by the
+// given type, after validating and normalizing it, or an error if the given
+// type is invalid.
+func (pt Provider) WithType(typeName string) (Provider, error) {
+	return (&ProviderBuilder{addr: pt}).Type(typeName).Build()
+}
+
 // LegacyString returns the provider type, which is frequently used
 // interchangeably with provider name. This function can and should be removed
 // when provider type is fully integrated. As a safeguard for future
@@ -120,6 +188,21 @@ func (pt Provider) LegacyString() string {
 	return pt.Type
 }
 
+// CacheKey returns the slash-separated path, relative to the root of a
+// Terraform plugin cache directory, where Terraform CLI places the given
+// version of the receiver for the given platform, such as
+// "registry.terraform.io/hashicorp/aws/5.0.0/linux_amd64".
+//
+// The platform must be given in Terraform's usual "os_arch" form. Callers
+// should use filepath.FromSlash to convert the result for use with the
+// local filesystem.
+func (pt Provider) CacheKey(version, platform string) string {
+	if pt.IsZero() {
+		panic("called CacheKey on zero-value addrs.Provider")
+	}
+	return pt.String() + "/" + version + "/" + platform
+}
+
 // IsZero returns true if the receiver is the zero value of addrs.Provider.
 //
 // The zero value is not a valid addrs.Provider and calling other methods on
@@ -148,20 +231,35 @@ func (pt Provider) IsBuiltIn() bool {
 // LessThan returns true if the receiver should sort before the other given
 // address in an ordered list of provider addresses.
 //
-// This ordering is an arbitrary one just to allow deterministic results from
-// functions that would otherwise have no natural ordering. It's subject
-// to change in future.
+// Addresses are ordered by hostname, then by namespace, then by type, each
+// compared bytewise. Lock files and other generated files rely on this
+// order being stable, so it will not change.
 func (pt Provider) LessThan(other Provider) bool {
+	return pt.Compare(other) < 0
+}
+
+// Compare returns -1 if the receiver sorts before the other given address,
+// +1 if it sorts after it, or 0 if the two are equal, using the same
+// order as LessThan.
+func (pt Provider) Compare(other Provider) int {
 	switch {
 	case pt.Hostname != other.Hostname:
-		return pt.Hostname < other.Hostname
+		return strings.Compare(string(pt.Hostname), string(other.Hostname))
 	case pt.Namespace != other.Namespace:
-		return pt.Namespace < other.Namespace
+		return strings.Compare(pt.Namespace, other.Namespace)
 	default:
-		return pt.Type < other.Type
+		return strings.Compare(pt.Type, other.Type)
 	}
 }
 
+// SortProviders sorts the given provider addresses in place into the order
+// defined by Provider.LessThan.
+func SortProviders(providers []Provider) {
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].LessThan(providers[j])
+	})
+}
+
 // IsLegacy returns true if the provider is a legacy-style provider
 func (pt Provider) IsLegacy() bool {
 	if pt.IsZero() {
@@ -189,19 +287,75 @@ func (pt Provider) Equals(other Provider) bool {
 // "name"-only format is parsed as -/name (i.e. legacy namespace)
 // requiring further identification of the namespace via Registry API
 func ParseProviderSource(str string) (Provider, error) {
+	return Parser{}.ParseProviderSource(str)
+}
+
+// ParseProviderSource is like the package-level function of the same name,
+// but additionally applies the rules configured in the receiver.
+func (p Parser) ParseProviderSource(str string) (ret Provider, err error) {
+	if p.Observer != nil {
+		start := time.Now()
+		defer func() { p.observe(ProviderKind, start, ret.Hostname, err) }()
+	}
+	p.tracef("parsing %q as a provider source address", str)
+	if err := p.checkLength(str); err != nil {
+		return Provider{}, p.localize(&ParserError{
+			Summary: "Invalid provider source string",
+			Detail:  fmt.Sprintf("The provider source string %s.", err),
+		})
+	}
+
+	if err := p.checkInterpolation(str); err != nil {
+		return Provider{}, p.localize(err)
+	}
+
+	ret, err = p.parseProviderSource(str)
+	if err != nil {
+		return ret, p.localize(err)
+	}
+	if err := p.checkProviderLimits(ret, str); err != nil {
+		return Provider{}, p.localize(err)
+	}
+	if err := p.checkHostname(ret.Hostname); err != nil {
+		return Provider{}, p.localize(err)
+	}
+	if err := p.checkProviderHostProfile(ret, str); err != nil {
+		return Provider{}, p.localize(err)
+	}
+	return ret, nil
+}
+
+func (p Parser) parseProviderSource(str string) (Provider, error) {
+	defaultHost := p.defaultProviderHost()
+
 	var ret Provider
+	if strings.Contains(str, "//") || strings.Count(str, "/") == 3 {
+		// Module registry addresses have either a subdirectory or four
+		// segments, so this is probably a module address given where a
+		// provider address was expected.
+		return ret, &ParserError{
+			Summary: "Invalid provider source string",
+			Detail:  fmt.Sprintf(`The "source" attribute must be in the format "[hostname/][namespace/]name". The given string %q looks like a module source address, which belongs in the "source" argument of a module block instead.`, str),
+		}
+	}
+	givenName := str[strings.LastIndex(str, "/")+1:]
 	parts, err := parseSourceStringParts(str)
 	if err != nil {
 		return ret, err
 	}
+	p.tracef("found %d slash-separated parts", len(parts))
 
 	name := parts[len(parts)-1]
+	if name != givenName {
+		p.tracef("normalized type %q to %q", givenName, name)
+	}
 	ret.Type = name
-	ret.Hostname = DefaultProviderRegistryHost
+	ret.Hostname = defaultHost
 
 	if len(parts) == 1 {
+		p.tracef("no namespace given, so using the unknown namespace placeholder %q and default hostname %s", UnknownProviderNamespace, defaultHost.ForDisplay())
 		return Provider{
-			Hostname:  DefaultProviderRegistryHost,
+			Hostname:  defaultHost,
 			Namespace: UnknownProviderNamespace,
 			Type:      name,
 		}, nil
@@ -214,6 +368,7 @@ func ParseProviderSource(str string) (Provider, error) {
 			// For now we're tolerating legacy provider addresses until we've
 			// finished updating the rest of the codebase to no longer use them,
 			// or else we'd get errors round-tripping through legacy subsystems.
+			p.tracef("found the legacy namespace placeholder %q", LegacyProviderNamespace)
 			ret.Namespace = LegacyProviderNamespace
 		} else {
 			namespace, err := ParseProviderPart(givenNamespace)
@@ -223,20 +378,28 @@ func ParseProviderSource(str string) (Provider, error) {
 					Detail:  fmt.Sprintf(`Invalid provider namespace %q in source %q: %s"`, namespace, str, err),
 				}
 			}
+			if namespace != givenNamespace {
+				p.tracef("normalized namespace %q to %q", givenNamespace, namespace)
+			}
 			ret.Namespace = namespace
 		}
 	}
 
+	if len(parts) == 2 {
+		p.tracef("no hostname given, so using default hostname %s", defaultHost.ForDisplay())
+	}
+
 	// Final Case: 3 parts
 	if len(parts) == 3 {
 		// the namespace is always the first part in a three-part source string
-		hn, err := svchost.ForComparison(parts[0])
+		hn, err := p.parseHostname(parts[0])
 		if err != nil {
 			return Provider{}, &ParserError{
 				Summary: "Invalid provider source hostname",
 				Detail:  fmt.Sprintf(`Invalid provider source hostname namespace %q in source %q: %s"`, hn, str, err),
 			}
 		}
+		p.tracef("normalized hostname %q to %s", parts[0], hn.ForDisplay())
 		ret.Hostname = hn
 	}
 
@@ -304,6 +467,24 @@ func MustParseProviderSource(raw string) (Provider) {
 	return p
 }
 
+// ParseProviderSourceOrDefault is like ParseProviderSource except that if
+// the given string is valid but doesn't specify a known namespace, such as
+// "aws" or "-/aws", it returns the given default provider instead of the
+// partially-known address.
+//
+// Errors are still returned for invalid source strings, so callers can
+// distinguish between an ambiguous address and an invalid one.
+func ParseProviderSourceOrDefault(str string, def Provider) (Provider, error) {
+	p, err := ParseProviderSource(str)
+	if err != nil {
+		return p, err
+	}
+	if !p.HasKnownNamespace() || p.IsLegacy() {
+		return def, nil
+	}
+	return p, nil
+}
+
 // ValidateProviderAddress returns error if the given address is not FQN,
 // that is if it is missing any of the three components from
 // hostname/namespace/name.
@@ -432,9 +613,45 @@ func ParseProviderPart(given string) (string, error) {
 		return "", fmt.Errorf("must contain only letters, digits, and dashes, and may not use leading or trailing dashes")
 	}
 
+	// The IDNA rules are more liberal than our documented rules, allowing
+	// various symbols such as emoji, so we additionally check each
+	// character of the normalized result.
+	for _, r := range result {
+		if !IsValidProviderPartRune(r) {
+			return "", fmt.Errorf("must contain only letters, digits, and dashes, and may not use leading or trailing dashes")
+		}
+	}
+
 	return result, nil
 }
 
+// IsValidProviderPartRune returns true if the given rune may appear in a
+// normalized provider namespace or type, as returned by ParseProviderPart.
+//
+// The permitted characters are the dash, decimal digits (Unicode category
+// Nd), letters (category L, including modifier letters), and the
+// non-enclosing combining marks (categories Mn and Mc) that many scripts
+// require to write letters. All other characters, including symbols (such
+// as modifier symbols and emoji), punctuation, and whitespace, are rejected.
+//
+// This function only describes individual characters. A valid provider part
+// must also satisfy the other rules enforced by ParseProviderPart, such as
+// not starting or ending with a dash.
+func IsValidProviderPartRune(r rune) bool {
+	switch {
+	case r == '-':
+		return true
+	case unicode.IsLetter(r):
+		return true
+	case unicode.Is(unicode.Nd, r):
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Mc):
+		return true
+	default:
+		return false
+	}
+}
+
 // MustParseProviderPart is a wrapper around ParseProviderPart that panics if
 // it returns an error.
 func MustParseProviderPart(given string) string {
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProviderDeprecation describes a registry's notice that a provider is
+// deprecated, optionally naming a successor provider that should be used
+// instead.
+//
+// In JSON, a ProviderDeprecation is an object with the properties
+// "provider", "successor", and "message", where the first two are
+// provider source strings and "successor" may be omitted.
+type ProviderDeprecation struct {
+	Provider Provider
+
+	// Successor is the provider that should be used instead of Provider,
+	// or the zero value if the registry didn't name one.
+	Successor Provider
+
+	// Message is a human-readable explanation from the registry.
+	Message string
+}
+
+type providerDeprecationJSON struct {
+	Provider  string `json:"provider"`
+	Successor string `json:"successor,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// HasSuccessor returns true if the deprecation names a successor provider.
+func (d ProviderDeprecation) HasSuccessor() bool {
+	return !d.Successor.IsZero()
+}
+
+// ApplySuccessor returns the successor provider and true if the given
+// provider is the deprecated one and a successor is known. Otherwise it
+// returns the given provider unchanged and false.
+func (d ProviderDeprecation) ApplySuccessor(p Provider) (Provider, bool) {
+	if p != d.Provider || !d.HasSuccessor() {
+		return p, false
+	}
+	return d.Successor, true
+}
+
+func (d ProviderDeprecation) MarshalJSON() ([]byte, error) {
+	if d.Provider.IsZero() {
+		return nil, fmt.Errorf("provider deprecation has no provider")
+	}
+	provider, err := d.Provider.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	raw := providerDeprecationJSON{
+		Provider: string(provider),
+		Message:  d.Message,
+	}
+	if d.HasSuccessor() {
+		successor, err := d.Successor.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		raw.Successor = string(successor)
+	}
+	return json.Marshal(raw)
+}
+
+func (d *ProviderDeprecation) UnmarshalJSON(b []byte) error {
+	var raw providerDeprecationJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	provider, err := ParseProviderSource(raw.Provider)
+	if err != nil {
+		return fmt.Errorf("invalid deprecated provider %q: %s", raw.Provider, err)
+	}
+	var successor Provider
+	if raw.Successor != "" {
+		successor, err = ParseProviderSource(raw.Successor)
+		if err != nil {
+			return fmt.Errorf("invalid successor provider %q: %s", raw.Successor, err)
+		}
+		if successor == provider {
+			return fmt.Errorf("provider %s can't be its own successor", provider.ForDisplay())
+		}
+	}
+
+	*d = ProviderDeprecation{
+		Provider:  provider,
+		Successor: successor,
+		Message:   raw.Message,
+	}
+	return nil
+}
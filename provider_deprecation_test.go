@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderDeprecationJSON(t *testing.T) {
+	tests := map[string]struct {
+		want    ProviderDeprecation
+		wantErr string
+	}{
+		`{"provider":"hashicorp/template","successor":"hashicorp/cloudinit","message":"Use cloudinit instead."}`: {
+			want: ProviderDeprecation{
+				Provider:  MustParseProviderSource("hashicorp/template"),
+				Successor: MustParseProviderSource("hashicorp/cloudinit"),
+				Message:   "Use cloudinit instead.",
+			},
+		},
+		`{"provider":"example.com/acme/widget"}`: {
+			want: ProviderDeprecation{
+				Provider: MustParseProviderSource("example.com/acme/widget"),
+			},
+		},
+		`{"provider":"template","successor":"cloudinit"}`: {
+			want: ProviderDeprecation{
+				Provider:  MustParseProviderSource("template"),
+				Successor: MustParseProviderSource("cloudinit"),
+			},
+		},
+		`{"provider":"a/b/c/d"}`: {
+			wantErr: `invalid deprecated provider "a/b/c/d": Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name". The given string "a/b/c/d" looks like a module source address, which belongs in the "source" argument of a module block instead.`,
+		},
+		`{"provider":"hashicorp/template","successor":"registry.terraform.io/hashicorp/template"}`: {
+			wantErr: `provider hashicorp/template can't be its own successor`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			var got ProviderDeprecation
+			err := json.Unmarshal([]byte(input), &got)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Fatalf("wrong result\n%s", diff)
+			}
+
+			// The result must round-trip.
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("unexpected error marshaling: %s", err)
+			}
+			var again ProviderDeprecation
+			if err := json.Unmarshal(b, &again); err != nil {
+				t.Fatalf("unexpected error unmarshaling %s: %s", b, err)
+			}
+			if diff := cmp.Diff(got, again); diff != "" {
+				t.Errorf("wrong result after round-trip\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviderDeprecationApplySuccessor(t *testing.T) {
+	template := MustParseProviderSource("hashicorp/template")
+	cloudinit := MustParseProviderSource("hashicorp/cloudinit")
+	aws := MustParseProviderSource("hashicorp/aws")
+
+	d := ProviderDeprecation{Provider: template, Successor: cloudinit}
+	if got, ok := d.ApplySuccessor(template); !ok || got != cloudinit {
+		t.Errorf("wrong result for deprecated provider: %s, %t", got, ok)
+	}
+	if got, ok := d.ApplySuccessor(aws); ok || got != aws {
+		t.Errorf("wrong result for other provider: %s, %t", got, ok)
+	}
+
+	d = ProviderDeprecation{Provider: template}
+	if got, ok := d.ApplySuccessor(template); ok || got != template {
+		t.Errorf("wrong result without successor: %s, %t", got, ok)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"math"
+	"sync"
+)
+
+// ProviderID is a compact handle for a provider address that has been
+// added to a ProviderInterner, for workloads such as large dependency
+// graphs where storing a full Provider value per edge would dominate
+// memory use.
+//
+// A ProviderID is only meaningful together with the ProviderInterner that
+// issued it. The zero value is NoProviderID, which never refers to an
+// address.
+type ProviderID uint32
+
+// NoProviderID is the zero value of ProviderID, which doesn't refer to any
+// provider address.
+const NoProviderID ProviderID = 0
+
+// ProviderInterner assigns a distinct ProviderID to each distinct provider
+// address it is given, and can translate in both directions.
+//
+// IDs are assigned sequentially from 1 in the order addresses are first
+// interned, so they can also be used as indexes into slices. The zero value
+// is an empty interner ready to use, and all methods are safe for
+// concurrent use.
+type ProviderInterner struct {
+	mu        sync.RWMutex
+	providers []Provider
+	ids       map[Provider]ProviderID
+}
+
+// Intern returns the ID of the given address, assigning a new ID if the
+// address hasn't been interned before.
+//
+// Intern panics if given the zero value of Provider, or if the interner
+// already holds the maximum number of addresses that a ProviderID can
+// represent.
+func (in *ProviderInterner) Intern(p Provider) ProviderID {
+	if p.IsZero() {
+		panic("called Intern with zero-value addrs.Provider")
+	}
+	if id, ok := in.Lookup(p); ok {
+		return id
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if id, ok := in.ids[p]; ok {
+		// Another goroutine interned the same address in the meantime.
+		return id
+	}
+	if uint64(len(in.providers)) == math.MaxUint32 {
+		panic("too many provider addresses for ProviderID")
+	}
+	if in.ids == nil {
+		in.ids = make(map[Provider]ProviderID)
+	}
+	in.providers = append(in.providers, p)
+	id := ProviderID(len(in.providers))
+	in.ids[p] = id
+	return id
+}
+
+// Lookup returns the ID of the given address and true, or NoProviderID and
+// false if the address hasn't been interned.
+func (in *ProviderInterner) Lookup(p Provider) (ProviderID, bool) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	id, ok := in.ids[p]
+	return id, ok
+}
+
+// Provider returns the address with the given ID and true, or the zero
+// value of Provider and false if the interner didn't issue that ID.
+func (in *ProviderInterner) Provider(id ProviderID) (Provider, bool) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	if id == NoProviderID || int(id) > len(in.providers) {
+		return Provider{}, false
+	}
+	return in.providers[id-1], true
+}
+
+// Len returns the number of distinct addresses that have been interned,
+// which is also the largest ID issued so far.
+func (in *ProviderInterner) Len() int {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return len(in.providers)
+}
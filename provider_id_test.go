@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestProviderInterner(t *testing.T) {
+	var in ProviderInterner
+	aws := MustParseProviderSource("hashicorp/aws")
+	null := MustParseProviderSource("hashicorp/null")
+
+	if _, ok := in.Lookup(aws); ok {
+		t.Fatal("empty interner found an address")
+	}
+	awsID := in.Intern(aws)
+	nullID := in.Intern(null)
+	if awsID != 1 || nullID != 2 {
+		t.Errorf("wrong IDs %d and %d; want 1 and 2", awsID, nullID)
+	}
+	if got := in.Intern(MustParseProviderSource("registry.terraform.io/HashiCorp/AWS")); got != awsID {
+		t.Errorf("same address got a different ID %d; want %d", got, awsID)
+	}
+	if got, ok := in.Lookup(null); !ok || got != nullID {
+		t.Errorf("wrong lookup result %d, %t; want %d, true", got, ok, nullID)
+	}
+	if got, ok := in.Provider(nullID); !ok || got != null {
+		t.Errorf("wrong address %s, %t; want %s, true", got, ok, null)
+	}
+	for _, id := range []ProviderID{NoProviderID, 3} {
+		if _, ok := in.Provider(id); ok {
+			t.Errorf("found address for unissued ID %d", id)
+		}
+	}
+	if got, want := in.Len(), 2; got != want {
+		t.Errorf("wrong length %d; want %d", got, want)
+	}
+}
+
+func TestProviderInterner_concurrent(t *testing.T) {
+	var in ProviderInterner
+	providers := []Provider{
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("hashicorp/null"),
+		MustParseProviderSource("example.com/foo/bar"),
+	}
+
+	var wg sync.WaitGroup
+	ids := make([][]ProviderID, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, p := range providers {
+				ids[i] = append(ids[i], in.Intern(p))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got, want := in.Len(), len(providers); got != want {
+		t.Fatalf("wrong length %d; want %d", got, want)
+	}
+	for i := range ids {
+		for j, id := range ids[i] {
+			if got, _ := in.Provider(id); got != providers[j] {
+				t.Errorf("goroutine %d got ID %d for %s, which refers to %s", i, id, providers[j], got)
+			}
+		}
+	}
+}
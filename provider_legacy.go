@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+)
+
+// NamespaceResolver finds the namespace of a provider that was given
+// without one, such as in a state snapshot from Terraform v0.12 or
+// earlier, which refers to providers only by type.
+//
+// Terraform itself resolves such providers by asking the public registry,
+// which knows which namespace each legacy provider type moved to. This
+// package does not make network requests, so callers must provide their
+// own NamespaceResolver to use that mapping.
+type NamespaceResolver interface {
+	// ResolveLegacyNamespace returns the namespace on
+	// DefaultProviderRegistryHost of the provider with the given type.
+	ResolveLegacyNamespace(typeName string) (string, error)
+}
+
+// NamespaceResolverFunc is an adapter to allow the use of an ordinary
+// function as a NamespaceResolver.
+type NamespaceResolverFunc func(typeName string) (string, error)
+
+// ResolveLegacyNamespace calls f(typeName).
+func (f NamespaceResolverFunc) ResolveLegacyNamespace(typeName string) (string, error) {
+	return f(typeName)
+}
+
+// SameProviderAllowingLegacy returns true if the two given addresses refer
+// to the same provider, treating an address with the legacy or unknown
+// namespace, such as "-/aws", as equal to the fully-qualified address that
+// the given resolver resolves it to.
+//
+// The legacy "terraform" provider always resolves to the built-in provider
+// of the same type, as Terraform does, without consulting the resolver.
+// Otherwise, if the resolver is nil then legacy and unknown namespaces
+// resolve to "hashicorp", which is correct for the providers that HashiCorp
+// originally distributed but not for providers that later moved to other
+// namespaces. Two addresses that both lack a namespace are equal if their
+// types are equal, without consulting the resolver.
+//
+// SameProviderAllowingLegacy returns an error if the resolver fails or
+// returns an invalid namespace.
+func SameProviderAllowingLegacy(a, b Provider, resolver NamespaceResolver) (bool, error) {
+	if !needsNamespaceResolution(a) && !needsNamespaceResolution(b) {
+		return a == b, nil
+	}
+	if needsNamespaceResolution(a) && needsNamespaceResolution(b) {
+		return a.Type == b.Type, nil
+	}
+
+	resolvedA, err := resolveLegacyProvider(a, resolver)
+	if err != nil {
+		return false, err
+	}
+	resolvedB, err := resolveLegacyProvider(b, resolver)
+	if err != nil {
+		return false, err
+	}
+	return resolvedA == resolvedB, nil
+}
+
+// needsNamespaceResolution returns true if the given address is on the
+// default registry but has the legacy or unknown namespace.
+func needsNamespaceResolution(p Provider) bool {
+	return p.Hostname == DefaultProviderRegistryHost &&
+		(p.Namespace == LegacyProviderNamespace || p.Namespace == UnknownProviderNamespace)
+}
+
+// resolveLegacyProvider returns the fully-qualified address that Terraform
+// uses in place of the given address, if it needs resolution, or returns it
+// unchanged otherwise. The legacy "terraform" provider becomes the built-in
+// provider, and any other type has its namespace resolved using the given
+// resolver.
+func resolveLegacyProvider(p Provider, resolver NamespaceResolver) (Provider, error) {
+	if !needsNamespaceResolution(p) {
+		return p, nil
+	}
+	if implied := impliedProvider(p); resolver == nil || implied.IsBuiltIn() {
+		return implied, nil
+	}
+	given, err := resolver.ResolveLegacyNamespace(p.Type)
+	if err != nil {
+		return Provider{}, fmt.Errorf("failed to resolve namespace for legacy provider %q: %s", p.Type, err)
+	}
+	namespace, err := ParseProviderNamespace(given)
+	if err != nil {
+		return Provider{}, fmt.Errorf("invalid namespace %q for legacy provider %q: %s", given, p.Type, err)
+	}
+	return NewProvider(DefaultProviderRegistryHost, string(namespace), p.Type), nil
+}
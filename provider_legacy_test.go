@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSameProviderAllowingLegacy(t *testing.T) {
+	resolver := NamespaceResolverFunc(func(typeName string) (string, error) {
+		switch typeName {
+		case "github":
+			return "integrations", nil
+		case "broken":
+			return "", fmt.Errorf("registry unavailable")
+		case "invalid":
+			return "not valid", nil
+		default:
+			return "hashicorp", nil
+		}
+	})
+	legacy := func(typeName string) Provider {
+		return Provider{Hostname: DefaultProviderRegistryHost, Namespace: LegacyProviderNamespace, Type: typeName}
+	}
+
+	tests := map[string]struct {
+		a, b     Provider
+		resolver NamespaceResolver
+		want     bool
+		wantErr  string
+	}{
+		"identical": {
+			a:    MustParseProviderSource("hashicorp/aws"),
+			b:    MustParseProviderSource("hashicorp/aws"),
+			want: true,
+		},
+		"different": {
+			a:    MustParseProviderSource("hashicorp/aws"),
+			b:    MustParseProviderSource("hashicorp/null"),
+			want: false,
+		},
+		"legacy and resolved": {
+			a:        legacy("aws"),
+			b:        MustParseProviderSource("hashicorp/aws"),
+			resolver: resolver,
+			want:     true,
+		},
+		"resolved and legacy": {
+			a:        MustParseProviderSource("integrations/github"),
+			b:        legacy("github"),
+			resolver: resolver,
+			want:     true,
+		},
+		"legacy resolves elsewhere": {
+			a:        legacy("github"),
+			b:        MustParseProviderSource("hashicorp/github"),
+			resolver: resolver,
+			want:     false,
+		},
+		"unknown namespace": {
+			a:        MustParseProviderSource("github"),
+			b:        MustParseProviderSource("integrations/github"),
+			resolver: resolver,
+			want:     true,
+		},
+		"nil resolver assumes hashicorp": {
+			a:    legacy("github"),
+			b:    MustParseProviderSource("hashicorp/github"),
+			want: true,
+		},
+		"both legacy": {
+			a:        legacy("broken"),
+			b:        MustParseProviderSource("broken"),
+			resolver: resolver,
+			want:     true,
+		},
+		"legacy terraform is built in": {
+			a:        legacy("terraform"),
+			b:        MustParseProviderSource("terraform.io/builtin/terraform"),
+			resolver: resolver,
+			want:     true,
+		},
+		"legacy terraform is built in with nil resolver": {
+			a:    legacy("terraform"),
+			b:    MustParseProviderSource("terraform.io/builtin/terraform"),
+			want: true,
+		},
+		"legacy terraform is not on the registry": {
+			a:    legacy("terraform"),
+			b:    MustParseProviderSource("hashicorp/terraform"),
+			want: false,
+		},
+		"legacy on other host is not resolved": {
+			a:        MustParseProviderSource("example.com/hashicorp/aws"),
+			b:        legacy("aws"),
+			resolver: resolver,
+			want:     false,
+		},
+		"resolver fails": {
+			a:        legacy("broken"),
+			b:        MustParseProviderSource("hashicorp/broken"),
+			resolver: resolver,
+			wantErr:  `failed to resolve namespace for legacy provider "broken": registry unavailable`,
+		},
+		"resolver returns invalid namespace": {
+			a:        legacy("invalid"),
+			b:        MustParseProviderSource("hashicorp/invalid"),
+			resolver: resolver,
+			wantErr:  `invalid namespace "not valid" for legacy provider "invalid": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := SameProviderAllowingLegacy(test.a, test.b, test.resolver)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if err.Error() != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MirrorPath returns the slash-separated path, relative to the root of a
+// filesystem mirror directory, of the directory that contains the given
+// version of the receiver for the given platform in the mirror's
+// "unpacked" layout, such as
+// "registry.terraform.io/hashicorp/aws/4.0.0/linux_amd64".
+//
+// As with CacheKey, the hostname is given in its Unicode form, because
+// that is the form Terraform CLI accepts when it reads the mirror
+// directory. The version must be a semantic version without a "v" prefix,
+// and the operating system and architecture must combine to a platform in
+// Terraform's usual "os_arch" form; MirrorPath returns an error otherwise.
+// Callers should use filepath.FromSlash to convert the result for use with
+// the local filesystem.
+func (pt Provider) MirrorPath(version, os, arch string) (string, error) {
+	if pt.IsZero() {
+		panic("called MirrorPath on zero-value addrs.Provider")
+	}
+	platform, err := mirrorPlatform(version, os, arch)
+	if err != nil {
+		return "", err
+	}
+	return pt.mirrorDir() + "/" + version + "/" + platform, nil
+}
+
+// MirrorArchivePath returns the slash-separated path, relative to the root
+// of a filesystem mirror directory, of the zip archive of the given version
+// of the receiver for the given platform in the mirror's "packed" layout,
+// such as
+// "registry.terraform.io/hashicorp/aws/terraform-provider-aws_4.0.0_linux_amd64.zip".
+//
+// The hostname, version, and platform are handled as for MirrorPath.
+func (pt Provider) MirrorArchivePath(version, os, arch string) (string, error) {
+	if pt.IsZero() {
+		panic("called MirrorArchivePath on zero-value addrs.Provider")
+	}
+	platform, err := mirrorPlatform(version, os, arch)
+	if err != nil {
+		return "", err
+	}
+	asset := ProviderReleaseAsset{
+		Kind:     ProviderReleaseArchive,
+		Type:     pt.Type,
+		Version:  version,
+		Platform: platform,
+	}
+	return pt.mirrorDir() + "/" + asset.Filename(), nil
+}
+
+// mirrorDir returns the directory, relative to the root of a filesystem
+// mirror, that contains all of the versions of the receiver.
+func (pt Provider) mirrorDir() string {
+	return pt.Hostname.ForDisplay() + "/" + pt.Namespace + "/" + pt.Type
+}
+
+// mirrorPlatform validates the given version and platform for use in a
+// filesystem mirror path, and returns the platform in "os_arch" form.
+func mirrorPlatform(version, os, arch string) (string, error) {
+	if !releaseVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid provider version %q: must be a semantic version such as \"1.0.0\"", version)
+	}
+	platform := os + "_" + arch
+	if !releasePlatformPattern.MatchString(platform) {
+		return "", fmt.Errorf("invalid platform %q: must be in the form \"os_arch\"", platform)
+	}
+	return platform, nil
+}
+
+// NetworkMirrorIndexURL returns the URL of the index of available versions
+// of the receiver in the provider network mirror protocol, given the base
+// URL of a network mirror, such as
+// "https://mirror.example.com/registry.terraform.io/hashicorp/aws/index.json".
+//
+// The base URL is treated as a directory even if its path doesn't end in a
+// slash. The hostname in the result is in its ASCII-compatible "punycode"
+// form, and the other segments are percent-encoded as needed, as for
+// URLPathEscaped.
+func (pt Provider) NetworkMirrorIndexURL(base *url.URL) *url.URL {
+	if pt.IsZero() {
+		panic("called NetworkMirrorIndexURL on zero-value addrs.Provider")
+	}
+	return pt.networkMirrorURL(base, "index.json")
+}
+
+// NetworkMirrorVersionURL returns the URL of the list of archives of the
+// given version of the receiver in the provider network mirror protocol,
+// given the base URL of a network mirror, such as
+// "https://mirror.example.com/registry.terraform.io/hashicorp/aws/4.0.0.json".
+//
+// The base URL is handled as for NetworkMirrorIndexURL.
+func (pt Provider) NetworkMirrorVersionURL(base *url.URL, version string) *url.URL {
+	if pt.IsZero() {
+		panic("called NetworkMirrorVersionURL on zero-value addrs.Provider")
+	}
+	return pt.networkMirrorURL(base, url.PathEscape(version)+".json")
+}
+
+// NetworkMirrorArchiveURL returns the URL of an archive of the given
+// version of the receiver, given the base URL of a network mirror and the
+// "url" property of one of the archives in the mirror's response for that
+// version.
+//
+// The protocol allows the archive URL to be relative, in which case it is
+// resolved relative to the URL returned by NetworkMirrorVersionURL.
+// NetworkMirrorArchiveURL returns an error if the archive URL is not a
+// valid URL.
+func (pt Provider) NetworkMirrorArchiveURL(base *url.URL, version, archive string) (*url.URL, error) {
+	ref, err := url.Parse(archive)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive URL %q for %s v%s: %s", archive, pt.ForDisplay(), version, err)
+	}
+	return pt.NetworkMirrorVersionURL(base, version).ResolveReference(ref), nil
+}
+
+// networkMirrorURL returns the URL of the given file in the directory for
+// the receiver under the given network mirror base URL.
+func (pt Provider) networkMirrorURL(base *url.URL, filename string) *url.URL {
+	dir := *base
+	if !strings.HasSuffix(dir.Path, "/") {
+		dir.Path += "/"
+		if dir.RawPath != "" {
+			dir.RawPath += "/"
+		}
+	}
+	// The leading "./" prevents a hostname with a port number from being
+	// taken as a URL scheme.
+	ref, err := url.Parse("./" + pt.URLPathEscaped() + "/" + filename)
+	if err != nil {
+		// Can't happen, because all of the segments are escaped.
+		panic(fmt.Sprintf("invalid network mirror path for %s: %s", pt, err))
+	}
+	return dir.ResolveReference(ref)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestProviderMirrorPath(t *testing.T) {
+	tests := map[string]struct {
+		wantDir     string
+		wantArchive string
+	}{
+		"hashicorp/aws": {
+			"registry.terraform.io/hashicorp/aws/4.0.0/linux_amd64",
+			"registry.terraform.io/hashicorp/aws/terraform-provider-aws_4.0.0_linux_amd64.zip",
+		},
+		"испытание.example.com/awesomecorp/happycloud": {
+			"испытание.example.com/awesomecorp/happycloud/4.0.0/linux_amd64",
+			"испытание.example.com/awesomecorp/happycloud/terraform-provider-happycloud_4.0.0_linux_amd64.zip",
+		},
+		"example.com:8443/awesomecorp/happycloud": {
+			"example.com:8443/awesomecorp/happycloud/4.0.0/linux_amd64",
+			"example.com:8443/awesomecorp/happycloud/terraform-provider-happycloud_4.0.0_linux_amd64.zip",
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			p := MustParseProviderSource(input)
+			got, err := p.MirrorPath("4.0.0", "linux", "amd64")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.wantDir {
+				t.Errorf("wrong directory\ngot:  %s\nwant: %s", got, test.wantDir)
+			}
+			got, err = p.MirrorArchivePath("4.0.0", "linux", "amd64")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.wantArchive {
+				t.Errorf("wrong archive\ngot:  %s\nwant: %s", got, test.wantArchive)
+			}
+		})
+	}
+}
+
+func TestProviderMirrorPathInvalid(t *testing.T) {
+	tests := map[string]struct {
+		version, os, arch string
+		wantErr           string
+	}{
+		"path traversal in version": {
+			"../../evil", "linux", "amd64",
+			`invalid provider version "../../evil": must be a semantic version such as "1.0.0"`,
+		},
+		"v prefix": {
+			"v4.0.0", "linux", "amd64",
+			`invalid provider version "v4.0.0": must be a semantic version such as "1.0.0"`,
+		},
+		"slash in os": {
+			"4.0.0", "linux/..", "amd64",
+			`invalid platform "linux/.._amd64": must be in the form "os_arch"`,
+		},
+		"empty arch": {
+			"4.0.0", "linux", "",
+			`invalid platform "linux_": must be in the form "os_arch"`,
+		},
+	}
+
+	p := MustParseProviderSource("hashicorp/aws")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, fn := range []func(string, string, string) (string, error){p.MirrorPath, p.MirrorArchivePath} {
+				_, err := fn(test.version, test.os, test.arch)
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderNetworkMirrorURLs(t *testing.T) {
+	tests := map[string]struct {
+		provider    string
+		base        string
+		archive     string
+		wantIndex   string
+		wantVersion string
+		wantArchive string
+	}{
+		"relative archive": {
+			provider:    "hashicorp/aws",
+			base:        "https://mirror.example.com/providers/",
+			archive:     "terraform-provider-aws_4.0.0_linux_amd64.zip",
+			wantIndex:   "https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/index.json",
+			wantVersion: "https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/4.0.0.json",
+			wantArchive: "https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/terraform-provider-aws_4.0.0_linux_amd64.zip",
+		},
+		"base without trailing slash": {
+			provider:    "example.com:8443/awesomecorp/happycloud",
+			base:        "https://mirror.example.com/providers",
+			archive:     "https://cdn.example.net/happycloud.zip",
+			wantIndex:   "https://mirror.example.com/providers/example.com:8443/awesomecorp/happycloud/index.json",
+			wantVersion: "https://mirror.example.com/providers/example.com:8443/awesomecorp/happycloud/4.0.0.json",
+			wantArchive: "https://cdn.example.net/happycloud.zip",
+		},
+		"internationalized names": {
+			provider:    "испытание.example.com/награды/ёлка",
+			base:        "https://mirror.example.com/",
+			archive:     "../archives/ёлка.zip",
+			wantIndex:   "https://mirror.example.com/xn--80akhbyknj4f.example.com/%D0%BD%D0%B0%D0%B3%D1%80%D0%B0%D0%B4%D1%8B/%D1%91%D0%BB%D0%BA%D0%B0/index.json",
+			wantVersion: "https://mirror.example.com/xn--80akhbyknj4f.example.com/%D0%BD%D0%B0%D0%B3%D1%80%D0%B0%D0%B4%D1%8B/%D1%91%D0%BB%D0%BA%D0%B0/4.0.0.json",
+			wantArchive: "https://mirror.example.com/xn--80akhbyknj4f.example.com/%D0%BD%D0%B0%D0%B3%D1%80%D0%B0%D0%B4%D1%8B/archives/%D1%91%D0%BB%D0%BA%D0%B0.zip",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := MustParseProviderSource(test.provider)
+			base, err := url.Parse(test.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := p.NetworkMirrorIndexURL(base).String(); got != test.wantIndex {
+				t.Errorf("wrong index URL\ngot:  %s\nwant: %s", got, test.wantIndex)
+			}
+			if got := p.NetworkMirrorVersionURL(base, "4.0.0").String(); got != test.wantVersion {
+				t.Errorf("wrong version URL\ngot:  %s\nwant: %s", got, test.wantVersion)
+			}
+			archive, err := p.NetworkMirrorArchiveURL(base, "4.0.0", test.archive)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := archive.String(); got != test.wantArchive {
+				t.Errorf("wrong archive URL\ngot:  %s\nwant: %s", got, test.wantArchive)
+			}
+			if base.String() != test.base {
+				t.Errorf("base URL was modified to %s", base)
+			}
+		})
+	}
+
+	p := MustParseProviderSource("hashicorp/aws")
+	base, _ := url.Parse("https://mirror.example.com/")
+	if _, err := p.NetworkMirrorArchiveURL(base, "4.0.0", "http://[::1"); err == nil {
+		t.Errorf("unexpected success with invalid archive URL")
+	}
+}
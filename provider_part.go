@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProviderNamespace is a provider namespace that has already been validated
+// and normalized by ParseProviderNamespace.
+//
+// Functions that accept a ProviderNamespace rather than a string can rely
+// on it being valid, as long as callers don't construct one by direct
+// conversion from an arbitrary string. Use Validate to check a value whose
+// origin is unknown.
+type ProviderNamespace string
+
+// ParseProviderNamespace validates and normalizes the given provider
+// namespace, using the same rules as ParseProviderPart.
+//
+// The special LegacyProviderNamespace and UnknownProviderNamespace values
+// are not accepted, because they are placeholders rather than real
+// namespaces.
+func ParseProviderNamespace(given string) (ProviderNamespace, error) {
+	result, err := ParseProviderPart(given)
+	if err != nil {
+		return "", err
+	}
+	return ProviderNamespace(result), nil
+}
+
+// Validate returns an error if the receiver is not a valid, normalized
+// provider namespace, as would be returned by ParseProviderNamespace.
+func (n ProviderNamespace) Validate() error {
+	result, err := ParseProviderNamespace(string(n))
+	if err != nil {
+		return err
+	}
+	if result != n {
+		return fmt.Errorf("must be given in normalized form %q", result)
+	}
+	return nil
+}
+
+// ProviderType is a provider type that has already been validated and
+// normalized by ParseProviderType.
+//
+// Functions that accept a ProviderType rather than a string can rely on it
+// being valid, as long as callers don't construct one by direct conversion
+// from an arbitrary string. Use Validate to check a value whose origin is
+// unknown.
+type ProviderType string
+
+// ParseProviderType validates and normalizes the given provider type,
+// using the same rules as ParseProviderPart.
+//
+// Types with the prefix "terraform-" are not accepted, for the same reason
+// that ParseProviderSource rejects them.
+func ParseProviderType(given string) (ProviderType, error) {
+	result, err := ParseProviderPart(given)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(result, "terraform-") {
+		return "", fmt.Errorf(`must not have the redundant prefix "terraform-"`)
+	}
+	return ProviderType(result), nil
+}
+
+// Validate returns an error if the receiver is not a valid, normalized
+// provider type, as would be returned by ParseProviderType.
+func (t ProviderType) Validate() error {
+	result, err := ParseProviderType(string(t))
+	if err != nil {
+		return err
+	}
+	if result != t {
+		return fmt.Errorf("must be given in normalized form %q", result)
+	}
+	return nil
+}
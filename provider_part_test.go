@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestParseProviderNamespace(t *testing.T) {
+	tests := map[string]struct {
+		Want  ProviderNamespace
+		Error string
+	}{
+		`hashicorp`: {
+			`hashicorp`,
+			``,
+		},
+		`HashiCorp`: {
+			`hashicorp`,
+			``,
+		},
+		`terraform-modules`: {
+			`terraform-modules`,
+			``,
+		},
+		`-`: {
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		`?`: {
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+	}
+
+	for given, test := range tests {
+		t.Run(given, func(t *testing.T) {
+			got, err := ParseProviderNamespace(given)
+			if test.Error != "" {
+				if err == nil {
+					t.Errorf("unexpected success\nwant error: %s", test.Error)
+				} else if got := err.Error(); got != test.Error {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestParseProviderType(t *testing.T) {
+	tests := map[string]struct {
+		Want  ProviderType
+		Error string
+	}{
+		`aws`: {
+			`aws`,
+			``,
+		},
+		`Google-Beta`: {
+			`google-beta`,
+			``,
+		},
+		`terraform-provider-aws`: {
+			``,
+			`must not have the redundant prefix "terraform-"`,
+		},
+		`bad.type`: {
+			``,
+			`dots are not allowed`,
+		},
+	}
+
+	for given, test := range tests {
+		t.Run(given, func(t *testing.T) {
+			got, err := ParseProviderType(given)
+			if test.Error != "" {
+				if err == nil {
+					t.Errorf("unexpected success\nwant error: %s", test.Error)
+				} else if got := err.Error(); got != test.Error {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestProviderPartValidate(t *testing.T) {
+	tests := map[string]struct {
+		Validate func() error
+		Error    string
+	}{
+		"valid namespace": {
+			ProviderNamespace("hashicorp").Validate,
+			``,
+		},
+		"non-normalized namespace": {
+			ProviderNamespace("HashiCorp").Validate,
+			`must be given in normalized form "hashicorp"`,
+		},
+		"legacy namespace": {
+			ProviderNamespace(LegacyProviderNamespace).Validate,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"valid type": {
+			ProviderType("aws").Validate,
+			``,
+		},
+		"non-normalized type": {
+			ProviderType("AWS").Validate,
+			`must be given in normalized form "aws"`,
+		},
+		"empty type": {
+			ProviderType("").Validate,
+			`must have at least one character`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.Validate()
+			switch {
+			case test.Error == "" && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case test.Error != "" && err == nil:
+				t.Errorf("unexpected success\nwant error: %s", test.Error)
+			case test.Error != "" && err.Error() != test.Error:
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", err, test.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// ProviderPatternWildcard is the segment of a ProviderPattern that matches
+// any value.
+const ProviderPatternWildcard = "*"
+
+// ProviderPattern is a pattern matching a set of provider addresses, as
+// used in the "include" and "exclude" arguments of the provider_installation
+// blocks in the Terraform CLI configuration, such as
+// "registry.terraform.io/hashicorp/*".
+//
+// Each field is either a specific value, normalized as in Provider, or
+// ProviderPatternWildcard.
+type ProviderPattern struct {
+	Hostname  svchost.Hostname
+	Namespace string
+	Type      string
+}
+
+// ParseProviderPattern parses a provider matching pattern.
+//
+// A pattern has the same form as a provider source address with either two
+// or three segments, except that any segment may be the wildcard "*". As
+// with source addresses, the hostname defaults to
+// DefaultProviderRegistryHost if omitted. As in Terraform CLI, a wildcard
+// namespace must be followed by a wildcard type, so "*/aws" is not valid,
+// but the hostname may be a wildcard on its own, as in "*/hashicorp/*" or
+// "*/hashicorp/aws".
+func ParseProviderPattern(str string) (ProviderPattern, error) {
+	parts := strings.Split(str, "/")
+	if len(parts) != 2 && len(parts) != 3 {
+		return ProviderPattern{}, fmt.Errorf("invalid provider matching pattern %q: must have either two or three slash-separated segments", str)
+	}
+
+	ret := ProviderPattern{Hostname: DefaultProviderRegistryHost}
+	if len(parts) == 3 {
+		if parts[0] == ProviderPatternWildcard {
+			ret.Hostname = svchost.Hostname(ProviderPatternWildcard)
+		} else {
+			host, err := parseHostname(parts[0])
+			if err != nil {
+				return ProviderPattern{}, fmt.Errorf("invalid hostname in provider matching pattern %q: %s", str, err)
+			}
+			ret.Hostname = host
+		}
+		parts = parts[1:]
+	}
+
+	if parts[0] == ProviderPatternWildcard && parts[1] != ProviderPatternWildcard {
+		return ProviderPattern{}, fmt.Errorf("invalid provider matching pattern %q: the namespace can be a wildcard only if the type is also a wildcard", str)
+	}
+	segments := []struct {
+		name   string
+		given  string
+		target *string
+	}{
+		{"namespace", parts[0], &ret.Namespace},
+		{"type", parts[1], &ret.Type},
+	}
+	for _, segment := range segments {
+		if segment.given == ProviderPatternWildcard {
+			*segment.target = ProviderPatternWildcard
+			continue
+		}
+		normalized, err := ParseProviderPart(segment.given)
+		if err != nil {
+			return ProviderPattern{}, fmt.Errorf("invalid %s in provider matching pattern %q: %s", segment.name, str, err)
+		}
+		*segment.target = normalized
+	}
+	return ret, nil
+}
+
+// MustParseProviderPattern is a wrapper around ParseProviderPattern that
+// panics if it returns an error.
+func MustParseProviderPattern(str string) ProviderPattern {
+	ret, err := ParseProviderPattern(str)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// Matches returns true if the given provider address matches the receiver.
+func (p ProviderPattern) Matches(addr Provider) bool {
+	return (p.Hostname == ProviderPatternWildcard || p.Hostname == addr.Hostname) &&
+		(p.Namespace == ProviderPatternWildcard || p.Namespace == addr.Namespace) &&
+		(p.Type == ProviderPatternWildcard || p.Type == addr.Type)
+}
+
+// String returns the pattern in its three-segment form, such as
+// "registry.terraform.io/hashicorp/*".
+func (p ProviderPattern) String() string {
+	host := ProviderPatternWildcard
+	if p.Hostname != ProviderPatternWildcard {
+		host = p.Hostname.ForDisplay()
+	}
+	return host + "/" + p.Namespace + "/" + p.Type
+}
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestParseProviderPattern(t *testing.T) {
+	tests := map[string]struct {
+		want    string
+		wantErr string
+	}{
+		"hashicorp/aws": {
+			want: "registry.terraform.io/hashicorp/aws",
+		},
+		"HashiCorp/*": {
+			want: "registry.terraform.io/hashicorp/*",
+		},
+		"example.com/*/*": {
+			want: "example.com/*/*",
+		},
+		"*/*/*": {
+			want: "*/*/*",
+		},
+		"*/*": {
+			want: "registry.terraform.io/*/*",
+		},
+		"aws": {
+			wantErr: `invalid provider matching pattern "aws": must have either two or three slash-separated segments`,
+		},
+		"*/hashicorp/*": {
+			want: "*/hashicorp/*",
+		},
+		"*/hashicorp/aws": {
+			want: "*/hashicorp/aws",
+		},
+		"*/aws": {
+			wantErr: `invalid provider matching pattern "*/aws": the namespace can be a wildcard only if the type is also a wildcard`,
+		},
+		"example.com/*/aws": {
+			wantErr: `invalid provider matching pattern "example.com/*/aws": the namespace can be a wildcard only if the type is also a wildcard`,
+		},
+		"hashicorp/a*": {
+			wantErr: `invalid type in provider matching pattern "hashicorp/a*": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"exa mple.com/hashicorp/aws": {
+			wantErr: `invalid hostname in provider matching pattern "exa mple.com/hashicorp/aws": idna: disallowed rune U+0020`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseProviderPattern(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success: %s", got)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := got.String(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestProviderPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		provider string
+		want     bool
+	}{
+		{"hashicorp/aws", "hashicorp/aws", true},
+		{"hashicorp/aws", "hashicorp/google", false},
+		{"hashicorp/*", "hashicorp/google", true},
+		{"hashicorp/*", "integrations/github", false},
+		{"registry.terraform.io/*/*", "integrations/github", true},
+		{"registry.terraform.io/*/*", "example.com/awesomecorp/happycloud", false},
+		{"example.com/*/*", "example.com/awesomecorp/happycloud", true},
+		{"*/*/*", "example.com/awesomecorp/happycloud", true},
+		{"*/*/*", "terraform", true},
+		{"*/hashicorp/*", "example.com/hashicorp/aws", true},
+		{"*/hashicorp/*", "example.com/awesomecorp/aws", false},
+	}
+
+	for _, test := range tests {
+		pattern := MustParseProviderPattern(test.pattern)
+		provider := MustParseProviderSource(test.provider)
+		if got := pattern.Matches(provider); got != test.want {
+			t.Errorf("%s matching %s: got %t, want %t", test.pattern, test.provider, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ProviderRedirectKind describes why a registry redirected from one provider
+// address to another.
+type ProviderRedirectKind string
+
+const (
+	// ProviderAlias is a redirect from an alternative name for a provider to
+	// its canonical address on the same registry, such as from the legacy
+	// address "-/aws" to "hashicorp/aws".
+	ProviderAlias ProviderRedirectKind = "alias"
+
+	// ProviderMoved is a redirect from the former address of a provider to
+	// the address it has moved to, which may be on a different registry.
+	ProviderMoved ProviderRedirectKind = "moved"
+)
+
+// ProviderRedirect describes a registry's instruction to use the provider
+// at address To in place of the provider at address From.
+type ProviderRedirect struct {
+	From Provider
+	To   Provider
+	Kind ProviderRedirectKind
+}
+
+func (r ProviderRedirect) String() string {
+	return fmt.Sprintf("%s %s to %s", r.From.ForDisplay(), r.Kind, r.To.ForDisplay())
+}
+
+// ProviderVersionsResponse is the subset of the response from the provider
+// registry protocol's "versions" operation that describes where a provider
+// can be found, rather than its available versions.
+//
+// The public registry uses these fields to report that a provider address
+// is an alias of another address, by returning a different canonical ID,
+// or that the provider has moved, using MovedTo.
+type ProviderVersionsResponse struct {
+	// ID is the canonical "namespace/type" address of the provider on the
+	// registry that returned the response.
+	ID string `json:"id"`
+
+	// MovedTo, if non-empty, is the source address of the provider that
+	// should be used instead of the requested one.
+	MovedTo string `json:"moved_to"`
+
+	// Warnings are human-readable messages that the registry asks clients
+	// to show to the user.
+	Warnings []string `json:"warnings"`
+}
+
+// DecodeProviderVersionsResponse decodes a JSON response from the provider
+// registry protocol's "versions" operation, as described for
+// ProviderVersionsResponse.
+func DecodeProviderVersionsResponse(r io.Reader) (*ProviderVersionsResponse, error) {
+	var ret ProviderVersionsResponse
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("invalid provider versions response: %s", err)
+	}
+	return &ret, nil
+}
+
+// Redirects returns the redirects described by the response, in the order
+// they should be followed, given the address of the provider whose
+// versions were requested. The To address of the last redirect is the
+// address the caller should use.
+//
+// The result is empty if the registry indicated neither an alias nor a
+// move, in which case the requested address is already correct.
+func (r *ProviderVersionsResponse) Redirects(requested Provider) ([]ProviderRedirect, error) {
+	var ret []ProviderRedirect
+	current := requested
+
+	if r.ID != "" {
+		id := r.ID
+		if strings.Count(id, "/") == 1 {
+			id = requested.Hostname.ForDisplay() + "/" + id
+		}
+		canonical, err := ParseProviderSource(id)
+		if err != nil {
+			return nil, fmt.Errorf("registry returned invalid provider ID %q: %s", r.ID, err)
+		}
+		if canonical.Hostname != requested.Hostname {
+			return nil, fmt.Errorf("registry returned provider ID %q for a different registry", r.ID)
+		}
+		if canonical != current {
+			ret = append(ret, ProviderRedirect{From: current, To: canonical, Kind: ProviderAlias})
+			current = canonical
+		}
+	}
+
+	if r.MovedTo != "" {
+		moved, err := ParseProviderSource(r.MovedTo)
+		if err != nil {
+			return nil, fmt.Errorf("registry returned invalid moved_to address %q: %s", r.MovedTo, err)
+		}
+		if moved.IsLegacy() {
+			return nil, fmt.Errorf("registry returned legacy moved_to address %q", r.MovedTo)
+		}
+		if moved != current {
+			ret = append(ret, ProviderRedirect{From: current, To: moved, Kind: ProviderMoved})
+		}
+	}
+
+	return ret, nil
+}
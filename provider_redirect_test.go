@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderVersionsResponseRedirects(t *testing.T) {
+	legacyAWS := MustParseProviderSource("-/aws")
+	hashicorpAWS := MustParseProviderSource("hashicorp/aws")
+
+	tests := map[string]struct {
+		requested Provider
+		body      string
+		want      []ProviderRedirect
+		wantErr   string
+	}{
+		"no redirect": {
+			requested: hashicorpAWS,
+			body:      `{"id":"hashicorp/aws","versions":[{"version":"5.0.0"}]}`,
+			want:      nil,
+		},
+		"no id": {
+			requested: hashicorpAWS,
+			body:      `{"versions":[]}`,
+			want:      nil,
+		},
+		"legacy alias": {
+			requested: legacyAWS,
+			body:      `{"id":"hashicorp/aws"}`,
+			want: []ProviderRedirect{
+				{From: legacyAWS, To: hashicorpAWS, Kind: ProviderAlias},
+			},
+		},
+		"case differences": {
+			requested: hashicorpAWS,
+			body:      `{"id":"HashiCorp/AWS"}`,
+			want:      nil,
+		},
+		"moved": {
+			requested: MustParseProviderSource("terraform-providers/aws"),
+			body:      `{"id":"terraform-providers/aws","moved_to":"hashicorp/aws"}`,
+			want: []ProviderRedirect{
+				{From: MustParseProviderSource("terraform-providers/aws"), To: hashicorpAWS, Kind: ProviderMoved},
+			},
+		},
+		"alias then moved to other registry": {
+			requested: legacyAWS,
+			body:      `{"id":"hashicorp/aws","moved_to":"example.com/acme/aws"}`,
+			want: []ProviderRedirect{
+				{From: legacyAWS, To: hashicorpAWS, Kind: ProviderAlias},
+				{From: hashicorpAWS, To: MustParseProviderSource("example.com/acme/aws"), Kind: ProviderMoved},
+			},
+		},
+		"id for other registry": {
+			requested: hashicorpAWS,
+			body:      `{"id":"example.com/hashicorp/aws"}`,
+			wantErr:   `registry returned provider ID "example.com/hashicorp/aws" for a different registry`,
+		},
+		"invalid moved_to": {
+			requested: hashicorpAWS,
+			body:      `{"moved_to":"not/a/valid/address"}`,
+			wantErr:   `registry returned invalid moved_to address "not/a/valid/address": Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name". The given string "not/a/valid/address" looks like a module source address, which belongs in the "source" argument of a module block instead.`,
+		},
+		"legacy moved_to": {
+			requested: hashicorpAWS,
+			body:      `{"moved_to":"-/aws"}`,
+			wantErr:   `registry returned legacy moved_to address "-/aws"`,
+		},
+		"malformed": {
+			requested: hashicorpAWS,
+			body:      `{"id":`,
+			wantErr:   `invalid provider versions response: unexpected EOF`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := DecodeProviderVersionsResponse(strings.NewReader(test.body))
+			var got []ProviderRedirect
+			if err == nil {
+				got, err = resp.Redirects(test.requested)
+			}
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
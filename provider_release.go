@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ProviderReleaseAssetKind identifies which of the files that make up a
+// provider release a ProviderReleaseAsset is.
+type ProviderReleaseAssetKind int
+
+const (
+	// ProviderReleaseArchive is the zip archive of the provider for a
+	// single platform, such as "terraform-provider-aws_4.0.0_linux_amd64.zip".
+	ProviderReleaseArchive ProviderReleaseAssetKind = iota + 1
+
+	// ProviderReleaseChecksums is the file of SHA-256 checksums of all of
+	// the archives in a release, such as
+	// "terraform-provider-aws_4.0.0_SHA256SUMS".
+	ProviderReleaseChecksums
+
+	// ProviderReleaseChecksumsSignature is the detached GPG signature of
+	// the checksums file, such as
+	// "terraform-provider-aws_4.0.0_SHA256SUMS.sig".
+	ProviderReleaseChecksumsSignature
+
+	// ProviderReleaseManifest is the registry manifest that declares the
+	// protocol versions the provider supports, such as
+	// "terraform-provider-aws_4.0.0_manifest.json".
+	ProviderReleaseManifest
+)
+
+// ProviderReleaseAsset is one of the files that a provider publisher
+// attaches to a release so that a provider registry can ingest it.
+//
+// Release asset names include only the provider type, and not its
+// hostname or namespace, because those are decided by the registry that
+// the release is published to.
+type ProviderReleaseAsset struct {
+	Kind    ProviderReleaseAssetKind
+	Type    string
+	Version string
+
+	// Platform is the platform of an archive, in Terraform's usual
+	// "os_arch" form, or empty for the other kinds of asset, which are
+	// shared by all platforms.
+	Platform string
+}
+
+var (
+	releaseVersionPattern  = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+	releasePlatformPattern = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9]+$`)
+)
+
+const (
+	releaseAssetPrefix        = "terraform-provider-"
+	releaseChecksumsSuffix    = "SHA256SUMS"
+	releaseChecksumsSigSuffix = "SHA256SUMS.sig"
+	releaseManifestSuffix     = "manifest.json"
+	releaseArchiveExtension   = ".zip"
+)
+
+// Filename returns the name of the file for the receiving asset, such as
+// "terraform-provider-aws_4.0.0_linux_amd64.zip".
+func (a ProviderReleaseAsset) Filename() string {
+	base := releaseAssetPrefix + a.Type + "_" + a.Version + "_"
+	switch a.Kind {
+	case ProviderReleaseArchive:
+		return base + a.Platform + releaseArchiveExtension
+	case ProviderReleaseChecksums:
+		return base + releaseChecksumsSuffix
+	case ProviderReleaseChecksumsSignature:
+		return base + releaseChecksumsSigSuffix
+	case ProviderReleaseManifest:
+		return base + releaseManifestSuffix
+	default:
+		panic(fmt.Sprintf("unsupported provider release asset kind %d", a.Kind))
+	}
+}
+
+// ProviderReleaseAssets returns the full set of assets that a release of
+// the given version of the given provider must include for the given
+// platforms: one archive per platform, in the given order, followed by
+// the checksums file, its signature, and the registry manifest.
+//
+// The version must be a semantic version without a "v" prefix, such as
+// "4.0.0", and each platform must be in Terraform's usual "os_arch" form.
+// Built-in providers have no releases, so ProviderReleaseAssets returns an
+// error for them.
+func ProviderReleaseAssets(provider Provider, version string, platforms []string) ([]ProviderReleaseAsset, error) {
+	if provider.IsZero() {
+		return nil, fmt.Errorf("provider address must not be the zero value")
+	}
+	if provider.IsBuiltIn() {
+		return nil, fmt.Errorf("built-in provider %s has no release assets", provider.ForDisplay())
+	}
+	if !releaseVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid provider release version %q: must be a semantic version such as \"1.0.0\"", version)
+	}
+
+	ret := make([]ProviderReleaseAsset, 0, len(platforms)+3)
+	for _, platform := range platforms {
+		if !releasePlatformPattern.MatchString(platform) {
+			return nil, fmt.Errorf("invalid platform %q: must be in the form \"os_arch\"", platform)
+		}
+		ret = append(ret, ProviderReleaseAsset{
+			Kind:     ProviderReleaseArchive,
+			Type:     provider.Type,
+			Version:  version,
+			Platform: platform,
+		})
+	}
+	for _, kind := range []ProviderReleaseAssetKind{ProviderReleaseChecksums, ProviderReleaseChecksumsSignature, ProviderReleaseManifest} {
+		ret = append(ret, ProviderReleaseAsset{
+			Kind:    kind,
+			Type:    provider.Type,
+			Version: version,
+		})
+	}
+	return ret, nil
+}
+
+// ParseProviderReleaseAssetName parses the name of a file in a provider
+// release, which is the reverse of ProviderReleaseAsset.Filename.
+//
+// The result has the provider type only, which callers can combine with a
+// hostname and namespace using NewProvider.
+func ParseProviderReleaseAssetName(name string) (ProviderReleaseAsset, error) {
+	var ret ProviderReleaseAsset
+	if !strings.HasPrefix(name, releaseAssetPrefix) {
+		return ret, fmt.Errorf("provider release asset name %q must start with %q", name, releaseAssetPrefix)
+	}
+	parts := strings.SplitN(name[len(releaseAssetPrefix):], "_", 3)
+	if len(parts) != 3 {
+		return ret, fmt.Errorf("provider release asset name %q must include the provider type and version", name)
+	}
+	typeName, err := ParseProviderPart(parts[0])
+	if err != nil {
+		return ret, fmt.Errorf("invalid provider type in release asset name %q: %s", name, err)
+	}
+	if !releaseVersionPattern.MatchString(parts[1]) {
+		return ret, fmt.Errorf("invalid version %q in provider release asset name %q", parts[1], name)
+	}
+	ret.Type = typeName
+	ret.Version = parts[1]
+
+	switch rest := parts[2]; rest {
+	case releaseChecksumsSuffix:
+		ret.Kind = ProviderReleaseChecksums
+	case releaseChecksumsSigSuffix:
+		ret.Kind = ProviderReleaseChecksumsSignature
+	case releaseManifestSuffix:
+		ret.Kind = ProviderReleaseManifest
+	default:
+		platform := strings.TrimSuffix(rest, releaseArchiveExtension)
+		if platform == rest || !releasePlatformPattern.MatchString(platform) {
+			return ProviderReleaseAsset{}, fmt.Errorf("unrecognized provider release asset name %q", name)
+		}
+		ret.Kind = ProviderReleaseArchive
+		ret.Platform = platform
+	}
+	return ret, nil
+}
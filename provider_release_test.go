@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderReleaseAssets(t *testing.T) {
+	provider := MustParseProviderSource("hashicorp/aws")
+	assets, err := ProviderReleaseAssets(provider, "4.0.0", []string{"linux_amd64", "darwin_arm64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got []string
+	for _, asset := range assets {
+		got = append(got, asset.Filename())
+
+		parsed, err := ParseProviderReleaseAssetName(asset.Filename())
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", asset.Filename(), err)
+			continue
+		}
+		if diff := cmp.Diff(asset, parsed); diff != "" {
+			t.Errorf("wrong result parsing %q\n%s", asset.Filename(), diff)
+		}
+	}
+	want := []string{
+		"terraform-provider-aws_4.0.0_linux_amd64.zip",
+		"terraform-provider-aws_4.0.0_darwin_arm64.zip",
+		"terraform-provider-aws_4.0.0_SHA256SUMS",
+		"terraform-provider-aws_4.0.0_SHA256SUMS.sig",
+		"terraform-provider-aws_4.0.0_manifest.json",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong asset names\n%s", diff)
+	}
+}
+
+func TestProviderReleaseAssetsErrors(t *testing.T) {
+	tests := map[string]struct {
+		provider  Provider
+		version   string
+		platforms []string
+		wantErr   string
+	}{
+		"zero provider": {
+			Provider{},
+			"1.0.0",
+			nil,
+			`provider address must not be the zero value`,
+		},
+		"built-in provider": {
+			MustParseProviderSource("terraform.io/builtin/terraform"),
+			"1.0.0",
+			nil,
+			`built-in provider terraform.io/builtin/terraform has no release assets`,
+		},
+		"version with prefix": {
+			MustParseProviderSource("hashicorp/aws"),
+			"v1.0.0",
+			nil,
+			`invalid provider release version "v1.0.0": must be a semantic version such as "1.0.0"`,
+		},
+		"bad platform": {
+			MustParseProviderSource("hashicorp/aws"),
+			"1.0.0",
+			[]string{"linux-amd64"},
+			`invalid platform "linux-amd64": must be in the form "os_arch"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ProviderReleaseAssets(test.provider, test.version, test.platforms)
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got := err.Error(); got != test.wantErr {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseProviderReleaseAssetName(t *testing.T) {
+	tests := map[string]struct {
+		want    ProviderReleaseAsset
+		wantErr string
+	}{
+		"terraform-provider-google-beta_5.1.0-rc1_windows_386.zip": {
+			want: ProviderReleaseAsset{
+				Kind:     ProviderReleaseArchive,
+				Type:     "google-beta",
+				Version:  "5.1.0-rc1",
+				Platform: "windows_386",
+			},
+		},
+		"terraform-provider-aws_4.0.0_SHA256SUMS.sig": {
+			want: ProviderReleaseAsset{
+				Kind:    ProviderReleaseChecksumsSignature,
+				Type:    "aws",
+				Version: "4.0.0",
+			},
+		},
+		"terraform-aws_4.0.0_SHA256SUMS": {
+			wantErr: `provider release asset name "terraform-aws_4.0.0_SHA256SUMS" must start with "terraform-provider-"`,
+		},
+		"terraform-provider-aws": {
+			wantErr: `provider release asset name "terraform-provider-aws" must include the provider type and version`,
+		},
+		"terraform-provider-aws_4.0_linux_amd64.zip": {
+			wantErr: `invalid version "4.0" in provider release asset name "terraform-provider-aws_4.0_linux_amd64.zip"`,
+		},
+		"terraform-provider-aws_4.0.0_linux_amd64.tar.gz": {
+			wantErr: `unrecognized provider release asset name "terraform-provider-aws_4.0.0_linux_amd64.tar.gz"`,
+		},
+		"terraform-provider-aws_4.0.0_README.md": {
+			wantErr: `unrecognized provider release asset name "terraform-provider-aws_4.0.0_README.md"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseProviderReleaseAssetName(name)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success: %#v", got)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
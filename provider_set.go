@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+// ProviderSet is an unordered set of provider addresses.
+//
+// ProviderSet is a map so that it can be constructed with a literal and
+// ranged over directly, but ranging over it visits addresses in an
+// unpredictable order. Use Sorted for a deterministic order. A nil
+// ProviderSet is an empty set, but Add panics on it, like assigning to a
+// nil map.
+type ProviderSet map[Provider]struct{}
+
+// NewProviderSet returns a ProviderSet containing the given addresses.
+func NewProviderSet(providers ...Provider) ProviderSet {
+	ret := make(ProviderSet, len(providers))
+	for _, p := range providers {
+		ret.Add(p)
+	}
+	return ret
+}
+
+// Add adds the given address to the set, if it isn't already present.
+//
+// Add panics if given the zero value of Provider.
+func (s ProviderSet) Add(p Provider) {
+	if p.IsZero() {
+		panic("called Add with zero-value addrs.Provider")
+	}
+	s[p] = struct{}{}
+}
+
+// Remove removes the given address from the set, if it is present.
+func (s ProviderSet) Remove(p Provider) {
+	delete(s, p)
+}
+
+// Has returns true if the given address is in the set.
+func (s ProviderSet) Has(p Provider) bool {
+	_, exists := s[p]
+	return exists
+}
+
+// Union returns a new set containing the addresses that are in either the
+// receiver or the other given set.
+func (s ProviderSet) Union(other ProviderSet) ProviderSet {
+	ret := make(ProviderSet, len(s)+len(other))
+	for p := range s {
+		ret[p] = struct{}{}
+	}
+	for p := range other {
+		ret[p] = struct{}{}
+	}
+	return ret
+}
+
+// Intersect returns a new set containing the addresses that are in both
+// the receiver and the other given set.
+func (s ProviderSet) Intersect(other ProviderSet) ProviderSet {
+	ret := make(ProviderSet)
+	for p := range s {
+		if other.Has(p) {
+			ret[p] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// Difference returns a new set containing the addresses that are in the
+// receiver but not in the other given set.
+func (s ProviderSet) Difference(other ProviderSet) ProviderSet {
+	ret := make(ProviderSet)
+	for p := range s {
+		if !other.Has(p) {
+			ret[p] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// Sorted returns the addresses in the set in the order defined by
+// Provider.LessThan, as a new slice.
+func (s ProviderSet) Sorted() []Provider {
+	if len(s) == 0 {
+		return nil
+	}
+	ret := make([]Provider, 0, len(s))
+	for p := range s {
+		ret = append(ret, p)
+	}
+	SortProviders(ret)
+	return ret
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderSet(t *testing.T) {
+	aws := MustParseProviderSource("hashicorp/aws")
+	google := MustParseProviderSource("hashicorp/google")
+	null := MustParseProviderSource("hashicorp/null")
+	happycloud := MustParseProviderSource("example.com/awesomecorp/happycloud")
+
+	a := NewProviderSet(null, aws, google)
+	b := NewProviderSet(google, happycloud, google)
+
+	tests := map[string]struct {
+		got  ProviderSet
+		want []Provider
+	}{
+		"union": {
+			a.Union(b),
+			[]Provider{happycloud, aws, google, null},
+		},
+		"intersect": {
+			a.Intersect(b),
+			[]Provider{google},
+		},
+		"difference": {
+			a.Difference(b),
+			[]Provider{aws, null},
+		},
+		"empty difference": {
+			b.Difference(b),
+			nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, test.got.Sorted()); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+
+	if !a.Has(aws) || a.Has(happycloud) {
+		t.Errorf("wrong result from Has")
+	}
+	a.Remove(aws)
+	if a.Has(aws) || len(a) != 2 {
+		t.Errorf("Remove did not remove %s", aws)
+	}
+	if len(b) != 2 {
+		t.Errorf("operations modified their operands")
+	}
+
+	var empty ProviderSet
+	if empty.Has(aws) || empty.Sorted() != nil {
+		t.Errorf("wrong result from nil set")
+	}
+}
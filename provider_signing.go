@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SigningKey describes a GPG public key that a provider registry reports
+// as having signed a provider release, in the "signing_keys" property of
+// the provider registry protocol's "download" operation.
+type SigningKey struct {
+	// KeyID is the ID of the key, as a hexadecimal string.
+	KeyID string `json:"key_id"`
+
+	// ASCIIArmor is the public key in ASCII-armored form.
+	ASCIIArmor string `json:"ascii_armor"`
+
+	// TrustSignature, if set, is an ASCII-armored signature of the key by
+	// a key that the client already trusts, such as HashiCorp's key for
+	// partner providers.
+	TrustSignature string `json:"trust_signature,omitempty"`
+
+	// Source and SourceURL describe who the key belongs to.
+	Source    string `json:"source,omitempty"`
+	SourceURL string `json:"source_url,omitempty"`
+}
+
+// ProviderSigner associates the signing keys that a registry reported for
+// a provider with the provider's address.
+type ProviderSigner struct {
+	Provider Provider
+	Keys     []SigningKey
+}
+
+// DecodeProviderSigningKeys decodes the signing keys from a JSON response
+// from the provider registry protocol's "download" operation for the given
+// provider.
+//
+// The response must include at least one signing key, because a client
+// can't verify a release that isn't signed.
+func DecodeProviderSigningKeys(provider Provider, r io.Reader) (*ProviderSigner, error) {
+	var raw struct {
+		SigningKeys struct {
+			GPGPublicKeys []SigningKey `json:"gpg_public_keys"`
+		} `json:"signing_keys"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid provider download response: %s", err)
+	}
+	keys := raw.SigningKeys.GPGPublicKeys
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid provider download response: no signing keys for %s", provider.ForDisplay())
+	}
+	for i, key := range keys {
+		if key.KeyID == "" || key.ASCIIArmor == "" {
+			return nil, fmt.Errorf("invalid provider download response: signing key %d for %s must have both key_id and ascii_armor", i, provider.ForDisplay())
+		}
+	}
+	return &ProviderSigner{Provider: provider, Keys: keys}, nil
+}
+
+// Key returns the signing key with the given ID, and true, or false if
+// there is no such key. Key IDs are compared case-insensitively, because
+// they are hexadecimal.
+func (s *ProviderSigner) Key(id string) (SigningKey, bool) {
+	for _, key := range s.Keys {
+		if strings.EqualFold(key.KeyID, id) {
+			return key, true
+		}
+	}
+	return SigningKey{}, false
+}
+
+// KeyIDs returns the IDs of the signing keys, in the order the registry
+// reported them.
+func (s *ProviderSigner) KeyIDs() []string {
+	ret := make([]string, len(s.Keys))
+	for i, key := range s.Keys {
+		ret[i] = key.KeyID
+	}
+	return ret
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeProviderSigningKeys(t *testing.T) {
+	const body = `{
+  "protocols": ["5.0"],
+  "os": "linux",
+  "arch": "amd64",
+  "filename": "terraform-provider-aws_5.0.0_linux_amd64.zip",
+  "signing_keys": {
+    "gpg_public_keys": [
+      {
+        "key_id": "34365D9472D7468F",
+        "ascii_armor": "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...",
+        "trust_signature": "",
+        "source": "HashiCorp",
+        "source_url": "https://www.hashicorp.com/security.html"
+      }
+    ]
+  }
+}`
+	aws := MustParseProviderSource("hashicorp/aws")
+	got, err := DecodeProviderSigningKeys(aws, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := &ProviderSigner{
+		Provider: aws,
+		Keys: []SigningKey{
+			{
+				KeyID:      "34365D9472D7468F",
+				ASCIIArmor: "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...",
+				Source:     "HashiCorp",
+				SourceURL:  "https://www.hashicorp.com/security.html",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"34365D9472D7468F"}, got.KeyIDs()); diff != "" {
+		t.Errorf("wrong key IDs\n%s", diff)
+	}
+	if _, ok := got.Key("34365d9472d7468f"); !ok {
+		t.Error("key not found by lowercase ID")
+	}
+	if _, ok := got.Key("0000000000000000"); ok {
+		t.Error("found nonexistent key")
+	}
+}
+
+func TestDecodeProviderSigningKeysInvalid(t *testing.T) {
+	aws := MustParseProviderSource("hashicorp/aws")
+	tests := map[string]string{
+		`{}`: `invalid provider download response: no signing keys for hashicorp/aws`,
+		`{"signing_keys":{"gpg_public_keys":[{"key_id":"ABC"}]}}`: `invalid provider download response: signing key 0 for hashicorp/aws must have both key_id and ascii_armor`,
+		`[`: `invalid provider download response: unexpected EOF`,
+	}
+	for body, want := range tests {
+		_, err := DecodeProviderSigningKeys(aws, strings.NewReader(body))
+		if err == nil {
+			t.Errorf("unexpected success for %s", body)
+			continue
+		}
+		if got := err.Error(); got != want {
+			t.Errorf("wrong error for %s\ngot:  %s\nwant: %s", body, got, want)
+		}
+	}
+}
@@ -6,7 +6,9 @@ package tfaddr
 import (
 	"fmt"
 	"log"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/google/go-cmp/cmp"
 	svchost "github.com/hashicorp/terraform-svchost"
@@ -137,6 +139,64 @@ func TestProviderDisplay(t *testing.T) {
 	}
 }
 
+func TestProviderForRegistryProtocol(t *testing.T) {
+	tests := []struct {
+		Input Provider
+		Want  string
+	}{
+		{
+			Provider{
+				Type:      "test",
+				Hostname:  DefaultProviderRegistryHost,
+				Namespace: "hashicorp",
+			},
+			"hashicorp/test",
+		},
+		{
+			Provider{
+				Type:      "test",
+				Hostname:  "registry.terraform.com",
+				Namespace: "hashicorp",
+			},
+			"hashicorp/test",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.Input.ForRegistryProtocol()
+		if got != test.Want {
+			t.Errorf("wrong result for %s: %q\n", test.Input.String(), got)
+		}
+	}
+}
+
+func TestProviderCacheKey(t *testing.T) {
+	tests := []struct {
+		Input Provider
+		Want  string
+	}{
+		{
+			MustParseProviderSource("hashicorp/aws"),
+			"registry.terraform.io/hashicorp/aws/5.0.0/linux_amd64",
+		},
+		{
+			MustParseProviderSource("example.com:8443/foo/bar"),
+			"example.com:8443/foo/bar/5.0.0/linux_amd64",
+		},
+		{
+			MustParseProviderSource("испытание.com/foo/bar"),
+			"испытание.com/foo/bar/5.0.0/linux_amd64",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.Input.CacheKey("5.0.0", "linux_amd64")
+		if got != test.Want {
+			t.Errorf("wrong result for %s: %q\n", test.Input.String(), got)
+		}
+	}
+}
+
 func TestProviderIsBuiltIn(t *testing.T) {
 	tests := []struct {
 		Input Provider
@@ -253,7 +313,7 @@ func ExampleParseProviderSource() {
 		log.Fatal(err)
 	}
 	fmt.Printf("%#v", pAddr)
-	// Output: tfaddr.Provider{Type:"aws", Namespace:"hashicorp", Hostname:svchost.Hostname("registry.terraform.io")}
+	// Output: tfaddr.NewProvider("registry.terraform.io", "hashicorp", "aws")
 }
 
 func TestParseProviderSource(t *testing.T) {
@@ -369,6 +429,10 @@ func TestParseProviderSource(t *testing.T) {
 			Provider{},
 			true,
 		},
+		strings.Repeat("a.", 127) + "com/hashicorp/aws": { // too long to be a DNS name
+			Provider{},
+			true,
+		},
 		"badhost!/hashicorp/aws": {
 			Provider{},
 			true,
@@ -503,6 +567,30 @@ func TestParseProviderPart(t *testing.T) {
 			``,
 			`must have at least one character`,
 		},
+		`ab😀`: { // emoji are accepted by IDNA, but are not letters
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		`snow☃man`: {
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		`a˄b`: { // U+02C4 is a modifier symbol, not a modifier letter
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		`a·b`: { // middle dot is punctuation, even though IDNA allows it
+			``,
+			`must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		`a①`: { // IDNA maps circled digits to plain digits
+			`a1`,
+			``,
+		},
+		`नमस्ते`: { // includes combining marks, which are part of the letters
+			`नमस्ते`,
+			``,
+		},
 	}
 
 	for given, test := range tests {
@@ -525,6 +613,63 @@ func TestParseProviderPart(t *testing.T) {
 	}
 }
 
+func TestIsValidProviderPartRune(t *testing.T) {
+	// We check at least one character from every Unicode general category,
+	// so that adding support for a new category is always a deliberate
+	// decision.
+	wantCategories := map[string]bool{
+		"Lu": true, "Ll": true, "Lt": true, "Lm": true, "Lo": true, "LC": true,
+		"Mn": true, "Mc": true, "Me": false,
+		"Nd": true, "Nl": false, "No": false,
+		"Pc": false, "Pd": false, "Ps": false, "Pe": false, "Pi": false, "Pf": false, "Po": false,
+		"Sm": false, "Sc": false, "Sk": false, "So": false,
+		"Zs": false, "Zl": false, "Zp": false,
+		"Cc": false, "Cf": false, "Cn": false, "Co": false, "Cs": false,
+	}
+
+	for name, table := range unicode.Categories {
+		if len(name) != 2 {
+			// Skip the single-letter major categories, which are just
+			// unions of the two-letter categories we test below.
+			continue
+		}
+		want, ok := wantCategories[name]
+		if !ok {
+			t.Errorf("no expectation for Unicode category %s", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, r := range sampleRunes(table) {
+				if r == '-' {
+					// The dash is the one allowed character in Pd
+					continue
+				}
+				if got := IsValidProviderPartRune(r); got != want {
+					t.Errorf("wrong result for %U %q\ngot:  %t\nwant: %t", r, r, got, want)
+				}
+			}
+		})
+	}
+
+	if !IsValidProviderPartRune('-') {
+		t.Errorf("dash is not accepted")
+	}
+}
+
+// sampleRunes returns the first and last rune of each range in the given
+// table, which is enough to cover each table without testing every single
+// character.
+func sampleRunes(table *unicode.RangeTable) []rune {
+	var ret []rune
+	for _, r := range table.R16 {
+		ret = append(ret, rune(r.Lo), rune(r.Hi))
+	}
+	for _, r := range table.R32 {
+		ret = append(ret, rune(r.Lo), rune(r.Hi))
+	}
+	return ret
+}
+
 func TestProviderEquals(t *testing.T) {
 	tests := []struct {
 		InputP Provider
@@ -565,3 +710,247 @@ func TestProviderEquals(t *testing.T) {
 func TestValidateProviderAddress(t *testing.T) {
 	t.Skip("TODO")
 }
+
+func FuzzParseProviderSource(f *testing.F) {
+	f.Add("hashicorp/aws")
+	f.Add("registry.terraform.io/hashicorp/aws")
+	f.Add("Испытание.com:1234/HashiCorp/AWS")
+	f.Add("-/aws")
+	f.Add("aws")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		got, err := ParseProviderSource(input)
+		if err != nil {
+			return
+		}
+
+		// Any valid address must round-trip through its string
+		// representation, except for those with an unknown namespace
+		// which has no string representation of its own.
+		if !got.HasKnownNamespace() {
+			return
+		}
+		again, err := ParseProviderSource(got.String())
+		if err != nil {
+			t.Fatalf("failed to re-parse %q from %q: %s", got.String(), input, err)
+		}
+		if again != got {
+			t.Fatalf("wrong result re-parsing %q from %q\ngot:  %#v\nwant: %#v", got.String(), input, again, got)
+		}
+	})
+}
+
+func TestParseProviderSourceOrDefault(t *testing.T) {
+	def := NewProvider(DefaultProviderRegistryHost, "hashicorp", "default")
+
+	tests := map[string]struct {
+		Want Provider
+		Err  bool
+	}{
+		"example.com/foo/bar": {
+			NewProvider("example.com", "foo", "bar"),
+			false,
+		},
+		"hashicorp/aws": {
+			NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws"),
+			false,
+		},
+		"aws": {
+			def,
+			false,
+		},
+		"-/aws": {
+			def,
+			false,
+		},
+		"example.com/-/aws": {
+			Provider{},
+			true,
+		},
+		"badhost!/hashicorp/aws": {
+			Provider{},
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseProviderSourceOrDefault(name, def)
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if err != nil && !test.Err {
+				t.Errorf("got error: %s, expected success", err)
+			}
+			if err == nil && test.Err {
+				t.Errorf("got success, expected error")
+			}
+		})
+	}
+}
+
+func TestProviderWith(t *testing.T) {
+	base := NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws")
+	legacy := Provider{
+		Type:      "aws",
+		Namespace: LegacyProviderNamespace,
+		Hostname:  DefaultProviderRegistryHost,
+	}
+
+	tests := map[string]struct {
+		with    func() (Provider, error)
+		want    Provider
+		wantErr string
+	}{
+		"hostname": {
+			with: func() (Provider, error) { return base.WithHostname("Example.COM") },
+			want: NewProvider("example.com", "hashicorp", "aws"),
+		},
+		"invalid hostname": {
+			with:    func() (Provider, error) { return base.WithHostname("bad!host") },
+			wantErr: `invalid hostname "bad!host": idna: disallowed rune U+0021`,
+		},
+		"hostname of legacy provider": {
+			with:    func() (Provider, error) { return legacy.WithHostname("example.com") },
+			wantErr: `invalid hostname "example.com": the legacy provider namespace "-" can be used only with hostname registry.terraform.io`,
+		},
+		"namespace": {
+			with: func() (Provider, error) { return base.WithNamespace("OtherCorp") },
+			want: NewProvider(DefaultProviderRegistryHost, "othercorp", "aws"),
+		},
+		"namespace of legacy provider": {
+			with: func() (Provider, error) { return legacy.WithNamespace("hashicorp") },
+			want: base,
+		},
+		"legacy namespace": {
+			with:    func() (Provider, error) { return base.WithNamespace(LegacyProviderNamespace) },
+			wantErr: `invalid namespace "-": must contain only letters, digits, and dashes, and may not use leading or trailing dashes`,
+		},
+		"type": {
+			with: func() (Provider, error) { return base.WithType("AWS-Beta") },
+			want: NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws-beta"),
+		},
+		"invalid type": {
+			with:    func() (Provider, error) { return base.WithType("terraform-provider-aws") },
+			wantErr: `invalid type "terraform-provider-aws": must not have the redundant prefix "terraform-"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.with()
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+
+	if base != NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws") {
+		t.Errorf("receiver was modified")
+	}
+}
+
+func TestProviderShortestForm(t *testing.T) {
+	tests := map[string]string{
+		"hashicorp/aws":                       "hashicorp/aws",
+		"registry.terraform.io/hashicorp/aws": "hashicorp/aws",
+		"Example.com/HashiCorp/AWS":           "example.com/hashicorp/aws",
+		"aws":                                 "aws",
+		"-/aws":                               "-/aws",
+		"terraform.io/builtin/terraform":      "terraform.io/builtin/terraform",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			addr := MustParseProviderSource(input)
+			got := addr.ShortestForm()
+			if got != want {
+				t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+			if again := MustParseProviderSource(got); again != addr {
+				t.Errorf("result doesn't round-trip\ngot:  %#v\nwant: %#v", again, addr)
+			}
+		})
+	}
+}
+
+func TestMixedUpSourceAddressHints(t *testing.T) {
+	providerInputs := []string{
+		"hashicorp/consul/aws/foo",
+		"hashicorp/consul/aws//modules/consul-cluster",
+	}
+	for _, input := range providerInputs {
+		_, err := ParseProviderSource(input)
+		if err == nil {
+			t.Errorf("unexpected success parsing %q as a provider", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "looks like a module source address") {
+			t.Errorf("missing module hint for %q: %s", input, err)
+		}
+	}
+
+	if _, err := ParseModuleSource("hashicorp/aws"); err == nil {
+		t.Errorf("unexpected success parsing provider address as a module")
+	} else if !strings.Contains(err.Error(), "looks like a provider source address") {
+		t.Errorf("missing provider hint: %s", err)
+	}
+	if _, err := ParseModuleSource("hashicorp/aws//foo"); err == nil {
+		t.Errorf("unexpected success parsing two-part module address with subdirectory")
+	} else if strings.Contains(err.Error(), "looks like a provider source address") {
+		t.Errorf("unexpected provider hint: %s", err)
+	}
+}
+
+func TestSortProviders(t *testing.T) {
+	providers := []Provider{
+		MustParseProviderSource("hashicorp/google"),
+		MustParseProviderSource("example.com/awesomecorp/happycloud"),
+		MustParseProviderSource("terraform.io/builtin/terraform"),
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("integrations/github"),
+	}
+	SortProviders(providers)
+
+	var got []string
+	for _, p := range providers {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"example.com/awesomecorp/happycloud",
+		"registry.terraform.io/hashicorp/aws",
+		"registry.terraform.io/hashicorp/google",
+		"registry.terraform.io/integrations/github",
+		"terraform.io/builtin/terraform",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong order\n%s", diff)
+	}
+
+	for i := range providers {
+		for j := range providers {
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got := providers[i].Compare(providers[j]); got != want {
+				t.Errorf("%s.Compare(%s) = %d; want %d", providers[i], providers[j], got, want)
+			}
+		}
+	}
+}
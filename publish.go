@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PublishRules describes naming rules that a registry enforces when a new
+// provider or module package is published, which are typically stricter
+// than the rules for parsing addresses of packages that already exist.
+//
+// The validation methods of PublishRules check only these additional rules.
+// Addresses given to them should already have been validated by parsing,
+// or by a constructor such as NewProviderChecked.
+type PublishRules struct {
+	// ASCIIOnly requires the namespace and name to contain only ASCII
+	// characters. Provider addresses otherwise permit letters from any
+	// script.
+	ASCIIOnly bool
+
+	// RequireLowercase requires the namespace and name to contain no
+	// uppercase letters. Provider addresses are always normalized to
+	// lowercase, but module addresses preserve case.
+	RequireLowercase bool
+
+	// MaxNamespaceLength and MaxNameLength, if greater than zero, are the
+	// maximum lengths in characters of the namespace and of the provider
+	// type or module name, respectively.
+	MaxNamespaceLength int
+	MaxNameLength      int
+
+	// ReservedNamespaces and ReservedNames are namespaces and provider
+	// types or module names that can't be used for new packages. They are
+	// compared case-insensitively.
+	ReservedNamespaces []string
+	ReservedNames      []string
+}
+
+// DefaultPublishRules returns the rules recommended for registries that
+// don't have specific requirements of their own: ASCII-only lowercase
+// names of no more than 64 characters, with the namespace "builtin" and
+// the name "terraform" reserved to avoid confusion with Terraform's
+// built-in provider.
+func DefaultPublishRules() PublishRules {
+	return PublishRules{
+		ASCIIOnly:          true,
+		RequireLowercase:   true,
+		MaxNamespaceLength: 64,
+		MaxNameLength:      64,
+		ReservedNamespaces: []string{BuiltInProviderNamespace},
+		ReservedNames:      []string{"terraform"},
+	}
+}
+
+// ValidateProvider returns a *SegmentError if the given provider address
+// doesn't conform to the receiver's rules.
+func (r PublishRules) ValidateProvider(addr Provider) error {
+	if err := r.checkName(addr.Namespace, r.MaxNamespaceLength, r.ReservedNamespaces); err != nil {
+		return &SegmentError{Segment: "namespace", Value: addr.Namespace, Err: err}
+	}
+	if err := r.checkName(addr.Type, r.MaxNameLength, r.ReservedNames); err != nil {
+		return &SegmentError{Segment: "type", Value: addr.Type, Err: err}
+	}
+	return nil
+}
+
+// ValidateModulePackage returns a *SegmentError if the given module
+// package address doesn't conform to the receiver's rules.
+//
+// The target system is not checked, because the parsing rules already
+// require it to be lowercase ASCII.
+func (r PublishRules) ValidateModulePackage(addr ModulePackage) error {
+	if err := r.checkName(addr.Namespace, r.MaxNamespaceLength, r.ReservedNamespaces); err != nil {
+		return &SegmentError{Segment: "namespace", Value: addr.Namespace, Err: err}
+	}
+	if err := r.checkName(addr.Name, r.MaxNameLength, r.ReservedNames); err != nil {
+		return &SegmentError{Segment: "module name", Value: addr.Name, Err: err}
+	}
+	return nil
+}
+
+// checkName returns an error if the given name doesn't conform to the
+// receiver's character rules, is longer than maxLength characters when
+// maxLength is greater than zero, or is one of the given reserved names.
+func (r PublishRules) checkName(name string, maxLength int, reserved []string) error {
+	for _, c := range name {
+		if r.ASCIIOnly && c > unicode.MaxASCII {
+			return fmt.Errorf("must contain only ASCII characters")
+		}
+		if r.RequireLowercase && unicode.IsUpper(c) {
+			return fmt.Errorf("must not contain uppercase letters")
+		}
+	}
+	if err := (Limits{}).checkSegment(name, maxLength); err != nil {
+		return err
+	}
+	for _, n := range reserved {
+		if strings.EqualFold(name, n) {
+			return fmt.Errorf("is reserved")
+		}
+	}
+	return nil
+}
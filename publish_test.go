@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestPublishRulesValidateProvider(t *testing.T) {
+	tests := map[string]struct {
+		rules   PublishRules
+		addr    string
+		wantErr string
+	}{
+		"default rules": {
+			rules: DefaultPublishRules(),
+			addr:  "hashicorp/aws",
+		},
+		"non-ASCII namespace": {
+			rules:   DefaultPublishRules(),
+			addr:    "испытание/aws",
+			wantErr: `invalid namespace "испытание": must contain only ASCII characters`,
+		},
+		"non-ASCII namespace allowed": {
+			rules: PublishRules{},
+			addr:  "испытание/aws",
+		},
+		"reserved namespace": {
+			rules:   DefaultPublishRules(),
+			addr:    "example.com/builtin/aws",
+			wantErr: `invalid namespace "builtin": is reserved`,
+		},
+		"reserved type": {
+			rules:   DefaultPublishRules(),
+			addr:    "hashicorp/terraform",
+			wantErr: `invalid type "terraform": is reserved`,
+		},
+		"type too long": {
+			rules:   PublishRules{MaxNameLength: 2},
+			addr:    "hashicorp/aws",
+			wantErr: `invalid type "aws": must be no longer than 2 characters`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.rules.ValidateProvider(MustParseProviderSource(test.addr))
+			checkLimitsErr(t, "provider", err, test.wantErr)
+		})
+	}
+}
+
+func TestPublishRulesValidateModulePackage(t *testing.T) {
+	tests := map[string]struct {
+		rules   PublishRules
+		addr    string
+		wantErr string
+	}{
+		"default rules": {
+			rules: DefaultPublishRules(),
+			addr:  "hashicorp/consul/aws",
+		},
+		"uppercase namespace": {
+			rules:   DefaultPublishRules(),
+			addr:    "HashiCorp/consul/aws",
+			wantErr: `invalid namespace "HashiCorp": must not contain uppercase letters`,
+		},
+		"uppercase namespace allowed": {
+			rules: PublishRules{ASCIIOnly: true},
+			addr:  "HashiCorp/consul/aws",
+		},
+		"reserved name": {
+			rules:   PublishRules{ReservedNames: []string{"Consul"}},
+			addr:    "hashicorp/consul/aws",
+			wantErr: `invalid module name "consul": is reserved`,
+		},
+		"namespace too long": {
+			rules:   PublishRules{MaxNamespaceLength: 5},
+			addr:    "hashicorp/consul/aws",
+			wantErr: `invalid namespace "hashicorp": must be no longer than 5 characters`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.rules.ValidateModulePackage(MustParseModuleSource(test.addr).Package)
+			checkLimitsErr(t, "module", err, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// PURLType is the package-url (purl) type used for Terraform providers and
+// modules by ToPURL methods, such as Provider.ToPURL.
+//
+// The purl forms are defined as follows:
+//
+//	pkg:terraform/<namespace>/<type>@<version>
+//	pkg:terraform/<namespace>/<name>/<target system>@<version>#<subdir>
+//
+// The first is a provider address and the second a module address, which
+// is distinguished by its two-segment purl namespace. The version and the
+// module subdirectory are optional.
+//
+// The "repository_url" qualifier gives the registry hostname, in its ASCII
+// "punycode" form, such as in
+// "pkg:terraform/hashicorp/aws@5.0.0?repository_url=registry.terraform.io".
+// ToPURL methods always include it, but when parsing a purl without it
+// the address is assumed to belong to the default registry host.
+//
+// Provider addresses with the legacy or unknown namespace have no valid
+// purl form.
+const PURLType = "terraform"
+
+// ToPURL returns the package-url (purl) form of the provider address at the
+// given version, as described for PURLType. If version is empty then the
+// result has no version component.
+func (pt Provider) ToPURL(version string) string {
+	return buildPURL([]string{pt.Namespace, pt.Type}, version, pt.Hostname, "")
+}
+
+// ToPURL returns the package-url (purl) form of the module address at the
+// given version, as described for PURLType. If version is empty then the
+// result has no version component.
+func (s Module) ToPURL(version string) string {
+	segments := []string{s.Package.Namespace, s.Package.Name, s.Package.TargetSystem}
+	return buildPURL(segments, version, s.Package.Host, s.Subdir)
+}
+
+// ProviderFromPURL parses the package-url (purl) form of a provider address,
+// as described for PURLType, returning the provider address and the
+// version, which is empty if the purl has no version component.
+func ProviderFromPURL(purl string) (Provider, string, error) {
+	segments, version, host, subdir, err := parsePURL(purl)
+	if err != nil {
+		return Provider{}, "", err
+	}
+	if len(segments) != 2 || subdir != "" {
+		return Provider{}, "", fmt.Errorf("invalid purl %q: a provider purl must have the form pkg:%s/namespace/type", purl, PURLType)
+	}
+	if host == "" {
+		host = DefaultProviderRegistryHost.ForDisplay()
+	}
+	p, err := ParseProviderSource(host + "/" + strings.Join(segments, "/"))
+	if err != nil {
+		return Provider{}, "", err
+	}
+	return p, version, nil
+}
+
+// ModuleFromPURL parses the package-url (purl) form of a module address, as
+// described for PURLType, returning the module address and the version,
+// which is empty if the purl has no version component.
+func ModuleFromPURL(purl string) (Module, string, error) {
+	segments, version, host, subdir, err := parsePURL(purl)
+	if err != nil {
+		return Module{}, "", err
+	}
+	if len(segments) != 3 {
+		return Module{}, "", fmt.Errorf("invalid purl %q: a module purl must have the form pkg:%s/namespace/name/system", purl, PURLType)
+	}
+	if host == "" {
+		host = DefaultModuleRegistryHost.ForDisplay()
+	}
+	source := host + "/" + strings.Join(segments, "/")
+	if subdir != "" {
+		source += "//" + subdir
+	}
+	mod, err := ParseModuleSource(source)
+	if err != nil {
+		return Module{}, "", err
+	}
+	return mod, version, nil
+}
+
+func buildPURL(segments []string, version string, repo svchost.Hostname, subdir string) string {
+	var buf strings.Builder
+	buf.WriteString("pkg:" + PURLType)
+	for _, segment := range segments {
+		buf.WriteByte('/')
+		buf.WriteString(url.PathEscape(segment))
+	}
+	if version != "" {
+		buf.WriteByte('@')
+		buf.WriteString(url.PathEscape(version))
+	}
+	buf.WriteString("?repository_url=")
+	buf.WriteString(url.QueryEscape(repo.String()))
+	if subdir != "" {
+		buf.WriteByte('#')
+		for i, segment := range strings.Split(subdir, "/") {
+			if i > 0 {
+				buf.WriteByte('/')
+			}
+			buf.WriteString(url.PathEscape(segment))
+		}
+	}
+	return buf.String()
+}
+
+// parsePURL splits a purl into its decoded components, checking that it
+// has the expected type. host is in the form expected in a source string,
+// and is empty if the purl has no repository_url qualifier.
+func parsePURL(purl string) (segments []string, version, host, subdir string, err error) {
+	rest := purl
+	if len(rest) < 4 || !strings.EqualFold(rest[:4], "pkg:") {
+		return nil, "", "", "", fmt.Errorf("invalid purl %q: must start with \"pkg:\"", purl)
+	}
+	rest = strings.TrimLeft(rest[4:], "/")
+
+	if i := strings.Index(rest, "#"); i != -1 {
+		subdir, err = url.PathUnescape(strings.Trim(rest[i+1:], "/"))
+		if err != nil {
+			return nil, "", "", "", fmt.Errorf("invalid purl %q: invalid subpath: %s", purl, err)
+		}
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "?"); i != -1 {
+		qualifiers, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return nil, "", "", "", fmt.Errorf("invalid purl %q: invalid qualifiers: %s", purl, err)
+		}
+		if repo := qualifiers.Get("repository_url"); repo != "" {
+			repo = strings.TrimSuffix(strings.TrimPrefix(repo, "https://"), "/")
+			hostname, port := repo, ""
+			if i := strings.LastIndex(repo, ":"); i != -1 {
+				hostname, port = repo[:i], repo[i+1:]
+			}
+			host, err = hostForSource(hostname, port)
+			if err != nil {
+				return nil, "", "", "", fmt.Errorf("invalid purl %q: invalid repository_url: %s", purl, err)
+			}
+		}
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, "@"); i != -1 {
+		version, err = url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return nil, "", "", "", fmt.Errorf("invalid purl %q: invalid version: %s", purl, err)
+		}
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(strings.TrimRight(rest, "/"), "/")
+	if !strings.EqualFold(parts[0], PURLType) {
+		return nil, "", "", "", fmt.Errorf("invalid purl %q: must have type %q", purl, PURLType)
+	}
+	for _, part := range parts[1:] {
+		segment, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, "", "", "", fmt.Errorf("invalid purl %q: %s", purl, err)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, version, host, subdir, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderPURL(t *testing.T) {
+	tests := map[string]struct {
+		Input   Provider
+		Version string
+		Want    string
+	}{
+		"default host": {
+			MustParseProviderSource("hashicorp/aws"),
+			"5.0.0",
+			"pkg:terraform/hashicorp/aws@5.0.0?repository_url=registry.terraform.io",
+		},
+		"no version": {
+			MustParseProviderSource("hashicorp/aws"),
+			"",
+			"pkg:terraform/hashicorp/aws?repository_url=registry.terraform.io",
+		},
+		"custom host": {
+			MustParseProviderSource("example.com:8443/foo/bar"),
+			"1.0.0-beta1",
+			"pkg:terraform/foo/bar@1.0.0-beta1?repository_url=example.com%3A8443",
+		},
+		"internationalized": {
+			MustParseProviderSource("испытание.com/испытание/aws"),
+			"1.0.0",
+			"pkg:terraform/%D0%B8%D1%81%D0%BF%D1%8B%D1%82%D0%B0%D0%BD%D0%B8%D0%B5/aws@1.0.0?repository_url=xn--80akhbyknj4f.com",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := test.Input.ToPURL(test.Version)
+			if got != test.Want {
+				t.Fatalf("wrong purl\ngot:  %s\nwant: %s", got, test.Want)
+			}
+
+			back, version, err := ProviderFromPURL(got)
+			if err != nil {
+				t.Fatalf("unexpected error parsing purl: %s", err)
+			}
+			if diff := cmp.Diff(test.Input, back); diff != "" {
+				t.Errorf("wrong result parsing purl\n%s", diff)
+			}
+			if version != test.Version {
+				t.Errorf("wrong version\ngot:  %s\nwant: %s", version, test.Version)
+			}
+		})
+	}
+}
+
+func TestModulePURL(t *testing.T) {
+	tests := map[string]struct {
+		Input   Module
+		Version string
+		Want    string
+	}{
+		"default host": {
+			MustParseModuleSource("hashicorp/consul/aws"),
+			"0.1.0",
+			"pkg:terraform/hashicorp/consul/aws@0.1.0?repository_url=registry.terraform.io",
+		},
+		"subdir": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			"0.1.0",
+			"pkg:terraform/hashicorp/consul/aws@0.1.0?repository_url=registry.terraform.io#modules/consul-cluster",
+		},
+		"custom host": {
+			MustParseModuleSource("example.com/HashiCorp/Consul/aws"),
+			"",
+			"pkg:terraform/HashiCorp/Consul/aws?repository_url=example.com",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := test.Input.ToPURL(test.Version)
+			if got != test.Want {
+				t.Fatalf("wrong purl\ngot:  %s\nwant: %s", got, test.Want)
+			}
+
+			back, version, err := ModuleFromPURL(got)
+			if err != nil {
+				t.Fatalf("unexpected error parsing purl: %s", err)
+			}
+			if diff := cmp.Diff(test.Input, back); diff != "" {
+				t.Errorf("wrong result parsing purl\n%s", diff)
+			}
+			if version != test.Version {
+				t.Errorf("wrong version\ngot:  %s\nwant: %s", version, test.Version)
+			}
+		})
+	}
+}
+
+func TestProviderFromPURL(t *testing.T) {
+	tests := map[string]struct {
+		Want        Provider
+		WantVersion string
+		WantErr     string
+	}{
+		"pkg:terraform/hashicorp/aws@5.0.0": {
+			Want:        MustParseProviderSource("hashicorp/aws"),
+			WantVersion: "5.0.0",
+		},
+		"PKG:Terraform/HashiCorp/AWS?repository_url=https://example.com/": {
+			Want: MustParseProviderSource("example.com/hashicorp/aws"),
+		},
+		"pkg:npm/hashicorp/aws@5.0.0": {
+			WantErr: `invalid purl "pkg:npm/hashicorp/aws@5.0.0": must have type "terraform"`,
+		},
+		"terraform/hashicorp/aws": {
+			WantErr: `invalid purl "terraform/hashicorp/aws": must start with "pkg:"`,
+		},
+		"pkg:terraform/hashicorp/consul/aws": {
+			WantErr: `invalid purl "pkg:terraform/hashicorp/consul/aws": a provider purl must have the form pkg:terraform/namespace/type`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, version, err := ProviderFromPURL(input)
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if version != test.WantVersion {
+				t.Errorf("wrong version\ngot:  %s\nwant: %s", version, test.WantVersion)
+			}
+		})
+	}
+}
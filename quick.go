@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// The Generate methods in this file implement testing/quick.Generator, so
+// that property-based tests can receive random addresses. Each generated
+// address is valid and normalized, and so is unchanged by a round-trip
+// through its String representation and the corresponding parser.
+
+// quickHosts are the registry hostnames used in generated addresses.
+var quickHosts = []svchost.Hostname{
+	DefaultProviderRegistryHost,
+	"app.terraform.io",
+	"example.com",
+	"tf.example.net:8443",
+	"xn--80akhbyknj4f.com", // испытание.com
+}
+
+const (
+	quickLowerAlnum = "abcdefghijklmnopqrstuvwxyz0123456789"
+	quickMixedAlnum = quickLowerAlnum + "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// Generate implements testing/quick.Generator, returning a random valid
+// provider address whose namespace and type are no longer than size
+// characters.
+func (Provider) Generate(r *rand.Rand, size int) reflect.Value {
+	typeName := quickProviderPart(r, size)
+	for strings.HasPrefix(typeName, "terraform-") {
+		typeName = quickProviderPart(r, size)
+	}
+	return reflect.ValueOf(Provider{
+		Hostname:  quickHosts[r.Intn(len(quickHosts))],
+		Namespace: quickProviderPart(r, size),
+		Type:      typeName,
+	})
+}
+
+// Generate implements testing/quick.Generator, returning a random valid
+// module package address whose namespace, name, and target system are no
+// longer than size characters.
+func (ModulePackage) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(quickModulePackage(r, size))
+}
+
+// Generate implements testing/quick.Generator, returning a random valid
+// module address, which has a subdirectory about half of the time.
+func (Module) Generate(r *rand.Rand, size int) reflect.Value {
+	ret := Module{Package: quickModulePackage(r, size)}
+	if r.Intn(2) == 0 {
+		segments := make([]string, 1+r.Intn(3))
+		for i := range segments {
+			segments[i] = quickModuleName(r, size)
+		}
+		ret.Subdir = strings.Join(segments, "/")
+	}
+	return reflect.ValueOf(ret)
+}
+
+func quickModulePackage(r *rand.Rand, size int) ModulePackage {
+	return ModulePackage{
+		Host:         quickHosts[r.Intn(len(quickHosts))],
+		Namespace:    quickModuleName(r, size),
+		Name:         quickModuleName(r, size),
+		TargetSystem: quickWord(r, quickLowerAlnum, quickLength(r, size)),
+	}
+}
+
+// quickLength returns a random segment length between one and size, also
+// limited to the maximum length of the shortest kind of segment.
+func quickLength(r *rand.Rand, size int) int {
+	if size < 1 {
+		size = 1
+	}
+	if size > 64 {
+		size = 64
+	}
+	return 1 + r.Intn(size)
+}
+
+// quickProviderPart returns a random valid, normalized provider namespace
+// or type, which may contain single dashes between letters and digits.
+func quickProviderPart(r *rand.Rand, size int) string {
+	length := quickLength(r, size)
+	buf := []byte(quickWord(r, quickLowerAlnum, length))
+	for i := 1; i < length-1; i++ {
+		if buf[i-1] != '-' && r.Intn(6) == 0 {
+			buf[i] = '-'
+		}
+	}
+	return string(buf)
+}
+
+// quickModuleName returns a random valid module namespace or name.
+func quickModuleName(r *rand.Rand, size int) string {
+	length := quickLength(r, size)
+	buf := []byte(quickWord(r, quickMixedAlnum, length))
+	for i := 1; i < length-1; i++ {
+		switch r.Intn(8) {
+		case 0:
+			buf[i] = '-'
+		case 1:
+			buf[i] = '_'
+		}
+	}
+	return string(buf)
+}
+
+func quickWord(r *rand.Rand, alphabet string, length int) string {
+	buf := make([]byte, length)
+	for i := range buf {
+		buf[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
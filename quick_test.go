@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestQuickGenerate(t *testing.T) {
+	t.Run("provider", func(t *testing.T) {
+		roundTrip := func(p Provider) bool {
+			got, err := ParseProviderSource(p.String())
+			if err != nil {
+				t.Logf("generated invalid provider %#v: %s", p, err)
+				return false
+			}
+			return got == p
+		}
+		if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("module package", func(t *testing.T) {
+		roundTrip := func(pkg ModulePackage) bool {
+			got, err := ParseModuleSource(pkg.String())
+			if err != nil {
+				t.Logf("generated invalid module package %#v: %s", pkg, err)
+				return false
+			}
+			return got.Package == pkg && got.Subdir == ""
+		}
+		if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("module", func(t *testing.T) {
+		roundTrip := func(mod Module) bool {
+			got, err := ParseModuleSource(mod.String())
+			if err != nil {
+				t.Logf("generated invalid module %#v: %s", mod, err)
+				return false
+			}
+			return got == mod
+		}
+		if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error(err)
+		}
+	})
+}
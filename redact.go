@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// RedactedHost is the placeholder hostname used in place of a private
+// registry hostname by the RedactHost methods.
+//
+// It uses the ".invalid" top-level domain, which is reserved for names
+// that can never exist, so that a redacted address remains syntactically
+// valid but can't refer to a real registry.
+const RedactedHost = svchost.Hostname("redacted.invalid")
+
+// RedactHost returns a copy of the receiver with its hostname replaced by
+// RedactedHost, unless it is the hostname of a well-known public registry
+// or BuiltInProviderHost. The namespace and type are preserved.
+//
+// This is intended for error messages and telemetry that might otherwise
+// reveal the hostnames of private registries.
+func (pt Provider) RedactHost() Provider {
+	if !isPublicRegistryHost(pt.Hostname) {
+		pt.Hostname = RedactedHost
+	}
+	return pt
+}
+
+// RedactHost returns a copy of the receiver with its hostname replaced by
+// RedactedHost, unless it is the hostname of a well-known public registry,
+// as described for Provider.RedactHost.
+func (s ModulePackage) RedactHost() ModulePackage {
+	if !isPublicRegistryHost(s.Host) {
+		s.Host = RedactedHost
+	}
+	return s
+}
+
+// RedactHost returns a copy of the receiver with its package hostname
+// replaced by RedactedHost, unless it is the hostname of a well-known
+// public registry, as described for Provider.RedactHost. The subdirectory
+// is preserved.
+func (s Module) RedactHost() Module {
+	s.Package = s.Package.RedactHost()
+	return s
+}
+
+// isPublicRegistryHost returns true if the given hostname is one of the
+// wellKnownRegistryHosts or BuiltInProviderHost, without a port number.
+func isPublicRegistryHost(host svchost.Hostname) bool {
+	if host == BuiltInProviderHost {
+		return true
+	}
+	for _, known := range wellKnownRegistryHosts {
+		if host == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestRedactHost(t *testing.T) {
+	tests := map[string]struct {
+		addr interface{ String() string }
+		want string
+	}{
+		"public provider": {
+			MustParseProviderSource("hashicorp/aws").RedactHost(),
+			"registry.terraform.io/hashicorp/aws",
+		},
+		"built-in provider": {
+			MustParseProviderSource("terraform.io/builtin/terraform").RedactHost(),
+			"terraform.io/builtin/terraform",
+		},
+		"legacy provider": {
+			Provider{Hostname: DefaultProviderRegistryHost, Namespace: LegacyProviderNamespace, Type: "aws"}.RedactHost(),
+			"registry.terraform.io/-/aws",
+		},
+		"private provider": {
+			MustParseProviderSource("tf.corp.example.com/acme/internal").RedactHost(),
+			"redacted.invalid/acme/internal",
+		},
+		"public host with port": {
+			MustParseProviderSource("registry.terraform.io:8443/hashicorp/aws").RedactHost(),
+			"redacted.invalid/hashicorp/aws",
+		},
+		"OpenTofu registry module package": {
+			MustParseModuleSource("registry.opentofu.org/hashicorp/consul/aws").Package.RedactHost(),
+			"registry.opentofu.org/hashicorp/consul/aws",
+		},
+		"private module package": {
+			MustParseModuleSource("испытание.com/acme/vpc/aws").Package.RedactHost(),
+			"redacted.invalid/acme/vpc/aws",
+		},
+		"app.terraform.io module": {
+			MustParseModuleSource("app.terraform.io/acme/vpc/aws//modules/subnet").RedactHost(),
+			"app.terraform.io/acme/vpc/aws//modules/subnet",
+		},
+		"module with subdir": {
+			MustParseModuleSource("tf.corp.example.com/acme/vpc/aws//modules/subnet").RedactHost(),
+			"redacted.invalid/acme/vpc/aws//modules/subnet",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.addr.String(); got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedactHostParses(t *testing.T) {
+	redacted := MustParseModuleSource("tf.corp.example.com/acme/vpc/aws").RedactHost()
+	got, err := ParseModuleSource(redacted.String())
+	if err != nil {
+		t.Fatalf("redacted address doesn't parse: %s", err)
+	}
+	if got != redacted {
+		t.Errorf("wrong result %s; want %s", got, redacted)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// RegistryNamespace is the address of a namespace in a provider or module
+// registry, such as "registry.terraform.io/hashicorp".
+//
+// A namespace is typically owned by a single organization, and so this is
+// useful for policies that apply to all of the providers and modules that
+// an organization publishes.
+type RegistryNamespace struct {
+	Host      svchost.Hostname
+	Namespace string
+}
+
+// ParseRegistryNamespace parses a registry namespace address, which
+// consists of an optional registry hostname followed by a namespace, as in
+// "registry.terraform.io/hashicorp" or just "hashicorp". If the hostname is
+// omitted then it defaults to DefaultProviderRegistryHost, which is also
+// the default module registry host.
+//
+// The namespace must be valid as either a provider namespace or a module
+// namespace, and is normalized to lowercase because registry namespaces are
+// matched case-insensitively.
+func ParseRegistryNamespace(raw string) (RegistryNamespace, error) {
+	var ret RegistryNamespace
+	parts := strings.Split(raw, "/")
+	switch len(parts) {
+	case 1:
+		ret.Host = DefaultProviderRegistryHost
+	case 2:
+		host, err := parseHostname(parts[0])
+		if err != nil {
+			return ret, fmt.Errorf("invalid registry hostname %q: %s", parts[0], err)
+		}
+		ret.Host = host
+	default:
+		return ret, fmt.Errorf("a registry namespace address must have an optional hostname and a namespace, separated by a slash")
+	}
+
+	given := parts[len(parts)-1]
+	namespace, err := parseRegistryNamespaceName(given)
+	if err != nil {
+		return RegistryNamespace{}, fmt.Errorf("invalid namespace %q: %s", given, err)
+	}
+	ret.Namespace = namespace
+	return ret, nil
+}
+
+// MustParseRegistryNamespace is a wrapper around ParseRegistryNamespace
+// that panics if it returns an error.
+func MustParseRegistryNamespace(raw string) RegistryNamespace {
+	ret, err := ParseRegistryNamespace(raw)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// parseRegistryNamespaceName validates and normalizes the namespace portion
+// of a registry namespace address, accepting anything that is valid as
+// either a module namespace or a provider namespace.
+func parseRegistryNamespaceName(given string) (string, error) {
+	if _, err := parseModuleRegistryName(given); err == nil {
+		return strings.ToLower(given), nil
+	}
+	namespace, err := ParseProviderNamespace(given)
+	if err != nil {
+		return "", fmt.Errorf("must be a valid provider or module namespace")
+	}
+	return string(namespace), nil
+}
+
+// String returns the full string representation of the namespace address,
+// including the hostname.
+func (n RegistryNamespace) String() string {
+	return n.Host.ForDisplay() + "/" + n.Namespace
+}
+
+// ForDisplay is similar to String but omits the hostname if it is the
+// default registry hostname.
+func (n RegistryNamespace) ForDisplay() string {
+	if n.Host == DefaultProviderRegistryHost {
+		return n.Namespace
+	}
+	return n.String()
+}
+
+// ContainsProvider returns true if the given provider belongs to the
+// receiving namespace. Namespaces are compared case-insensitively.
+//
+// Addresses with the legacy or unknown provider namespace don't belong to
+// any namespace.
+func (n RegistryNamespace) ContainsProvider(addr Provider) bool {
+	return addr.Hostname == n.Host && strings.EqualFold(addr.Namespace, n.Namespace)
+}
+
+// ContainsModulePackage returns true if the given module package belongs
+// to the receiving namespace. Namespaces are compared case-insensitively.
+func (n RegistryNamespace) ContainsModulePackage(addr ModulePackage) bool {
+	return addr.Host == n.Host && strings.EqualFold(addr.Namespace, n.Namespace)
+}
+
+// RegistryNamespace returns the address of the registry namespace that the
+// provider belongs to.
+//
+// The result is not meaningful for an address with the legacy or unknown
+// provider namespace, which doesn't belong to any real namespace.
+func (pt Provider) RegistryNamespace() RegistryNamespace {
+	return RegistryNamespace{Host: pt.Hostname, Namespace: pt.Namespace}
+}
+
+// RegistryNamespace returns the address of the registry namespace that the
+// module package belongs to, with the namespace normalized to lowercase.
+func (s ModulePackage) RegistryNamespace() RegistryNamespace {
+	return RegistryNamespace{Host: s.Host, Namespace: strings.ToLower(s.Namespace)}
+}
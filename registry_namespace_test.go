@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestParseRegistryNamespace(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    RegistryNamespace
+		wantErr string
+	}{
+		"namespace only": {
+			input: "hashicorp",
+			want:  RegistryNamespace{Host: DefaultProviderRegistryHost, Namespace: "hashicorp"},
+		},
+		"with hostname": {
+			input: "Example.com/HashiCorp",
+			want:  RegistryNamespace{Host: svchost.Hostname("example.com"), Namespace: "hashicorp"},
+		},
+		"with port": {
+			input: "example.com:8443/hashicorp",
+			want:  RegistryNamespace{Host: svchost.Hostname("example.com:8443"), Namespace: "hashicorp"},
+		},
+		"module namespace with underscore": {
+			input: "my_org",
+			want:  RegistryNamespace{Host: DefaultProviderRegistryHost, Namespace: "my_org"},
+		},
+		"provider namespace with unicode": {
+			input: "испытание.com/ИСПЫТАНИЕ",
+			want:  RegistryNamespace{Host: svchost.Hostname("xn--80akhbyknj4f.com"), Namespace: "испытание"},
+		},
+		"empty": {
+			input:   "",
+			wantErr: `invalid namespace "": must be a valid provider or module namespace`,
+		},
+		"legacy namespace": {
+			input:   "-",
+			wantErr: `invalid namespace "-": must be a valid provider or module namespace`,
+		},
+		"invalid hostname": {
+			input:   "exa mple.com/hashicorp",
+			wantErr: `invalid registry hostname "exa mple.com": idna: disallowed rune U+0020`,
+		},
+		"single-label hostname": {
+			input: "localhost/hashicorp",
+			want:  RegistryNamespace{Host: svchost.Hostname("localhost"), Namespace: "hashicorp"},
+		},
+		"provider address": {
+			input:   "registry.terraform.io/hashicorp/aws",
+			wantErr: `a registry namespace address must have an optional hostname and a namespace, separated by a slash`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseRegistryNamespace(test.input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if err.Error() != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegistryNamespaceString(t *testing.T) {
+	ns := MustParseRegistryNamespace("hashicorp")
+	if got, want := ns.String(), "registry.terraform.io/hashicorp"; got != want {
+		t.Errorf("wrong String result %q; want %q", got, want)
+	}
+	if got, want := ns.ForDisplay(), "hashicorp"; got != want {
+		t.Errorf("wrong ForDisplay result %q; want %q", got, want)
+	}
+
+	ns = MustParseRegistryNamespace("испытание.com/hashicorp")
+	if got, want := ns.ForDisplay(), "испытание.com/hashicorp"; got != want {
+		t.Errorf("wrong ForDisplay result %q; want %q", got, want)
+	}
+}
+
+func TestRegistryNamespaceContains(t *testing.T) {
+	ns := MustParseRegistryNamespace("hashicorp")
+
+	providers := map[string]bool{
+		"hashicorp/aws":                       true,
+		"registry.terraform.io/HashiCorp/aws": true,
+		"example.com/hashicorp/aws":           false,
+		"terraform-providers/aws":             false,
+		"aws":                                 false,
+	}
+	for src, want := range providers {
+		if got := ns.ContainsProvider(MustParseProviderSource(src)); got != want {
+			t.Errorf("wrong result for provider %q: got %t, want %t", src, got, want)
+		}
+	}
+
+	modules := map[string]bool{
+		"hashicorp/consul/aws":             true,
+		"HashiCorp/consul/aws":             true,
+		"example.com/hashicorp/consul/aws": false,
+		"terraform-aws-modules/vpc/aws":    false,
+	}
+	for src, want := range modules {
+		if got := ns.ContainsModulePackage(MustParseModuleSource(src).Package); got != want {
+			t.Errorf("wrong result for module %q: got %t, want %t", src, got, want)
+		}
+	}
+}
+
+func TestAddressRegistryNamespace(t *testing.T) {
+	want := MustParseRegistryNamespace("example.com/hashicorp")
+
+	if got := MustParseProviderSource("example.com/hashicorp/aws").RegistryNamespace(); got != want {
+		t.Errorf("wrong provider namespace %s; want %s", got, want)
+	}
+	if got := MustParseModuleSource("example.com/HashiCorp/consul/aws").Package.RegistryNamespace(); got != want {
+		t.Errorf("wrong module namespace %s; want %s", got, want)
+	}
+}
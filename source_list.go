@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ListedSource is an address parsed by ParseSourceList, along with where it
+// appeared in the list.
+type ListedSource struct {
+	// Line is the one-based line number where the source string appeared.
+	Line int
+
+	// Source is the source string as written.
+	Source string
+
+	// Addr is the parsed address, whose type depends on the kind given to
+	// ParseSourceList.
+	Addr any
+}
+
+// ParseSourceList parses a list of source strings in the format commonly
+// used for allowlist files, where source strings are separated by
+// whitespace or newlines, blank lines are ignored, and a "#" starts a
+// comment that continues to the end of the line.
+//
+// Each source string is parsed as the given kind of address, giving a
+// Provider for ProviderKind, a ModulePackage for ModulePackageKind, a Module
+// for ModuleRegistryKind, or a ModuleSourceRemote for ModuleRemoteKind.
+// UnknownAddressKind parses each source string as ParseAny does, so that a
+// list can mix different kinds of address.
+//
+// ParseSourceList returns an error including the line number for the first
+// source string that is invalid, or if reading fails.
+func ParseSourceList(r io.Reader, kind AddressKind) ([]ListedSource, error) {
+	return Parser{}.ParseSourceList(r, kind)
+}
+
+// ParseSourceList is like the package-level function of the same name, but
+// parses provider and module registry addresses using the receiver's
+// settings.
+func (p Parser) ParseSourceList(r io.Reader, kind AddressKind) ([]ListedSource, error) {
+	parse, err := p.sourceListParser(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []ListedSource
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if idx := strings.IndexByte(text, '#'); idx != -1 {
+			text = text[:idx]
+		}
+		for _, source := range strings.Fields(text) {
+			addr, err := parse(source)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid source %q: %s", line, source, err)
+			}
+			ret = append(ret, ListedSource{Line: line, Source: source, Addr: addr})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source list: %s", err)
+	}
+	return ret, nil
+}
+
+// sourceListParser returns the function that ParseSourceList uses to parse
+// each source string for the given kind.
+func (p Parser) sourceListParser(kind AddressKind) (func(string) (any, error), error) {
+	switch kind {
+	case ProviderKind:
+		return func(s string) (any, error) { return p.ParseProviderSource(s) }, nil
+	case ModulePackageKind:
+		return func(s string) (any, error) {
+			addr, err := p.ParseModuleSource(s)
+			if err != nil {
+				return nil, err
+			}
+			if addr.Subdir != "" {
+				return nil, fmt.Errorf("a module package address may not include a subdirectory")
+			}
+			return addr.Package, nil
+		}, nil
+	case ModuleRegistryKind:
+		return func(s string) (any, error) { return p.ParseModuleSource(s) }, nil
+	case ModuleRemoteKind:
+		return func(s string) (any, error) { return ParseModuleSourceRemote(s) }, nil
+	case UnknownAddressKind:
+		return p.ParseAny, nil
+	default:
+		return nil, fmt.Errorf("can't parse a source list of kind %s", kind)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSourceList(t *testing.T) {
+	const list = `
+# Providers approved for general use.
+hashicorp/aws hashicorp/google   # the big two
+hashicorp/null
+
+  example.com/corp/widget
+`
+
+	tests := map[string]struct {
+		input   string
+		kind    AddressKind
+		want    []ListedSource
+		wantErr string
+	}{
+		"providers": {
+			input: list,
+			kind:  ProviderKind,
+			want: []ListedSource{
+				{Line: 3, Source: "hashicorp/aws", Addr: MustParseProviderSource("hashicorp/aws")},
+				{Line: 3, Source: "hashicorp/google", Addr: MustParseProviderSource("hashicorp/google")},
+				{Line: 4, Source: "hashicorp/null", Addr: MustParseProviderSource("hashicorp/null")},
+				{Line: 6, Source: "example.com/corp/widget", Addr: MustParseProviderSource("example.com/corp/widget")},
+			},
+		},
+		"module packages": {
+			input: "hashicorp/consul/aws\n",
+			kind:  ModulePackageKind,
+			want: []ListedSource{
+				{Line: 1, Source: "hashicorp/consul/aws", Addr: MustParseModuleSource("hashicorp/consul/aws").Package},
+			},
+		},
+		"module package with subdirectory": {
+			input:   "hashicorp/consul/aws\nhashicorp/consul/aws//modules/foo\n",
+			kind:    ModulePackageKind,
+			wantErr: `line 2: invalid source "hashicorp/consul/aws//modules/foo": a module package address may not include a subdirectory`,
+		},
+		"mixed": {
+			input: "hashicorp/consul/aws//modules/foo hashicorp/aws",
+			kind:  UnknownAddressKind,
+			want: []ListedSource{
+				{Line: 1, Source: "hashicorp/consul/aws//modules/foo", Addr: MustParseModuleSource("hashicorp/consul/aws//modules/foo")},
+				{Line: 1, Source: "hashicorp/aws", Addr: MustParseProviderSource("hashicorp/aws")},
+			},
+		},
+		"invalid": {
+			input:   "hashicorp/aws\n\nhashicorp/aws_\n",
+			kind:    ProviderKind,
+			wantErr: `line 3: invalid source "hashicorp/aws_": Invalid provider type: Invalid provider type "aws_" in source "hashicorp/aws_": must contain only letters, digits, and dashes, and may not use leading or trailing dashes"`,
+		},
+		"empty": {
+			input: "# nothing here\n",
+			kind:  ProviderKind,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSourceList(strings.NewReader(test.input), test.kind)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// FormatSourceTemplate executes the given text/template template with the
+// given address as its data, and returns the result only if it is a valid
+// source string for an equivalent address.
+//
+// The address must be a Provider, a ModulePackage, or a Module. The
+// template can refer to the fields and methods of the address, as in
+// "{{.Namespace}}/{{.Type}}" for a Provider.
+//
+// This is intended for code generators that produce source strings from
+// templates, so that a mistake in a template is reported as an error rather
+// than resulting in an invalid or unintended source string.
+func FormatSourceTemplate(tmpl string, addr any) (string, error) {
+	switch addr.(type) {
+	case Provider, ModulePackage, Module:
+	default:
+		return "", fmt.Errorf("cannot format source for %T; must be a Provider, ModulePackage, or Module", addr)
+	}
+
+	t, err := template.New("source").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid source template: %s", err)
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, addr); err != nil {
+		return "", fmt.Errorf("failed to render source template: %s", err)
+	}
+	src := buf.String()
+
+	switch addr := addr.(type) {
+	case Provider:
+		got, err := ParseProviderSource(src)
+		if err != nil {
+			return "", fmt.Errorf("source template produced an invalid provider source %q: %s", src, err)
+		}
+		if got != addr {
+			return "", fmt.Errorf("source template produced %q, which refers to %s instead of %s", src, got, addr)
+		}
+	case ModulePackage:
+		got, err := ParseModuleSource(src)
+		if err != nil {
+			return "", fmt.Errorf("source template produced an invalid module source %q: %s", src, err)
+		}
+		if got != (Module{Package: addr}) {
+			return "", fmt.Errorf("source template produced %q, which refers to %s instead of %s", src, got, addr)
+		}
+	case Module:
+		got, err := ParseModuleSource(src)
+		if err != nil {
+			return "", fmt.Errorf("source template produced an invalid module source %q: %s", src, err)
+		}
+		if got != addr {
+			return "", fmt.Errorf("source template produced %q, which refers to %s instead of %s", src, got, addr)
+		}
+	}
+	return src, nil
+}
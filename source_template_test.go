@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestFormatSourceTemplate(t *testing.T) {
+	tests := map[string]struct {
+		tmpl    string
+		addr    any
+		want    string
+		wantErr string
+	}{
+		"provider": {
+			tmpl: "{{.Namespace}}/{{.Type}}",
+			addr: MustParseProviderSource("hashicorp/aws"),
+			want: "hashicorp/aws",
+		},
+		"provider with method": {
+			tmpl: "{{.ForDisplay}}",
+			addr: MustParseProviderSource("испытание.com/hashicorp/aws"),
+			want: "испытание.com/hashicorp/aws",
+		},
+		"provider on other host without hostname": {
+			tmpl:    "{{.Namespace}}/{{.Type}}",
+			addr:    MustParseProviderSource("example.com/hashicorp/aws"),
+			wantErr: `source template produced "hashicorp/aws", which refers to registry.terraform.io/hashicorp/aws instead of example.com/hashicorp/aws`,
+		},
+		"module package": {
+			tmpl: "{{.Namespace}}/{{.Name}}/{{.TargetSystem}}",
+			addr: MustParseModuleSource("hashicorp/consul/aws").Package,
+			want: "hashicorp/consul/aws",
+		},
+		"module": {
+			tmpl: "{{.Package.ForDisplay}}//{{.Subdir}}",
+			addr: MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			want: "hashicorp/consul/aws//modules/consul-cluster",
+		},
+		"module missing subdir": {
+			tmpl:    "{{.Package.ForDisplay}}",
+			addr:    MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			wantErr: `source template produced "hashicorp/consul/aws", which refers to registry.terraform.io/hashicorp/consul/aws instead of registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster`,
+		},
+		"invalid result": {
+			tmpl:    "{{.Namespace}}_{{.Type}}/",
+			addr:    MustParseProviderSource("hashicorp/aws"),
+			wantErr: `source template produced an invalid provider source "hashicorp_aws/": Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name"`,
+		},
+		"invalid template": {
+			tmpl:    "{{.Namespace",
+			addr:    MustParseProviderSource("hashicorp/aws"),
+			wantErr: `invalid source template: template: source:1: unclosed action`,
+		},
+		"unknown field": {
+			tmpl:    "{{.Nonexistent}}",
+			addr:    MustParseProviderSource("hashicorp/aws"),
+			wantErr: `failed to render source template: template: source:1:2: executing "source" at <.Nonexistent>: can't evaluate field Nonexistent in type tfaddr.Provider`,
+		},
+		"unsupported type": {
+			tmpl:    "{{.}}",
+			addr:    "hashicorp/aws",
+			wantErr: `cannot format source for string; must be a Provider, ModulePackage, or Module`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FormatSourceTemplate(test.tmpl, test.addr)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot:        %s\nwant error: %s", got, test.wantErr)
+				}
+				if err.Error() != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result %q; want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+)
+
+// Sourced is an address along with a description of where it came from,
+// for tools that need to report the location of an address after passing
+// it through several stages of processing.
+//
+// T is typically one of the address types in this package, all of which
+// marshal to JSON as strings.
+type Sourced[T any] struct {
+	Addr       T          `json:"address"`
+	Provenance Provenance `json:"provenance"`
+}
+
+// Provenance describes where an address was found in a Terraform
+// configuration.
+type Provenance struct {
+	// Range is the location of the address in a configuration file. Its
+	// Filename is empty if the location is unknown.
+	Range SourceRange `json:"range"`
+
+	// ModuleCalls is the sequence of module call names leading to the
+	// module containing the address, starting from the root module, such
+	// as ["network", "vpc"] for module.network.module.vpc. It's empty for
+	// the root module.
+	ModuleCalls []string `json:"module_calls,omitempty"`
+}
+
+// ModulePath returns the module calls of the receiver in the syntax that
+// Terraform uses for module addresses, such as "module.network.module.vpc",
+// or an empty string for the root module.
+func (p Provenance) ModulePath() string {
+	var buf strings.Builder
+	for i, name := range p.ModuleCalls {
+		if i > 0 {
+			buf.WriteByte('.')
+		}
+		buf.WriteString("module.")
+		buf.WriteString(name)
+	}
+	return buf.String()
+}
+
+// SourceRange is a range of characters in a configuration file. Its JSON
+// representation matches that of the Range type in the HCL library, so
+// that ranges can be exchanged with tools that use HCL.
+type SourceRange struct {
+	Filename string    `json:"filename"`
+	Start    SourcePos `json:"start"`
+	End      SourcePos `json:"end"`
+}
+
+// SourcePos is a position in a configuration file. Line and Column count
+// from one, and Column counts characters rather than bytes. Byte is the
+// zero-based byte offset from the start of the file.
+type SourcePos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
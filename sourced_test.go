@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSourcedJSON(t *testing.T) {
+	sourced := Sourced[Provider]{
+		Addr: MustParseProviderSource("hashicorp/aws"),
+		Provenance: Provenance{
+			Range: SourceRange{
+				Filename: "network/versions.tf",
+				Start:    SourcePos{Line: 4, Column: 16, Byte: 57},
+				End:      SourcePos{Line: 4, Column: 31, Byte: 72},
+			},
+			ModuleCalls: []string{"network"},
+		},
+	}
+
+	got, err := json.Marshal(sourced)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"address":"registry.terraform.io/hashicorp/aws","provenance":{"range":{"filename":"network/versions.tf","start":{"line":4,"column":16,"byte":57},"end":{"line":4,"column":31,"byte":72}},"module_calls":["network"]}}`
+	if string(got) != want {
+		t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	var back Sourced[Provider]
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	if diff := cmp.Diff(sourced, back); diff != "" {
+		t.Errorf("wrong result decoding\n%s", diff)
+	}
+}
+
+func TestProvenanceModulePath(t *testing.T) {
+	tests := map[string][]string{
+		"":                          nil,
+		"module.network":            {"network"},
+		"module.network.module.vpc": {"network", "vpc"},
+	}
+	for want, calls := range tests {
+		if got := (Provenance{ModuleCalls: calls}).ModulePath(); got != want {
+			t.Errorf("wrong result %q; want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+)
+
+// Span is a range of bytes in a source string, from Start inclusive to End
+// exclusive.
+//
+// A segment that was omitted from a source string, such as an implied
+// hostname, has a zero-length span at the position where that segment
+// would be written.
+type Span struct {
+	Start, End int
+}
+
+// Len returns the length of the span in bytes.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// In returns the part of the given source string covered by the span.
+func (s Span) In(source string) string {
+	return source[s.Start:s.End]
+}
+
+// ProviderSpans describes where each segment of a provider address appears
+// in the source string it was parsed from.
+type ProviderSpans struct {
+	Hostname  Span
+	Namespace Span
+	Type      Span
+}
+
+// ModuleSpans describes where each segment of a module address appears in
+// the source string it was parsed from.
+type ModuleSpans struct {
+	Hostname     Span
+	Namespace    Span
+	Name         Span
+	TargetSystem Span
+
+	// Subdir covers the subdirectory as written, before normalization,
+	// excluding the "//" separator.
+	Subdir Span
+}
+
+// ParseProviderSourceSpans is like ParseProviderSource but also returns the
+// location of each segment in the given string, for tools such as
+// language servers that need to relate segments to positions in a file.
+//
+// The spans are derived only from the slash-separated structure of the
+// string, so they are returned even if the address is otherwise invalid.
+// Segments beyond those that a provider address can have are ignored.
+func ParseProviderSourceSpans(str string) (Provider, ProviderSpans, error) {
+	return Parser{}.ParseProviderSourceSpans(str)
+}
+
+// ParseProviderSourceSpans is like the package-level function of the same
+// name, but applies the rules configured in the receiver.
+func (p Parser) ParseProviderSourceSpans(str string) (Provider, ProviderSpans, error) {
+	addr, err := p.ParseProviderSource(str)
+	return addr, providerSourceSpans(str), err
+}
+
+// providerSourceSpans returns the spans for ParseProviderSourceSpans, which
+// depend only on the slash-separated structure of the given string.
+func providerSourceSpans(str string) ProviderSpans {
+	var spans ProviderSpans
+	segments := splitSpans(str, 0, len(str))
+	if len(segments) > 3 {
+		segments = segments[len(segments)-3:]
+	}
+	switch len(segments) {
+	case 1:
+		spans.Hostname = Span{0, 0}
+		spans.Namespace = Span{0, 0}
+		spans.Type = segments[0]
+	case 2:
+		spans.Hostname = Span{0, 0}
+		spans.Namespace = segments[0]
+		spans.Type = segments[1]
+	case 3:
+		spans.Hostname = segments[0]
+		spans.Namespace = segments[1]
+		spans.Type = segments[2]
+	}
+	return spans
+}
+
+// ParseModuleSourceSpans is like ParseModuleSource but also returns the
+// location of each segment in the given string, for tools such as
+// language servers that need to relate segments to positions in a file.
+//
+// The spans are derived only from the slash-separated structure of the
+// string, so they are returned even if the address is otherwise invalid.
+// Segments beyond those that a module address can have are ignored.
+func ParseModuleSourceSpans(raw string) (Module, ModuleSpans, error) {
+	return Parser{}.ParseModuleSourceSpans(raw)
+}
+
+// ParseModuleSourceSpans is like the package-level function of the same
+// name, but applies the rules configured in the receiver.
+func (p Parser) ParseModuleSourceSpans(raw string) (Module, ModuleSpans, error) {
+	addr, err := p.ParseModuleSource(raw)
+	return addr, moduleSourceSpans(raw), err
+}
+
+// moduleSourceSpans returns the spans for ParseModuleSourceSpans, which
+// depend only on the slash-separated structure of the given string.
+func moduleSourceSpans(raw string) ModuleSpans {
+	var spans ModuleSpans
+	pkgEnd := len(raw)
+	spans.Subdir = Span{len(raw), len(raw)}
+	if idx := strings.Index(raw, "//"); idx != -1 {
+		pkgEnd = idx
+		spans.Subdir = Span{idx + 2, len(raw)}
+	}
+
+	segments := splitSpans(raw, 0, pkgEnd)
+	if len(segments) > 4 {
+		segments = segments[len(segments)-4:]
+	}
+	if len(segments) == 4 {
+		spans.Hostname = segments[0]
+		segments = segments[1:]
+	}
+	fields := []*Span{&spans.Namespace, &spans.Name, &spans.TargetSystem}
+	for i, field := range fields {
+		if i < len(segments) {
+			*field = segments[i]
+		} else {
+			*field = Span{pkgEnd, pkgEnd}
+		}
+	}
+	return spans
+}
+
+// splitSpans returns the spans of the slash-separated segments of the given
+// range of str.
+func splitSpans(str string, start, end int) []Span {
+	var ret []Span
+	for {
+		idx := strings.IndexByte(str[start:end], '/')
+		if idx == -1 {
+			return append(ret, Span{start, end})
+		}
+		ret = append(ret, Span{start, start + idx})
+		start += idx + 1
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProviderSourceSpans(t *testing.T) {
+	tests := map[string]struct {
+		want    ProviderSpans
+		wantErr bool
+	}{
+		"aws": {
+			want: ProviderSpans{Hostname: Span{0, 0}, Namespace: Span{0, 0}, Type: Span{0, 3}},
+		},
+		"hashicorp/aws": {
+			want: ProviderSpans{Hostname: Span{0, 0}, Namespace: Span{0, 9}, Type: Span{10, 13}},
+		},
+		"example.com/HashiCorp/AWS": {
+			want: ProviderSpans{Hostname: Span{0, 11}, Namespace: Span{12, 21}, Type: Span{22, 25}},
+		},
+		"испытание.com/foo/bar": {
+			want: ProviderSpans{Hostname: Span{0, 22}, Namespace: Span{23, 26}, Type: Span{27, 30}},
+		},
+		"hashicorp/": {
+			want:    ProviderSpans{Hostname: Span{0, 0}, Namespace: Span{0, 9}, Type: Span{10, 10}},
+			wantErr: true,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, got, err := ParseProviderSourceSpans(input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("wrong error result: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong spans\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseModuleSourceSpans(t *testing.T) {
+	tests := map[string]struct {
+		want    ModuleSpans
+		wantErr bool
+	}{
+		"hashicorp/consul/aws": {
+			want: ModuleSpans{
+				Hostname:     Span{0, 0},
+				Namespace:    Span{0, 9},
+				Name:         Span{10, 16},
+				TargetSystem: Span{17, 20},
+				Subdir:       Span{20, 20},
+			},
+		},
+		"example.com/hashicorp/consul/aws//modules/./foo": {
+			want: ModuleSpans{
+				Hostname:     Span{0, 11},
+				Namespace:    Span{12, 21},
+				Name:         Span{22, 28},
+				TargetSystem: Span{29, 32},
+				Subdir:       Span{34, 47},
+			},
+		},
+		"hashicorp/consul": {
+			want: ModuleSpans{
+				Hostname:     Span{0, 0},
+				Namespace:    Span{0, 9},
+				Name:         Span{10, 16},
+				TargetSystem: Span{16, 16},
+				Subdir:       Span{16, 16},
+			},
+			wantErr: true,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, got, err := ParseModuleSourceSpans(input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("wrong error result: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong spans\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSpanIn(t *testing.T) {
+	input := "example.com/hashicorp/consul/aws//modules/foo"
+	_, spans, err := ParseModuleSourceSpans(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := []string{
+		spans.Hostname.In(input),
+		spans.Namespace.In(input),
+		spans.Name.In(input),
+		spans.TargetSystem.In(input),
+		spans.Subdir.In(input),
+	}
+	want := []string{"example.com", "hashicorp", "consul", "aws", "modules/foo"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong segments\n%s", diff)
+	}
+}
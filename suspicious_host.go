@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// wellKnownRegistryHosts are the hostnames of well-known public registries,
+// which SuspiciousHost compares against and RedactHost leaves unredacted.
+var wellKnownRegistryHosts = []svchost.Hostname{
+	"registry.terraform.io",
+	"app.terraform.io",
+	"registry.opentofu.org",
+}
+
+// maxSuspiciousHostDistance is the maximum edit distance between a
+// hostname and a well-known registry hostname for SuspiciousHost to
+// consider it suspicious. A distance of two catches transposed letters,
+// as in "registry.terrafrom.io".
+const maxSuspiciousHostDistance = 2
+
+// SuspiciousHost returns a description of why the given hostname seems
+// suspicious, and true, if it is very similar to but not the same as the
+// hostname of a well-known public registry, such as "registry.terrafrom.io"
+// or a hostname using lookalike characters from another script.
+//
+// Such a hostname might be a typo by the user or a typosquatting attempt.
+// Any port number in the hostname is ignored.
+func SuspiciousHost(hostname svchost.Hostname) (string, bool) {
+	given := hostname.ForDisplay()
+	if i := strings.LastIndex(given, ":"); i != -1 {
+		given = given[:i]
+	}
+	for _, known := range wellKnownRegistryHosts {
+		if given == known.ForDisplay() {
+			return "", false
+		}
+	}
+
+	bestDistance := maxSuspiciousHostDistance + 1
+	var best svchost.Hostname
+	for _, known := range wellKnownRegistryHosts {
+		if d := editDistance(given, known.ForDisplay()); d < bestDistance {
+			bestDistance, best = d, known
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return fmt.Sprintf("hostname %q is very similar to the well-known registry hostname %q", given, best.ForDisplay()), true
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestSuspiciousHost(t *testing.T) {
+	tests := map[string]struct {
+		input      string
+		wantReason string
+	}{
+		"exact": {
+			input: "registry.terraform.io",
+		},
+		"exact with port": {
+			input: "app.terraform.io:443",
+		},
+		"unrelated": {
+			input: "example.com",
+		},
+		"similar but legitimate length": {
+			input: "registry.example.io",
+		},
+		"transposed letters": {
+			input:      "registry.terrafrom.io",
+			wantReason: `hostname "registry.terrafrom.io" is very similar to the well-known registry hostname "registry.terraform.io"`,
+		},
+		"missing letter": {
+			input:      "registry.opentof.org",
+			wantReason: `hostname "registry.opentof.org" is very similar to the well-known registry hostname "registry.opentofu.org"`,
+		},
+		"wrong top-level domain with port": {
+			input:      "app.terraform.com:8443",
+			wantReason: `hostname "app.terraform.com" is very similar to the well-known registry hostname "app.terraform.io"`,
+		},
+		"lookalike character": {
+			input:      "registry.terrafоrm.io", // Cyrillic "о"
+			wantReason: `hostname "registry.terrafоrm.io" is very similar to the well-known registry hostname "registry.terraform.io"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			host, err := svchost.ForComparison(test.input)
+			if err != nil {
+				t.Fatalf("invalid test hostname: %s", err)
+			}
+			reason, suspicious := SuspiciousHost(host)
+			if suspicious != (test.wantReason != "") {
+				t.Fatalf("wrong result %t for %s", suspicious, host)
+			}
+			if reason != test.wantReason {
+				t.Errorf("wrong reason\ngot:  %s\nwant: %s", reason, test.wantReason)
+			}
+		})
+	}
+}
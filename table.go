@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableFormat selects the output format of WriteProviderTable and
+// WriteModulePackageTable.
+type TableFormat int
+
+const (
+	// TableText renders a plain text table whose columns are aligned
+	// using spaces, suitable for terminal output.
+	TableText TableFormat = iota
+
+	// TableMarkdown renders a table using the GitHub-flavored Markdown
+	// table syntax.
+	TableMarkdown
+)
+
+// tableHeader is the header row used for all address tables.
+var tableHeader = []string{"Address", "Display", "Host", "Namespace"}
+
+// WriteProviderTable writes a table describing each of the given providers
+// to w, in the given format. The columns of the table are the String and
+// ForDisplay forms of each address, followed by its hostname, in display
+// form, and its namespace.
+//
+// The rows are in the same order as the given providers.
+func WriteProviderTable(w io.Writer, providers []Provider, format TableFormat) error {
+	rows := make([][]string, len(providers))
+	for i, p := range providers {
+		rows[i] = []string{p.String(), p.ForDisplay(), p.Hostname.ForDisplay(), p.Namespace}
+	}
+	return writeTable(w, tableHeader, rows, format)
+}
+
+// WriteModulePackageTable writes a table describing each of the given
+// module packages to w, in the given format, with the same columns as
+// described for WriteProviderTable.
+func WriteModulePackageTable(w io.Writer, pkgs []ModulePackage, format TableFormat) error {
+	rows := make([][]string, len(pkgs))
+	for i, pkg := range pkgs {
+		rows[i] = []string{pkg.String(), pkg.ForDisplay(), pkg.Host.ForDisplay(), pkg.Namespace}
+	}
+	return writeTable(w, tableHeader, rows, format)
+}
+
+// writeTable writes the given header and rows to w, padding each column to
+// the width of its widest cell in characters.
+func writeTable(w io.Writer, header []string, rows [][]string, format TableFormat) error {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	if format == TableMarkdown {
+		for i := range widths {
+			// A Markdown delimiter row needs at least three dashes.
+			if widths[i] < 3 {
+				widths[i] = 3
+			}
+		}
+	}
+
+	var buf strings.Builder
+	writeRow := func(row []string) {
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case format == TableMarkdown:
+				buf.WriteString("| " + cell + padding + " ")
+			case i < len(row)-1:
+				buf.WriteString(cell + padding + "  ")
+			default:
+				// No trailing whitespace after the last column.
+				buf.WriteString(cell)
+			}
+		}
+		if format == TableMarkdown {
+			buf.WriteString("|")
+		}
+		buf.WriteString("\n")
+	}
+
+	writeRow(header)
+	if format == TableMarkdown {
+		delims := make([]string, len(header))
+		for i := range delims {
+			delims[i] = strings.Repeat("-", widths[i])
+		}
+		writeRow(delims)
+	}
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteProviderTable(t *testing.T) {
+	providers := []Provider{
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("испытание.com/test/null"),
+	}
+
+	tests := map[TableFormat]string{
+		TableText: `
+Address                              Display                  Host                   Namespace
+registry.terraform.io/hashicorp/aws  hashicorp/aws            registry.terraform.io  hashicorp
+испытание.com/test/null              испытание.com/test/null  испытание.com          test
+`,
+		TableMarkdown: `
+| Address                             | Display                 | Host                  | Namespace |
+| ----------------------------------- | ----------------------- | --------------------- | --------- |
+| registry.terraform.io/hashicorp/aws | hashicorp/aws           | registry.terraform.io | hashicorp |
+| испытание.com/test/null             | испытание.com/test/null | испытание.com         | test      |
+`,
+	}
+
+	for format, want := range tests {
+		var buf strings.Builder
+		if err := WriteProviderTable(&buf, providers, format); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(strings.TrimPrefix(want, "\n"), buf.String()); diff != "" {
+			t.Errorf("wrong output for format %d\n%s", format, diff)
+		}
+	}
+}
+
+func TestWriteModulePackageTable(t *testing.T) {
+	pkgs := []ModulePackage{
+		MustParseModuleSource("hashicorp/consul/aws").Package,
+		MustParseModuleSource("example.com/a/b/c").Package,
+	}
+
+	want := `
+| Address                                    | Display              | Host                  | Namespace |
+| ------------------------------------------ | -------------------- | --------------------- | --------- |
+| registry.terraform.io/hashicorp/consul/aws | hashicorp/consul/aws | registry.terraform.io | hashicorp |
+| example.com/a/b/c                          | example.com/a/b/c    | example.com           | a         |
+`
+	var buf strings.Builder
+	if err := WriteModulePackageTable(&buf, pkgs, TableMarkdown); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(strings.TrimPrefix(want, "\n"), buf.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func TestWriteProviderTableEmpty(t *testing.T) {
+	var buf strings.Builder
+	if err := WriteProviderTable(&buf, nil, TableMarkdown); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "| Address | Display | Host | Namespace |\n| ------- | ------- | ---- | --------- |\n"
+	if got := buf.String(); got != want {
+		t.Errorf("wrong output\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WarningUnknownTargetSystem indicates that a module package has a target
+// system that is not in the table returned by KnownTargetSystems.
+const WarningUnknownTargetSystem WarningCode = "unknown-target-system"
+
+// knownTargetSystems is the table returned by KnownTargetSystems, in
+// lexical order.
+var knownTargetSystems = []string{
+	"alicloud",
+	"aws",
+	"azuread",
+	"azurerm",
+	"cloudflare",
+	"consul",
+	"datadog",
+	"digitalocean",
+	"github",
+	"gitlab",
+	"google",
+	"helm",
+	"ibm",
+	"kubernetes",
+	"nomad",
+	"oci",
+	"openstack",
+	"tfe",
+	"vault",
+	"vsphere",
+}
+
+// KnownTargetSystems returns a table of target systems commonly used by
+// modules on the public Terraform registry, such as "aws" and "azurerm",
+// in lexical order.
+//
+// The table is not exhaustive, and modules may use any target system that
+// is valid syntactically. It is intended for heuristics such as those of
+// ValidateTargetSystemKnown.
+//
+// The result is a new slice on each call, so the caller may modify it.
+func KnownTargetSystems() []string {
+	ret := make([]string, len(knownTargetSystems))
+	copy(ret, knownTargetSystems)
+	return ret
+}
+
+// IsKnownTargetSystem returns true if the given target system appears in
+// the table returned by KnownTargetSystems, ignoring case.
+func IsKnownTargetSystem(system string) bool {
+	system = strings.ToLower(system)
+	i := sort.SearchStrings(knownTargetSystems, system)
+	return i < len(knownTargetSystems) && knownTargetSystems[i] == system
+}
+
+// ValidateTargetSystemKnown returns a warning with code
+// WarningUnknownTargetSystem if the target system of the given module
+// package is not in the table returned by KnownTargetSystems, or nil
+// otherwise.
+//
+// An unknown target system is not an error, because the table is not
+// exhaustive, but it often indicates that the segments of a module source
+// string were written in the wrong order, as in
+// "aws/vpc/cloudposse". If the namespace or name of the package
+// is a known target system then the warning message says so.
+func ValidateTargetSystemKnown(pkg ModulePackage) []Warning {
+	if IsKnownTargetSystem(pkg.TargetSystem) {
+		return nil
+	}
+	msg := fmt.Sprintf("module package %q has target system %q, which is not a commonly-used target system", pkg.ForDisplay(), pkg.TargetSystem)
+	switch {
+	case IsKnownTargetSystem(pkg.Namespace):
+		msg += fmt.Sprintf("; its namespace %q is a target system, so the segments may be in the wrong order", pkg.Namespace)
+	case IsKnownTargetSystem(pkg.Name):
+		msg += fmt.Sprintf("; its module name %q is a target system, so the segments may be in the wrong order", pkg.Name)
+	}
+	return []Warning{{
+		Code:    WarningUnknownTargetSystem,
+		Message: msg,
+	}}
+}
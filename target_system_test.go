@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKnownTargetSystems(t *testing.T) {
+	systems := KnownTargetSystems()
+	if !sort.StringsAreSorted(systems) {
+		t.Error("table is not sorted, so IsKnownTargetSystem will not work")
+	}
+	for _, system := range systems {
+		if _, err := parseModuleRegistryTargetSystem(system); err != nil {
+			t.Errorf("invalid target system %q in table: %s", system, err)
+		}
+	}
+
+	systems[0] = "modified"
+	if KnownTargetSystems()[0] == "modified" {
+		t.Error("modifying result changed the table")
+	}
+}
+
+func TestValidateTargetSystemKnown(t *testing.T) {
+	tests := map[string][]Warning{
+		"terraform-aws-modules/vpc/aws": nil,
+		"hashicorp/consul/AWS":          nil,
+		"hashicorp/network/example": {{
+			Code:    WarningUnknownTargetSystem,
+			Message: `module package "hashicorp/network/example" has target system "example", which is not a commonly-used target system`,
+		}},
+		"aws/vpc/terraform": {{
+			Code:    WarningUnknownTargetSystem,
+			Message: `module package "aws/vpc/terraform" has target system "terraform", which is not a commonly-used target system; its namespace "aws" is a target system, so the segments may be in the wrong order`,
+		}},
+		"aws/vpc/cloudposse": {{
+			Code:    WarningUnknownTargetSystem,
+			Message: `module package "aws/vpc/cloudposse" has target system "cloudposse", which is not a commonly-used target system; its namespace "aws" is a target system, so the segments may be in the wrong order`,
+		}},
+		"example/google/network": {{
+			Code:    WarningUnknownTargetSystem,
+			Message: `module package "example/google/network" has target system "network", which is not a commonly-used target system; its module name "google" is a target system, so the segments may be in the wrong order`,
+		}},
+	}
+
+	for src, want := range tests {
+		t.Run(src, func(t *testing.T) {
+			pkg := ModulePackage{Host: DefaultModuleRegistryHost}
+			// Constructed directly because the target system "AWS" is not
+			// valid syntax for a source string.
+			parts := strings.Split(src, "/")
+			pkg.Namespace, pkg.Name, pkg.TargetSystem = parts[0], parts[1], parts[2]
+			got := ValidateTargetSystemKnown(pkg)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
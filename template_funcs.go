@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// TemplateFuncs returns functions for formatting addresses in templates
+// using text/template or html/template, for use with the Funcs method of
+// a template. The result is a new map on each call, so the caller may
+// modify it.
+//
+// Each function takes a single Provider, ModulePackage, or Module, and
+// fails template execution for a value of any other type:
+//
+//   - tfaddrCanonical returns the result of the String method.
+//   - tfaddrDisplay returns the result of the ForDisplay method.
+//   - tfaddrHost returns the display form of the registry hostname.
+//   - tfaddrNamespace returns the namespace.
+//   - tfaddrURLPath returns the result of the URLPathEscaped method.
+//
+// For example:
+//
+//	{{ tfaddrDisplay .Provider }} is published by {{ tfaddrNamespace .Provider }}
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"tfaddrCanonical": templateAddrFunc(Provider.String, ModulePackage.String, Module.String),
+		"tfaddrDisplay":   templateAddrFunc(Provider.ForDisplay, ModulePackage.ForDisplay, Module.ForDisplay),
+		"tfaddrHost": templateAddrFunc(
+			func(p Provider) string { return p.Hostname.ForDisplay() },
+			func(p ModulePackage) string { return p.Host.ForDisplay() },
+			func(m Module) string { return m.Package.Host.ForDisplay() },
+		),
+		"tfaddrNamespace": templateAddrFunc(
+			func(p Provider) string { return p.Namespace },
+			func(p ModulePackage) string { return p.Namespace },
+			func(m Module) string { return m.Package.Namespace },
+		),
+		"tfaddrURLPath": templateAddrFunc(Provider.URLPathEscaped, ModulePackage.URLPathEscaped, Module.URLPathEscaped),
+	}
+}
+
+// templateAddrFunc returns a template function that calls whichever of the
+// given functions matches the type of its argument.
+func templateAddrFunc(
+	provider func(Provider) string,
+	pkg func(ModulePackage) string,
+	module func(Module) string,
+) func(any) (string, error) {
+	return func(addr any) (string, error) {
+		switch addr := addr.(type) {
+		case Provider:
+			if addr.IsZero() {
+				return "", fmt.Errorf("provider address is not set")
+			}
+			return provider(addr), nil
+		case ModulePackage:
+			return pkg(addr), nil
+		case Module:
+			return module(addr), nil
+		default:
+			return "", fmt.Errorf("unsupported address type %T; must be a Provider, ModulePackage, or Module", addr)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	data := map[string]any{
+		"Provider": MustParseProviderSource("испытание.com/hashicorp/aws"),
+		"Package":  MustParseModuleSource("hashicorp/consul/aws").Package,
+		"Module":   MustParseModuleSource("example.com/hashicorp/consul/aws//modules/consul cluster"),
+	}
+
+	tests := map[string]string{
+		`{{ tfaddrCanonical .Provider }}`: "испытание.com/hashicorp/aws",
+		`{{ tfaddrDisplay .Package }}`:    "hashicorp/consul/aws",
+		`{{ tfaddrCanonical .Package }}`:  "registry.terraform.io/hashicorp/consul/aws",
+		`{{ tfaddrHost .Provider }}`:      "испытание.com",
+		`{{ tfaddrHost .Module }}`:        "example.com",
+		`{{ tfaddrNamespace .Module }}`:   "hashicorp",
+		`{{ tfaddrDisplay .Module }}`:     "example.com/hashicorp/consul/aws//modules/consul cluster",
+		`{{ tfaddrURLPath .Provider }}`:   "xn--80akhbyknj4f.com/hashicorp/aws",
+		`{{ tfaddrURLPath .Module }}`:     "example.com/hashicorp/consul/aws//modules/consul%20cluster",
+	}
+
+	for tmpl, want := range tests {
+		t.Run(tmpl, func(t *testing.T) {
+			tt := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(tmpl))
+			var buf strings.Builder
+			if err := tt.Execute(&buf, data); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := buf.String(); got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncsInvalid(t *testing.T) {
+	tests := map[string]any{
+		`unsupported address type string; must be a Provider, ModulePackage, or Module`: "hashicorp/aws",
+		`provider address is not set`: Provider{},
+	}
+
+	for want, addr := range tests {
+		tt := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(`{{ tfaddrDisplay . }}`))
+		err := tt.Execute(&strings.Builder{}, addr)
+		if err == nil {
+			t.Errorf("unexpected success for %#v", addr)
+			continue
+		}
+		if !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("wrong error for %#v\ngot:  %s\nwant: ...%s", addr, err, want)
+		}
+	}
+}
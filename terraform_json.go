@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FromTerraformJSONProviderName parses a provider name as it appears in
+// Terraform's JSON output formats, such as the "provider_name" of resources
+// in "terraform show -json" and the keys of "provider_schemas" in
+// "terraform providers schema -json". The terraform-json library exposes
+// these names as plain strings.
+//
+// Terraform v0.13 and later always use fully-qualified names, like
+// "registry.terraform.io/hashicorp/aws", which are parsed as with
+// ParseProviderSource.
+//
+// Terraform v0.12 uses short names like "aws" instead. Those are returned
+// as legacy provider addresses, with LegacyProviderNamespace, because that's
+// how Terraform itself interprets them when upgrading from v0.12. The one
+// exception is "terraform", which is returned as the built-in provider
+// address terraform.io/builtin/terraform, again matching Terraform's own
+// upgrade behavior.
+func FromTerraformJSONProviderName(name string) (Provider, error) {
+	if strings.Contains(name, "/") {
+		return ParseProviderSource(name)
+	}
+
+	typeName, err := ParseProviderPart(name)
+	if err != nil {
+		// We'll let ParseProviderSource produce the error, so that it
+		// matches what would be returned for a fully-qualified name.
+		return ParseProviderSource(name)
+	}
+	if typeName == "terraform" {
+		return NewProvider(BuiltInProviderHost, BuiltInProviderNamespace, typeName), nil
+	}
+	return Provider{
+		Type:      typeName,
+		Namespace: LegacyProviderNamespace,
+		Hostname:  DefaultProviderRegistryHost,
+	}, nil
+}
+
+// ToTerraformJSONProviderName is the inverse of
+// FromTerraformJSONProviderName, returning the name that Terraform would use
+// for the given provider in its JSON output formats.
+//
+// Legacy provider addresses, and addresses with an unknown namespace, are
+// returned in Terraform v0.12's short form, like "aws". All other addresses
+// are returned in their fully-qualified form, like
+// "registry.terraform.io/hashicorp/aws".
+func ToTerraformJSONProviderName(p Provider) string {
+	if !p.HasKnownNamespace() || p.IsLegacy() {
+		return p.Type
+	}
+	return p.String()
+}
+
+// ProvidersFromTerraformJSON returns all of the provider addresses mentioned
+// in a document produced by "terraform show -json", describing either a
+// plan or a state, given as the result of decoding it with encoding/json
+// into a value of type any.
+//
+// The result includes the "provider_name" of every resource in the state,
+// planned values, and prior state, including those in child modules, the
+// "provider_name" of every resource change and resource drift, and the
+// "full_name" of every entry in the "provider_config" object of the
+// configuration, including providers that are configured but not used by
+// any resource. Other parts of the document, such as resource attribute
+// values, are never inspected. Each name is parsed as with
+// FromTerraformJSONProviderName.
+//
+// The result has no duplicates, and is sorted as by SortProviders.
+// ProvidersFromTerraformJSON returns an error if any of the provider
+// names is invalid.
+func ProvidersFromTerraformJSON(doc any) ([]Provider, error) {
+	seen := make(map[Provider]struct{})
+	if err := collectTerraformJSONProviders(doc, seen); err != nil {
+		return nil, err
+	}
+	ret := make([]Provider, 0, len(seen))
+	for p := range seen {
+		ret = append(ret, p)
+	}
+	SortProviders(ret)
+	return ret, nil
+}
+
+func collectTerraformJSONProviders(doc any, seen map[Provider]struct{}) error {
+	obj, _ := doc.(map[string]any)
+
+	// A state has its resources in "values", while a plan has them in
+	// "planned_values" and, for the state the plan was created from, in
+	// "prior_state".
+	modules := []any{
+		jsonObjectPath(obj, "values", "root_module"),
+		jsonObjectPath(obj, "planned_values", "root_module"),
+		jsonObjectPath(obj, "prior_state", "values", "root_module"),
+	}
+	for _, mod := range modules {
+		if err := collectTerraformJSONModuleProviders(mod, seen); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{"resource_changes", "resource_drift"} {
+		changes, _ := obj[key].([]any)
+		for _, change := range changes {
+			change, _ := change.(map[string]any)
+			if err := collectTerraformJSONProviderName(change["provider_name"], seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	configs, _ := jsonObjectPath(obj, "configuration", "provider_config").(map[string]any)
+	for _, config := range configs {
+		config, ok := config.(map[string]any)
+		if !ok {
+			continue
+		}
+		// Terraform v0.12 didn't include "full_name", so we fall back on
+		// the short "name" instead.
+		name, ok := config["full_name"]
+		if !ok {
+			name = config["name"]
+		}
+		if err := collectTerraformJSONProviderName(name, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectTerraformJSONModuleProviders collects the "provider_name" of each
+// resource in the given module, and in its child modules recursively. The
+// resources' "values" are provider-defined and so are never inspected.
+func collectTerraformJSONModuleProviders(v any, seen map[Provider]struct{}) error {
+	mod, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	resources, _ := mod["resources"].([]any)
+	for _, rs := range resources {
+		rs, _ := rs.(map[string]any)
+		if err := collectTerraformJSONProviderName(rs["provider_name"], seen); err != nil {
+			return err
+		}
+	}
+	children, _ := mod["child_modules"].([]any)
+	for _, child := range children {
+		if err := collectTerraformJSONModuleProviders(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonObjectPath returns the value at the given sequence of property names
+// in nested JSON objects, or nil if any of them is missing or isn't an
+// object.
+func jsonObjectPath(obj map[string]any, path ...string) any {
+	var v any = obj
+	for _, name := range path {
+		o, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = o[name]
+	}
+	return v
+}
+
+func collectTerraformJSONProviderName(v any, seen map[Provider]struct{}) error {
+	name, ok := v.(string)
+	if !ok || name == "" {
+		return nil
+	}
+	p, err := FromTerraformJSONProviderName(name)
+	if err != nil {
+		return fmt.Errorf("invalid provider name %q: %s", name, err)
+	}
+	seen[p] = struct{}{}
+	return nil
+}
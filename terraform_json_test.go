@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromTerraformJSONProviderName(t *testing.T) {
+	tests := map[string]struct {
+		Want     Provider
+		WantName string
+		Err      bool
+	}{
+		"registry.terraform.io/hashicorp/aws": {
+			NewProvider(DefaultProviderRegistryHost, "hashicorp", "aws"),
+			"registry.terraform.io/hashicorp/aws",
+			false,
+		},
+		"example.com/foo/bar": {
+			NewProvider("example.com", "foo", "bar"),
+			"example.com/foo/bar",
+			false,
+		},
+		"terraform.io/builtin/terraform": {
+			NewProvider(BuiltInProviderHost, BuiltInProviderNamespace, "terraform"),
+			"terraform.io/builtin/terraform",
+			false,
+		},
+		"aws": {
+			Provider{
+				Type:      "aws",
+				Namespace: LegacyProviderNamespace,
+				Hostname:  DefaultProviderRegistryHost,
+			},
+			"aws",
+			false,
+		},
+		"AWS": {
+			Provider{
+				Type:      "aws",
+				Namespace: LegacyProviderNamespace,
+				Hostname:  DefaultProviderRegistryHost,
+			},
+			"aws",
+			false,
+		},
+		"terraform": {
+			NewProvider(BuiltInProviderHost, BuiltInProviderNamespace, "terraform"),
+			"terraform.io/builtin/terraform",
+			false,
+		},
+		"bad!": {
+			Provider{},
+			"",
+			true,
+		},
+		"example.com/too/many/parts": {
+			Provider{},
+			"",
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FromTerraformJSONProviderName(name)
+			if test.Err {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := ToTerraformJSONProviderName(got); got != test.WantName {
+				t.Errorf("wrong name\ngot:  %s\nwant: %s", got, test.WantName)
+			}
+		})
+	}
+}
+
+func TestProvidersFromTerraformJSON(t *testing.T) {
+	// This is a trimmed-down plan from "terraform show -json", including
+	// a module, a provider that's configured but unused, a data source
+	// from a Terraform v0.12-style short name, and resource attribute
+	// values that happen to be named "provider_name".
+	const plan = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_instance.a",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {"provider_name": "My SAML IdP", "tags": {"provider_name": "Google"}}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.net",
+          "resources": [
+            {"address": "module.net.null_resource.b", "provider_name": "registry.terraform.io/hashicorp/null"}
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {"address": "aws_instance.a", "provider_name": "registry.terraform.io/hashicorp/aws"},
+    {"address": "data.template_file.c", "provider_name": "template"}
+  ],
+  "configuration": {
+    "provider_config": {
+      "aws": {"name": "aws", "full_name": "registry.terraform.io/hashicorp/aws"},
+      "module.net:google": {"name": "google", "full_name": "registry.terraform.io/hashicorp/google", "module_address": "module.net"},
+      "example": {"name": "example", "full_name": "example.com/test/example"}
+    },
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.a", "provider_config_key": "aws"}
+      ]
+    }
+  }
+}`
+	var doc any
+	if err := json.Unmarshal([]byte(plan), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ProvidersFromTerraformJSON(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Provider{
+		MustParseProviderSource("example.com/test/example"),
+		{Hostname: DefaultProviderRegistryHost, Namespace: LegacyProviderNamespace, Type: "template"},
+		MustParseProviderSource("hashicorp/aws"),
+		MustParseProviderSource("hashicorp/google"),
+		MustParseProviderSource("hashicorp/null"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestProvidersFromTerraformJSONInvalid(t *testing.T) {
+	doc := map[string]any{
+		"resource_changes": []any{
+			map[string]any{"provider_name": "registry.terraform.io/hashicorp/not valid"},
+		},
+	}
+	_, err := ProvidersFromTerraformJSON(doc)
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+	if got, want := err.Error(), `invalid provider name "registry.terraform.io/hashicorp/not valid"`; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s...", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// TestVectorsVersion is the version of the corpus returned by TestVectors.
+// It increases whenever an existing vector changes meaning, but not when
+// vectors are only added.
+const TestVectorsVersion = 1
+
+// TestVector is an example input to ParseProviderSource or
+// ParseModuleSource, along with the expected result, as returned by
+// TestVectors.
+type TestVector struct {
+	// Kind is ProviderKind for an input to ParseProviderSource, or
+	// ModuleRegistryKind for an input to ParseModuleSource.
+	Kind AddressKind
+
+	// Input is the source string to parse.
+	Input string
+
+	// Valid is true if parsing Input is expected to succeed.
+	Valid bool
+
+	// Canonical and Display are the expected results of String and
+	// ForDisplay respectively for a valid input, or empty for an invalid
+	// input.
+	Canonical string
+	Display   string
+}
+
+//go:embed test_vectors.json
+var testVectorsRaw []byte
+
+// testVectors is the parsed form of testVectorsRaw, populated on the first
+// call to TestVectors so that programs that never call it don't pay for
+// parsing it.
+var (
+	testVectors     []TestVector
+	testVectorsOnce sync.Once
+)
+
+func mustParseTestVectors(raw []byte) []TestVector {
+	var file struct {
+		Version int `json:"version"`
+		Vectors []struct {
+			Kind      string `json:"kind"`
+			Input     string `json:"input"`
+			Valid     bool   `json:"valid"`
+			Canonical string `json:"canonical"`
+			Display   string `json:"display"`
+		} `json:"vectors"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		panic(fmt.Sprintf("invalid test_vectors.json: %s", err))
+	}
+	if file.Version != TestVectorsVersion {
+		panic(fmt.Sprintf("test_vectors.json has version %d, but TestVectorsVersion is %d", file.Version, TestVectorsVersion))
+	}
+
+	ret := make([]TestVector, len(file.Vectors))
+	for i, v := range file.Vectors {
+		ret[i] = TestVector{
+			Input:     v.Input,
+			Valid:     v.Valid,
+			Canonical: v.Canonical,
+			Display:   v.Display,
+		}
+		switch v.Kind {
+		case "provider":
+			ret[i].Kind = ProviderKind
+		case "module":
+			ret[i].Kind = ModuleRegistryKind
+		default:
+			panic(fmt.Sprintf("invalid kind %q in test_vectors.json", v.Kind))
+		}
+	}
+	return ret
+}
+
+// TestVectors returns a corpus of valid and invalid source strings along
+// with the results this package produces for them, so that other
+// implementations of the address syntax, such as in registries written in
+// other languages, can check that they behave in the same way.
+//
+// The same corpus is available in the file test_vectors.json in this
+// package's source repository, for use without Go. Its "version" property
+// is TestVectorsVersion.
+//
+// The result is a new slice on each call, so the caller may modify it.
+func TestVectors() []TestVector {
+	testVectorsOnce.Do(func() {
+		testVectors = mustParseTestVectors(testVectorsRaw)
+	})
+	ret := make([]TestVector, len(testVectors))
+	copy(ret, testVectors)
+	return ret
+}
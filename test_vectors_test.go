@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+)
+
+func TestTestVectors(t *testing.T) {
+	vectors := TestVectors()
+	if len(vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+
+	for _, v := range vectors {
+		t.Run(v.Kind.String()+" "+v.Input, func(t *testing.T) {
+			var canonical, display string
+			var err error
+			switch v.Kind {
+			case ProviderKind:
+				var addr Provider
+				addr, err = ParseProviderSource(v.Input)
+				if err == nil {
+					canonical, display = addr.String(), addr.ForDisplay()
+				}
+			case ModuleRegistryKind:
+				var addr Module
+				addr, err = ParseModuleSource(v.Input)
+				if err == nil {
+					canonical, display = addr.String(), addr.ForDisplay()
+				}
+			default:
+				t.Fatalf("unsupported kind %s", v.Kind)
+			}
+
+			if v.Valid != (err == nil) {
+				t.Fatalf("wrong validity; want valid=%t, but got error: %v", v.Valid, err)
+			}
+			if canonical != v.Canonical {
+				t.Errorf("wrong canonical form %q; want %q", canonical, v.Canonical)
+			}
+			if display != v.Display {
+				t.Errorf("wrong display form %q; want %q", display, v.Display)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyAddress is returned when unmarshaling an address from an empty
+// string, unless the address is wrapped in Optional.
+var ErrEmptyAddress = errors.New("address must not be empty")
+
+// The methods in this file implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler, so that addresses can be used as strings in
+// encodings such as JSON, including as map keys.
+//
+// An address is represented by the result of its String method, except as
+// described for Provider.MarshalText. The zero
+// value has no text representation, and so MarshalText returns an error for
+// it and UnmarshalText returns ErrEmptyAddress for empty text. Wrap an
+// address in Optional to represent the zero value as empty text instead.
+//
+// When decoding JSON with encoding/json, a JSON null leaves the address
+// unchanged, which for a newly-declared value is the zero value. The
+// methods in jsonv2.go, which encoding/json prefers when built with
+// GOEXPERIMENT=jsonv2, follow the same rules.
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// An address with an unknown namespace, as produced by parsing a source
+// string like "aws", has no fully-qualified form that can be parsed, so it
+// is represented by its ShortestForm instead.
+func (pt Provider) MarshalText() ([]byte, error) {
+	if pt.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of Provider")
+	}
+	if pt.Namespace == UnknownProviderNamespace && pt.Hostname != DefaultProviderRegistryHost {
+		return nil, fmt.Errorf("cannot marshal provider %q with an unknown namespace on a host other than %s", pt.Type, DefaultProviderRegistryHost.ForDisplay())
+	}
+	return []byte(pt.sourceString()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseProviderSource.
+func (pt *Provider) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseProviderSource(string(text))
+	if err != nil {
+		return err
+	}
+	*pt = addr
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ModulePackage) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of ModulePackage")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseModuleSource. The text must not include a subdirectory.
+func (s *ModulePackage) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseModuleSource(string(text))
+	if err != nil {
+		return err
+	}
+	if addr.Subdir != "" {
+		return fmt.Errorf("a module package address may not include a subdirectory")
+	}
+	*s = addr.Package
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Module) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of Module")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseModuleSource.
+func (s *Module) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseModuleSource(string(text))
+	if err != nil {
+		return err
+	}
+	*s = addr
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ModuleSourceRemote) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of ModuleSourceRemote")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseModuleSourceRemote.
+func (s *ModuleSourceRemote) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseModuleSourceRemote(string(text))
+	if err != nil {
+		return err
+	}
+	*s = addr
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ModuleSourceGitHub) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of ModuleSourceGitHub")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseModuleSourceGitHub.
+func (s *ModuleSourceGitHub) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseModuleSourceGitHub(string(text))
+	if err != nil {
+		return err
+	}
+	*s = addr
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ModuleSourceBitbucket) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero value of ModuleSourceBitbucket")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the given text
+// as with ParseModuleSourceBitbucket.
+func (s *ModuleSourceBitbucket) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyAddress
+	}
+	addr, err := ParseModuleSourceBitbucket(string(text))
+	if err != nil {
+		return err
+	}
+	*s = addr
+	return nil
+}
+
+// Optional wraps an address so that its zero value is represented as empty
+// text, rather than being an error to marshal or unmarshal.
+//
+// This is intended for optional fields in configuration files, where an
+// empty string means that no address was given.
+type Optional[T Provider | ModulePackage | Module] struct {
+	Addr T
+}
+
+// IsZero returns true if the wrapped address is the zero value.
+func (o Optional[T]) IsZero() bool {
+	var zero T
+	return o.Addr == zero
+}
+
+// MarshalText implements encoding.TextMarshaler, returning empty text for
+// the zero value.
+func (o Optional[T]) MarshalText() ([]byte, error) {
+	if o.IsZero() {
+		return []byte{}, nil
+	}
+	return any(o.Addr).(encoding.TextMarshaler).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, setting the wrapped
+// address to the zero value for empty text.
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		var zero T
+		o.Addr = zero
+		return nil
+	}
+	return any(&o.Addr).(encoding.TextUnmarshaler).UnmarshalText(text)
+}
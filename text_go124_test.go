@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.24
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// The "omitzero" option was added to encoding/json in Go 1.24, and relies
+// on the IsZero methods of the address types.
+
+func TestOmitZero(t *testing.T) {
+	type document struct {
+		Provider Provider      `json:"provider,omitzero"`
+		Package  ModulePackage `json:"package,omitzero"`
+		Module   Module        `json:"module,omitzero"`
+	}
+
+	got, err := json.Marshal(document{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `{}`; string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	got, err = json.Marshal(document{Module: MustParseModuleSource("hashicorp/consul/aws")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `{"module":"registry.terraform.io/hashicorp/consul/aws"}`; string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	type document struct {
+		Provider Provider      `json:"provider"`
+		Package  ModulePackage `json:"package"`
+		Module   Module        `json:"module"`
+	}
+
+	doc := document{
+		Provider: MustParseProviderSource("hashicorp/aws"),
+		Package:  MustParseModuleSource("example.com/hashicorp/consul/aws").Package,
+		Module:   MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+	}
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"provider":"registry.terraform.io/hashicorp/aws","package":"example.com/hashicorp/consul/aws","module":"registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster"}`
+	if string(got) != want {
+		t.Fatalf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	var back document
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	if diff := cmp.Diff(doc, back); diff != "" {
+		t.Errorf("wrong result decoding\n%s", diff)
+	}
+}
+
+func TestModuleSourceTextRoundTrip(t *testing.T) {
+	type document struct {
+		Remote    ModuleSourceRemote    `json:"remote"`
+		GitHub    ModuleSourceGitHub    `json:"github"`
+		Bitbucket ModuleSourceBitbucket `json:"bitbucket"`
+	}
+
+	input := `{"remote":"git::https://example.com/network.git//vpc?ref=v1.2.0","github":"github.com/hashicorp/example?ref=main","bitbucket":"bitbucket.org/hashicorp/example//modules/a"}`
+	var doc document
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := doc.GitHub.Ref, "main"; got != want {
+		t.Errorf("wrong GitHub ref %q; want %q", got, want)
+	}
+
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != input {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, input)
+	}
+
+	var remote ModuleSourceRemote
+	if err := json.Unmarshal([]byte(`"hashicorp/consul/aws"`), &remote); err == nil {
+		t.Errorf("unexpected success decoding registry address as remote address")
+	}
+}
+
+func TestProviderTextUnknownNamespace(t *testing.T) {
+	p := MustParseProviderSource("aws")
+	text, err := p.MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := string(text), "aws"; got != want {
+		t.Errorf("wrong text %q; want %q", got, want)
+	}
+	var back Provider
+	if err := back.UnmarshalText(text); err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	if back != p {
+		t.Errorf("wrong result decoding: %#v", back)
+	}
+
+	other := Provider{Hostname: svchost.Hostname("example.com"), Namespace: UnknownProviderNamespace, Type: "aws"}
+	if _, err := other.MarshalText(); err == nil {
+		t.Errorf("unexpected success marshaling unknown namespace on another host")
+	}
+}
+
+func TestUnmarshalTextEmpty(t *testing.T) {
+	targets := map[string]interface{ UnmarshalText([]byte) error }{
+		"provider":       new(Provider),
+		"module package": new(ModulePackage),
+		"module":         new(Module),
+		"remote module":  new(ModuleSourceRemote),
+		"GitHub module":  new(ModuleSourceGitHub),
+		"Bitbucket":      new(ModuleSourceBitbucket),
+	}
+	for name, target := range targets {
+		if err := target.UnmarshalText(nil); !errors.Is(err, ErrEmptyAddress) {
+			t.Errorf("wrong error for %s: %v", name, err)
+		}
+	}
+}
+
+func TestUnmarshalTextNull(t *testing.T) {
+	var doc struct {
+		Provider Provider `json:"provider"`
+	}
+	if err := json.Unmarshal([]byte(`{"provider":null}`), &doc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !doc.Provider.IsZero() {
+		t.Errorf("null decoded as %#v; want zero value", doc.Provider)
+	}
+}
+
+func TestMarshalTextZero(t *testing.T) {
+	values := map[string]interface{ MarshalText() ([]byte, error) }{
+		"provider":       Provider{},
+		"module package": ModulePackage{},
+		"module":         Module{},
+		"remote module":  ModuleSourceRemote{},
+		"GitHub module":  ModuleSourceGitHub{},
+		"Bitbucket":      ModuleSourceBitbucket{},
+	}
+	for name, v := range values {
+		if _, err := v.MarshalText(); err == nil {
+			t.Errorf("unexpected success marshaling zero %s", name)
+		}
+	}
+}
+
+func TestOptional(t *testing.T) {
+	type document struct {
+		Provider Optional[Provider] `json:"provider"`
+		Module   Optional[Module]   `json:"module"`
+	}
+
+	var doc document
+	if err := json.Unmarshal([]byte(`{"provider":"","module":"hashicorp/consul/aws"}`), &doc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !doc.Provider.IsZero() {
+		t.Errorf("empty provider decoded as %#v; want zero value", doc.Provider.Addr)
+	}
+	if got, want := doc.Module.Addr, MustParseModuleSource("hashicorp/consul/aws"); got != want {
+		t.Errorf("wrong module %s; want %s", got, want)
+	}
+
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `{"provider":"","module":"registry.terraform.io/hashicorp/consul/aws"}`
+	if string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+
+	var pkg Optional[ModulePackage]
+	if err := pkg.UnmarshalText([]byte("hashicorp/consul/aws//modules/consul-cluster")); err == nil {
+		t.Errorf("unexpected success with subdirectory")
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !(ModulePackage{}).IsZero() || MustParseModuleSource("hashicorp/consul/aws").Package.IsZero() {
+		t.Error("wrong result from ModulePackage.IsZero")
+	}
+	if !(Module{}).IsZero() || MustParseModuleSource("hashicorp/consul/aws").IsZero() {
+		t.Error("wrong result from Module.IsZero")
+	}
+	if !(Optional[Module]{}).IsZero() {
+		t.Error("wrong result from Optional.IsZero")
+	}
+}
+
+// TestJSONEmptyAndNull checks the empty and null rules through
+// encoding/json rather than by calling the methods directly, because
+// encoding/json prefers the methods in jsonv2.go when they are available.
+// It should pass both with and without GOEXPERIMENT=jsonv2.
+func TestJSONEmptyAndNull(t *testing.T) {
+	type document struct {
+		Provider Provider      `json:"provider"`
+		Package  ModulePackage `json:"package"`
+		Module   Module        `json:"module"`
+	}
+
+	for _, field := range []string{"provider", "package", "module"} {
+		t.Run(field, func(t *testing.T) {
+			var doc document
+			err := json.Unmarshal([]byte(`{"`+field+`":""}`), &doc)
+			if !errors.Is(err, ErrEmptyAddress) {
+				t.Errorf("wrong error for empty string: %v", err)
+			}
+
+			doc = document{
+				Provider: MustParseProviderSource("hashicorp/aws"),
+				Package:  MustParseModuleSource("hashicorp/consul/aws").Package,
+				Module:   MustParseModuleSource("hashicorp/consul/aws"),
+			}
+			want := doc
+			if err := json.Unmarshal([]byte(`{"`+field+`":null}`), &doc); err != nil {
+				t.Fatalf("unexpected error for null: %s", err)
+			}
+			if diff := cmp.Diff(want, doc); diff != "" {
+				t.Errorf("null changed the address\n%s", diff)
+			}
+		})
+	}
+
+	for name, v := range map[string]any{
+		"provider": struct{ P Provider }{},
+		"package":  struct{ P ModulePackage }{},
+		"module":   struct{ P Module }{},
+	} {
+		if got, err := json.Marshal(v); err == nil {
+			t.Errorf("unexpected success marshaling zero %s: %s", name, got)
+		}
+	}
+}
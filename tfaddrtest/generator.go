@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddrtest
+
+import (
+	"math/rand"
+	"strings"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+// Generator produces random addresses for property-based testing.
+//
+// A Generator is deterministic: two generators created with the same seed
+// produce the same sequence of results. A Generator is not safe for
+// concurrent use.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator returns a Generator whose results are determined by the
+// given seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// NewGeneratorFrom returns a Generator that takes its randomness from the
+// given source, such as the one passed to a testing/quick.Generator.
+func NewGeneratorFrom(r *rand.Rand) *Generator {
+	return &Generator{rand: r}
+}
+
+// Provider returns a random valid provider address.
+func (g *Generator) Provider() tfaddr.Provider {
+	return tfaddr.Provider{}.Generate(g.rand, generatorSize).Interface().(tfaddr.Provider)
+}
+
+// ModulePackage returns a random valid module package address.
+func (g *Generator) ModulePackage() tfaddr.ModulePackage {
+	return tfaddr.ModulePackage{}.Generate(g.rand, generatorSize).Interface().(tfaddr.ModulePackage)
+}
+
+// Module returns a random valid module address, which has a subdirectory
+// about half of the time.
+func (g *Generator) Module() tfaddr.Module {
+	return tfaddr.Module{}.Generate(g.rand, generatorSize).Interface().(tfaddr.Module)
+}
+
+// generatorSize is the maximum length of each segment of the addresses
+// returned by a Generator.
+const generatorSize = 20
+
+// AdversarialSource returns a source string derived from a random valid
+// provider or module address by applying a random mutation, such as
+// inserting an unusual character or removing a segment. The result may or
+// may not be a valid address.
+func (g *Generator) AdversarialSource() string {
+	var src string
+	if g.rand.Intn(2) == 0 {
+		src = g.Provider().String()
+	} else {
+		src = g.Module().String()
+	}
+
+	switch g.rand.Intn(4) {
+	case 0:
+		insert := adversarialFragments[g.rand.Intn(len(adversarialFragments))]
+		pos := g.rand.Intn(len(src) + 1)
+		return src[:pos] + insert + src[pos:]
+	case 1:
+		return src[:g.rand.Intn(len(src)+1)]
+	case 2:
+		parts := strings.Split(src, "/")
+		i := g.rand.Intn(len(parts))
+		return strings.Join(append(parts[:i:i], parts[i+1:]...), "/")
+	default:
+		pos := g.rand.Intn(len(src))
+		return src[:pos] + strings.ToUpper(src[pos:pos+1]) + src[pos+1:]
+	}
+}
+
+var adversarialFragments = []string{
+	"/", "//", "..", "./", "../", "-", "--", "_", ".", ":", "?", "\\", "%",
+	" ", "xn--", "\x00", "\xff", "İ", "ß", "ǅ", "${", "%{",
+}
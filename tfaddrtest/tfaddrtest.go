@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tfaddrtest contains helpers for testing code that works with the
+// address types from package tfaddr, including generators of random
+// addresses for property-based testing.
+package tfaddrtest
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+// MustProvider parses the given provider source string, failing the test
+// immediately if it is invalid.
+func MustProvider(t testing.TB, src string) tfaddr.Provider {
+	t.Helper()
+	addr, err := tfaddr.ParseProviderSource(src)
+	if err != nil {
+		t.Fatalf("invalid provider source %q: %s", src, err)
+	}
+	return addr
+}
+
+// MustModule parses the given module registry source string, failing the
+// test immediately if it is invalid.
+func MustModule(t testing.TB, src string) tfaddr.Module {
+	t.Helper()
+	addr, err := tfaddr.ParseModuleSource(src)
+	if err != nil {
+		t.Fatalf("invalid module source %q: %s", src, err)
+	}
+	return addr
+}
+
+// ReadCorpus reads a corpus of source strings from the given reader, with
+// one source string per line. Blank lines and lines starting with "#" are
+// ignored, and leading and trailing whitespace is removed from each line.
+func ReadCorpus(r io.Reader) ([]string, error) {
+	var ret []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ret = append(ret, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// LoadCorpus reads a corpus of source strings from the file at the given
+// path, in the format described for ReadCorpus.
+func LoadCorpus(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCorpus(f)
+}
+
+// AdversarialSources returns a fixed set of source strings that exercise
+// edge cases in address parsing, such as unusual characters, punycode,
+// path traversal, and segments that are empty or have the wrong count.
+// Some of them are valid addresses and some are not.
+//
+// The result is a new slice on each call, so the caller may modify it.
+func AdversarialSources() []string {
+	ret := make([]string, len(adversarialSources))
+	copy(ret, adversarialSources)
+	return ret
+}
+
+var adversarialSources = []string{
+	"",
+	"/",
+	"//",
+	"aws",
+	"-/aws",
+	"?/aws",
+	"hashicorp/",
+	"/hashicorp/aws",
+	"hashicorp//aws",
+	"hashicorp/aws/",
+	"HashiCorp/AWS",
+	"registry.terraform.io/hashicorp/aws",
+	"example.com/-/aws",
+	"example.com:443/hashicorp/aws",
+	"example.com:0/hashicorp/aws",
+	"example.com:99999/hashicorp/aws",
+	"xn--80akhbyknj4f.com/hashicorp/aws",
+	"испытание.com/испытание/aws",
+	"hashicorp/terraform-provider-aws",
+	"hashicorp/terraform-aws",
+	"hash--icorp/aws",
+	"-hashicorp/aws",
+	"hashicorp-/aws",
+	"hashi_corp/aws",
+	"hashicorp/a.ws",
+	"İstanbul/aws",
+	"Straße/aws",
+	"ǅ/aws",
+	"a/b/c/d",
+	"a/b/c/d/e",
+	"hashicorp/consul/aws",
+	"hashicorp/consul/aws//modules/foo",
+	"hashicorp/consul/aws//../foo",
+	"hashicorp/consul/aws//modules/../../foo",
+	"hashicorp/consul/aws//./",
+	`hashicorp/consul/aws//modules\foo`,
+	"hashicorp/consul/aws?ref=v1.0.0",
+	"hashicorp/consul/aws//foo?ref=v1.0.0",
+	"github.com/hashicorp/consul/aws",
+	"bitbucket.org/hashicorp/consul/aws",
+	"localhost/hashicorp/consul/aws",
+	"git::https://example.com/consul.git",
+	"https://example.com/consul.zip",
+	"./modules/consul",
+	"hashicorp/consul_/aws",
+	"hashicorp/_consul/aws",
+	"hashicorp/consul/AWS",
+	"hashicorp/consul/a-ws",
+	"${var.namespace}/consul/aws",
+	"hashicorp/%{if true}consul%{endif}/aws",
+	"hashicorp/aws\x00",
+	"hashicorp/\xff",
+	" hashicorp/aws",
+	"hashicorp/aws ",
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddrtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+func TestGeneratorValid(t *testing.T) {
+	g := NewGenerator(1)
+	for i := 0; i < 1000; i++ {
+		p := g.Provider()
+		if got, err := tfaddr.ParseProviderSource(p.String()); err != nil {
+			t.Fatalf("generated invalid provider %#v: %s", p, err)
+		} else if got != p {
+			t.Fatalf("generated non-normalized provider %#v", p)
+		}
+
+		m := g.Module()
+		if got, err := tfaddr.ParseModuleSource(m.String()); err != nil {
+			t.Fatalf("generated invalid module %#v: %s", m, err)
+		} else if got != m {
+			t.Fatalf("generated non-normalized module %#v", m)
+		}
+	}
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	a, b := NewGenerator(42), NewGenerator(42)
+	for i := 0; i < 100; i++ {
+		if got, want := a.AdversarialSource(), b.AdversarialSource(); got != want {
+			t.Fatalf("different results for the same seed\na: %q\nb: %q", got, want)
+		}
+	}
+}
+
+func TestAdversarialSources(t *testing.T) {
+	// The parsers must never panic, regardless of input.
+	sources := AdversarialSources()
+	g := NewGenerator(1)
+	for i := 0; i < 1000; i++ {
+		sources = append(sources, g.AdversarialSource())
+	}
+	for _, src := range sources {
+		tfaddr.ParseProviderSource(src)
+		tfaddr.ParseModuleSource(src)
+	}
+}
+
+func TestReadCorpus(t *testing.T) {
+	got, err := ReadCorpus(strings.NewReader("# comment\nhashicorp/aws\n\n  hashicorp/consul/aws  \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"hashicorp/aws", "hashicorp/consul/aws"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestMust(t *testing.T) {
+	if got, want := MustProvider(t, "hashicorp/aws").String(), "registry.terraform.io/hashicorp/aws"; got != want {
+		t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := MustModule(t, "hashicorp/consul/aws").String(), "registry.terraform.io/hashicorp/consul/aws"; got != want {
+		t.Errorf("wrong module\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// TrustPolicy describes which providers and module packages a program
+// should accept, such as in an admission controller that checks the
+// dependencies of a configuration before running it.
+//
+// A TrustPolicy is evaluated in the following order, and the first step
+// that matches determines the decision:
+//
+//  1. Each of the Rules, in order.
+//  2. AllowedHosts, which allows any address on one of the given hosts.
+//  3. AllowedNamespaces, which allows any address in one of the given
+//     namespaces.
+//
+// If nothing matches then the address is denied, unless both AllowedHosts
+// and AllowedNamespaces are empty, in which case it is allowed. The zero
+// value of TrustPolicy therefore allows everything.
+type TrustPolicy struct {
+	Rules             []TrustRule
+	AllowedHosts      []svchost.Hostname
+	AllowedNamespaces []RegistryNamespace
+}
+
+// TrustRule is a rule in a TrustPolicy that allows or denies addresses
+// whose full string form matches a pattern.
+type TrustRule struct {
+	// Pattern uses the syntax of path.Match, and is matched against the
+	// result of the String method of a Provider or ModulePackage, such as
+	// "registry.terraform.io/hashicorp/aws". A "*" matches any sequence of
+	// characters within a single segment, so for example
+	// "registry.terraform.io/hashicorp/*" matches all of the providers in
+	// the "hashicorp" namespace.
+	//
+	// Patterns are matched case-insensitively.
+	Pattern string
+
+	// Allow is true for a rule that allows matching addresses, or false for
+	// one that denies them.
+	Allow bool
+}
+
+// Decision is the result of evaluating an address against a TrustPolicy.
+type Decision struct {
+	// Allowed is true if the policy allows the address.
+	Allowed bool
+
+	// Rule describes the part of the policy that determined the decision:
+	// the pattern of a TrustRule, the display form of a hostname from
+	// AllowedHosts, or the String form of a namespace from
+	// AllowedNamespaces. It is empty if no part of the policy matched and
+	// so the default applied.
+	Rule string
+}
+
+func (d Decision) String() string {
+	verb := "denied"
+	if d.Allowed {
+		verb = "allowed"
+	}
+	if d.Rule == "" {
+		return verb + " by default"
+	}
+	return fmt.Sprintf("%s by %q", verb, d.Rule)
+}
+
+// Validate returns an error if any of the receiver's rules has a malformed
+// pattern.
+func (tp TrustPolicy) Validate() error {
+	for i, rule := range tp.Rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("rule %d has invalid pattern %q: %s", i, rule.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// EvaluateProvider decides whether the policy allows the given provider.
+//
+// Providers with the legacy or unknown namespace are not in any of the
+// AllowedNamespaces, but may still match the other parts of the policy.
+//
+// A rule with a malformed pattern matches every address and denies it,
+// so that mistakes in a policy fail closed. Use Validate to detect
+// malformed patterns in advance.
+func (tp TrustPolicy) EvaluateProvider(addr Provider) Decision {
+	return tp.evaluate(addr.String(), addr.Hostname, func(ns RegistryNamespace) bool {
+		return ns.ContainsProvider(addr)
+	})
+}
+
+// EvaluateModulePackage decides whether the policy allows the given module
+// package, as described for EvaluateProvider.
+//
+// To evaluate a Module, pass its Package field. The subdirectory of a
+// module doesn't affect whether it's trusted.
+func (tp TrustPolicy) EvaluateModulePackage(addr ModulePackage) Decision {
+	return tp.evaluate(addr.String(), addr.Host, func(ns RegistryNamespace) bool {
+		return ns.ContainsModulePackage(addr)
+	})
+}
+
+func (tp TrustPolicy) evaluate(str string, host svchost.Hostname, inNamespace func(RegistryNamespace) bool) Decision {
+	str = strings.ToLower(str)
+	for _, rule := range tp.Rules {
+		matched, err := path.Match(strings.ToLower(rule.Pattern), str)
+		if err != nil {
+			return Decision{Allowed: false, Rule: rule.Pattern}
+		}
+		if matched {
+			return Decision{Allowed: rule.Allow, Rule: rule.Pattern}
+		}
+	}
+	for _, allowed := range tp.AllowedHosts {
+		if allowed == host {
+			return Decision{Allowed: true, Rule: allowed.ForDisplay()}
+		}
+	}
+	for _, allowed := range tp.AllowedNamespaces {
+		if inNamespace(allowed) {
+			return Decision{Allowed: true, Rule: allowed.String()}
+		}
+	}
+	return Decision{Allowed: len(tp.AllowedHosts) == 0 && len(tp.AllowedNamespaces) == 0}
+}
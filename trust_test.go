@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+func TestTrustPolicy(t *testing.T) {
+	policy := TrustPolicy{
+		Rules: []TrustRule{
+			{Pattern: "registry.terraform.io/hashicorp/null", Allow: false},
+			{Pattern: "registry.terraform.io/Terraform-AWS-Modules/*/aws", Allow: true},
+		},
+		AllowedHosts: []svchost.Hostname{"example.com"},
+		AllowedNamespaces: []RegistryNamespace{
+			MustParseRegistryNamespace("hashicorp"),
+		},
+	}
+
+	providers := map[string]Decision{
+		"hashicorp/aws":             {Allowed: true, Rule: "registry.terraform.io/hashicorp"},
+		"HashiCorp/AWS":             {Allowed: true, Rule: "registry.terraform.io/hashicorp"},
+		"hashicorp/null":            {Allowed: false, Rule: "registry.terraform.io/hashicorp/null"},
+		"example.com/anyone/thing":  {Allowed: true, Rule: "example.com"},
+		"integrations/github":       {Allowed: false},
+		"example.net/hashicorp/aws": {Allowed: false},
+		"aws":                       {Allowed: false},
+	}
+	for src, want := range providers {
+		got := policy.EvaluateProvider(MustParseProviderSource(src))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong decision for provider %q\n%s", src, diff)
+		}
+	}
+
+	modules := map[string]Decision{
+		"hashicorp/consul/aws":                 {Allowed: true, Rule: "registry.terraform.io/hashicorp"},
+		"terraform-aws-modules/vpc/aws":        {Allowed: true, Rule: "registry.terraform.io/Terraform-AWS-Modules/*/aws"},
+		"terraform-aws-modules/vpc/azurerm":    {Allowed: false},
+		"terraform-aws-modules/vpc/aws//a/b/c": {Allowed: true, Rule: "registry.terraform.io/Terraform-AWS-Modules/*/aws"},
+	}
+	for src, want := range modules {
+		got := policy.EvaluateModulePackage(MustParseModuleSource(src).Package)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong decision for module %q\n%s", src, diff)
+		}
+	}
+}
+
+func TestTrustPolicyDefault(t *testing.T) {
+	addr := MustParseProviderSource("hashicorp/aws")
+
+	got := TrustPolicy{}.EvaluateProvider(addr)
+	if want := (Decision{Allowed: true}); got != want {
+		t.Errorf("wrong decision for zero value policy: %s", got)
+	}
+
+	policy := TrustPolicy{Rules: []TrustRule{{Pattern: "*/*/*", Allow: false}}}
+	got = policy.EvaluateProvider(addr)
+	if want := (Decision{Allowed: false, Rule: "*/*/*"}); got != want {
+		t.Errorf("wrong decision for deny-all rule: %s", got)
+	}
+}
+
+func TestTrustPolicyMalformedPattern(t *testing.T) {
+	policy := TrustPolicy{Rules: []TrustRule{{Pattern: "registry.terraform.io/[", Allow: true}}}
+
+	if err := policy.Validate(); err == nil {
+		t.Error("malformed pattern passed validation")
+	}
+	got := policy.EvaluateProvider(MustParseProviderSource("hashicorp/aws"))
+	if want := (Decision{Allowed: false, Rule: "registry.terraform.io/["}); got != want {
+		t.Errorf("wrong decision for malformed pattern: %s", got)
+	}
+}
+
+func TestDecisionString(t *testing.T) {
+	tests := map[Decision]string{
+		{Allowed: true}:                    "allowed by default",
+		{Allowed: false}:                   "denied by default",
+		{Allowed: true, Rule: "hashicorp"}: `allowed by "hashicorp"`,
+	}
+	for decision, want := range tests {
+		if got := decision.String(); got != want {
+			t.Errorf("wrong string %q; want %q", got, want)
+		}
+	}
+}
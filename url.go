@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// URLScheme is the URI scheme used for the URL forms of provider and module
+// addresses, as returned by methods like Provider.ToURL.
+//
+// The URL form of an address is intended for systems that require
+// identifiers to be URIs, such as SBOM documents and OCI annotations. It
+// is not a location that can be retrieved.
+//
+// The URL forms are defined as follows:
+//
+//	tfregistry://<hostname>/<namespace>/<type>
+//	tfregistry://<hostname>/<namespace>/<name>/<target system>
+//	tfregistry://<hostname>/<namespace>/<name>/<target system>?subdir=<subdir>
+//
+// The first is a provider address, and the others are module addresses,
+// the last with a subdirectory. The hostname is always included, even if
+// it is the default, and internationalized hostnames are given in their
+// ASCII-compatible "punycode" form, as RFC 3986 requires. Any other
+// non-ASCII characters are percent-encoded.
+const URLScheme = "tfregistry"
+
+// ToURL returns the URL form of the address, as described for URLScheme.
+//
+// Addresses with an unknown namespace, as produced by parsing a source
+// string like "aws", have no valid URL form. The result of ToURL for such an
+// address will fail to parse.
+func (pt Provider) ToURL() *url.URL {
+	return &url.URL{
+		Scheme: URLScheme,
+		Host:   pt.Hostname.String(),
+		Path:   "/" + pt.Namespace + "/" + pt.Type,
+	}
+}
+
+// ToURL returns the URL form of the module package address, as described
+// for URLScheme.
+func (s ModulePackage) ToURL() *url.URL {
+	return &url.URL{
+		Scheme: URLScheme,
+		Host:   s.Host.String(),
+		Path:   "/" + s.ForRegistryProtocol(),
+	}
+}
+
+// ToURL returns the URL form of the module address, as described for
+// URLScheme.
+func (s Module) ToURL() *url.URL {
+	ret := s.Package.ToURL()
+	if s.Subdir != "" {
+		ret.RawQuery = url.Values{"subdir": {s.Subdir}}.Encode()
+	}
+	return ret
+}
+
+// ParseURL parses the URL form of either a provider or a module address, as
+// described for URLScheme, returning either a Provider or a Module
+// respectively.
+func ParseURL(raw string) (any, error) {
+	_, parts, err := parseRegistryURL(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL %q: %s", raw, err)
+	}
+	if len(parts) == 2 {
+		return ParseProviderURL(raw)
+	}
+	return ParseModuleURL(raw)
+}
+
+// ParseProviderURL parses the URL form of a provider address, as described
+// for URLScheme.
+func ParseProviderURL(raw string) (Provider, error) {
+	u, parts, err := parseRegistryURL(raw)
+	if err != nil {
+		return Provider{}, &ParserError{
+			Summary: "Invalid provider URL",
+			Detail:  fmt.Sprintf("Invalid provider URL %q: %s.", raw, err),
+		}
+	}
+	if len(parts) != 2 || u.RawQuery != "" {
+		return Provider{}, &ParserError{
+			Summary: "Invalid provider URL",
+			Detail:  fmt.Sprintf("Invalid provider URL %q: must have the form %s://hostname/namespace/type.", raw, URLScheme),
+		}
+	}
+	return ParseProviderSource(u.Host + "/" + strings.Join(parts, "/"))
+}
+
+// ParseModuleURL parses the URL form of a module address, as described for
+// URLScheme.
+func ParseModuleURL(raw string) (Module, error) {
+	u, parts, err := parseRegistryURL(raw)
+	if err != nil {
+		return Module{}, fmt.Errorf("invalid module URL %q: %s", raw, err)
+	}
+	if len(parts) != 3 {
+		return Module{}, fmt.Errorf("invalid module URL %q: must have the form %s://hostname/namespace/name/system", raw, URLScheme)
+	}
+	query := u.Query()
+	subdir := query.Get("subdir")
+	query.Del("subdir")
+	if len(query) != 0 {
+		return Module{}, fmt.Errorf("invalid module URL %q: the only supported query argument is \"subdir\"", raw)
+	}
+
+	source := u.Host + "/" + strings.Join(parts, "/")
+	if subdir != "" {
+		source += "//" + subdir
+	}
+	return ParseModuleSource(source)
+}
+
+// parseRegistryURL checks the parts of the given URL that are common to all
+// address types, returning the parsed URL and its decoded path segments.
+//
+// The host of the returned URL is converted to its Unicode form, ready to
+// be used as part of a source string.
+func parseRegistryURL(raw string) (*url.URL, []string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case u.Scheme != URLScheme:
+		return nil, nil, fmt.Errorf("must use the %q scheme", URLScheme)
+	case u.Opaque != "" || u.Host == "":
+		return nil, nil, fmt.Errorf("must include a hostname")
+	case u.User != nil:
+		return nil, nil, fmt.Errorf("must not include user information")
+	case u.Fragment != "":
+		return nil, nil, fmt.Errorf("must not include a fragment")
+	}
+
+	u.Host, err = hostForSource(u.Hostname(), u.Port())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, nil, fmt.Errorf("must have either two or three path segments")
+	}
+	return u, parts, nil
+}
+
+// hostForSource converts the given hostname and optional port, as found in a
+// URL, into the form required in a source string.
+//
+// Source strings must use the Unicode form of internationalized hostnames
+// rather than the punycode form used in URLs.
+func hostForSource(host, port string) (string, error) {
+	host, err := idna.Lookup.ToUnicode(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid hostname")
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	return host, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderURL(t *testing.T) {
+	tests := map[string]struct {
+		Input Provider
+		Want  string
+	}{
+		"default host": {
+			MustParseProviderSource("hashicorp/aws"),
+			"tfregistry://registry.terraform.io/hashicorp/aws",
+		},
+		"custom host with port": {
+			MustParseProviderSource("Example.com:8443/foo/bar"),
+			"tfregistry://example.com:8443/foo/bar",
+		},
+		"internationalized": {
+			MustParseProviderSource("испытание.com/испытание/aws"),
+			"tfregistry://xn--80akhbyknj4f.com/%D0%B8%D1%81%D0%BF%D1%8B%D1%82%D0%B0%D0%BD%D0%B8%D0%B5/aws",
+		},
+		"legacy": {
+			MustParseProviderSource("-/aws"),
+			"tfregistry://registry.terraform.io/-/aws",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := test.Input.ToURL().String()
+			if got != test.Want {
+				t.Fatalf("wrong URL\ngot:  %s\nwant: %s", got, test.Want)
+			}
+
+			back, err := ParseProviderURL(got)
+			if err != nil {
+				t.Fatalf("unexpected error parsing URL: %s", err)
+			}
+			if diff := cmp.Diff(test.Input, back); diff != "" {
+				t.Errorf("wrong result parsing URL\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModuleURL(t *testing.T) {
+	tests := map[string]struct {
+		Input Module
+		Want  string
+	}{
+		"default host": {
+			MustParseModuleSource("hashicorp/consul/aws"),
+			"tfregistry://registry.terraform.io/hashicorp/consul/aws",
+		},
+		"subdir": {
+			MustParseModuleSource("hashicorp/consul/aws//modules/consul-cluster"),
+			"tfregistry://registry.terraform.io/hashicorp/consul/aws?subdir=modules%2Fconsul-cluster",
+		},
+		"internationalized host with port": {
+			MustParseModuleSource("Испытание.com:1234/HashiCorp/Consul/aws"),
+			"tfregistry://xn--80akhbyknj4f.com:1234/HashiCorp/Consul/aws",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := test.Input.ToURL().String()
+			if got != test.Want {
+				t.Fatalf("wrong URL\ngot:  %s\nwant: %s", got, test.Want)
+			}
+
+			back, err := ParseModuleURL(got)
+			if err != nil {
+				t.Fatalf("unexpected error parsing URL: %s", err)
+			}
+			if diff := cmp.Diff(test.Input, back); diff != "" {
+				t.Errorf("wrong result parsing URL\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := map[string]struct {
+		Want    any
+		WantErr string
+	}{
+		"tfregistry://registry.terraform.io/hashicorp/aws": {
+			Want: MustParseProviderSource("hashicorp/aws"),
+		},
+		"tfregistry://registry.terraform.io/hashicorp/consul/aws?subdir=foo": {
+			Want: MustParseModuleSource("hashicorp/consul/aws//foo"),
+		},
+		"https://registry.terraform.io/hashicorp/aws": {
+			WantErr: `invalid registry URL "https://registry.terraform.io/hashicorp/aws": must use the "tfregistry" scheme`,
+		},
+		"tfregistry://registry.terraform.io/hashicorp/aws#foo": {
+			WantErr: `invalid registry URL "tfregistry://registry.terraform.io/hashicorp/aws#foo": must not include a fragment`,
+		},
+		"tfregistry://user@registry.terraform.io/hashicorp/aws": {
+			WantErr: `invalid registry URL "tfregistry://user@registry.terraform.io/hashicorp/aws": must not include user information`,
+		},
+		"tfregistry:hashicorp/aws": {
+			WantErr: `invalid registry URL "tfregistry:hashicorp/aws": must include a hostname`,
+		},
+		"tfregistry://registry.terraform.io/hashicorp": {
+			WantErr: `invalid registry URL "tfregistry://registry.terraform.io/hashicorp": must have either two or three path segments`,
+		},
+		"tfregistry://registry.terraform.io/hashicorp/aws?subdir=foo": {
+			WantErr: `Invalid provider URL: Invalid provider URL "tfregistry://registry.terraform.io/hashicorp/aws?subdir=foo": must have the form tfregistry://hostname/namespace/type.`,
+		},
+		"tfregistry://registry.terraform.io/hashicorp/consul/aws?ref=main": {
+			WantErr: `invalid module URL "tfregistry://registry.terraform.io/hashicorp/consul/aws?ref=main": the only supported query argument is "subdir"`,
+		},
+		"tfregistry://registry.terraform.io/hashicorp/consul/aws?subdir=..%2Ffoo": {
+			WantErr: `subdirectory path "../foo" leads outside of the module package`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseURL(input)
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// VersionConstraint is a single version constraint in the syntax that
+// Terraform uses for provider and module versions, such as ">= 4.0".
+//
+// This package only parses and normalizes constraints, so that they can be
+// carried alongside addresses. Callers that need to select versions should
+// use a version library, such as github.com/hashicorp/go-version, with the
+// result of String.
+type VersionConstraint struct {
+	// Operator is one of "=", "!=", ">", ">=", "<", "<=", or "~>", or empty
+	// if the constraint was written as just a version, which has the same
+	// meaning as "=".
+	Operator string
+
+	// Version is the version number as written, such as "4.0" or
+	// "1.2.0-beta1".
+	Version string
+}
+
+func (c VersionConstraint) String() string {
+	if c.Operator == "" {
+		return c.Version
+	}
+	return c.Operator + " " + c.Version
+}
+
+// VersionConstraints is a set of version constraints that must all be
+// satisfied, written as a comma-separated list such as ">= 4.0, < 5.0".
+type VersionConstraints []VersionConstraint
+
+// String returns the constraints in their normalized comma-separated form.
+func (cs VersionConstraints) String() string {
+	parts := make([]string, len(cs))
+	for i, c := range cs {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	versionConstraintPattern = regexp.MustCompile(`^(=|!=|>=|>|<=|<|~>)?\s*(\S+)$`)
+	constraintVersionPattern = regexp.MustCompile(`^v?[0-9]+(?:\.[0-9]+){0,2}(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+)
+
+// ParseVersionConstraints parses a comma-separated list of version
+// constraints, such as ">= 4.0, < 5.0".
+func ParseVersionConstraints(raw string) (VersionConstraints, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, fmt.Errorf("must not be empty")
+	}
+	var ret VersionConstraints
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		m := versionConstraintPattern.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid version constraint %q", part)
+		}
+		if !constraintVersionPattern.MatchString(m[2]) {
+			return nil, fmt.Errorf("invalid version %q in constraint %q", m[2], part)
+		}
+		ret = append(ret, VersionConstraint{Operator: m[1], Version: m[2]})
+	}
+	return ret, nil
+}
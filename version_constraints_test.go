@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseVersionConstraints(t *testing.T) {
+	tests := map[string]struct {
+		want       VersionConstraints
+		wantString string
+		wantErr    string
+	}{
+		">= 4.0, < 5.0": {
+			want:       VersionConstraints{{">=", "4.0"}, {"<", "5.0"}},
+			wantString: ">= 4.0, < 5.0",
+		},
+		"~>1.2.0": {
+			want:       VersionConstraints{{"~>", "1.2.0"}},
+			wantString: "~> 1.2.0",
+		},
+		"1.0.0-beta1": {
+			want:       VersionConstraints{{"", "1.0.0-beta1"}},
+			wantString: "1.0.0-beta1",
+		},
+		" = 2 ,!= 2.1.3 ": {
+			want:       VersionConstraints{{"=", "2"}, {"!=", "2.1.3"}},
+			wantString: "= 2, != 2.1.3",
+		},
+		"": {
+			wantErr: "must not be empty",
+		},
+		">= 4.0,": {
+			wantErr: `invalid version constraint ""`,
+		},
+		"=> 4.0": {
+			wantErr: `invalid version constraint "=> 4.0"`,
+		},
+		">= four": {
+			wantErr: `invalid version "four" in constraint ">= four"`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseVersionConstraints(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := got.String(); got != test.wantString {
+				t.Errorf("wrong string %q; want %q", got, test.wantString)
+			}
+		})
+	}
+}
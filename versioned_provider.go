@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionedProvider is a provider address together with the version
+// constraints for that provider, for callers such as lock file writers and
+// dependency analyzers that need to carry both together.
+type VersionedProvider struct {
+	Provider Provider
+
+	// Constraints is empty if any version of the provider is acceptable.
+	Constraints VersionConstraints
+}
+
+// ParseVersionedProvider parses a provider source string optionally
+// followed by "@" and a comma-separated list of version constraints, such
+// as "hashicorp/aws@>= 4.0, < 5.0".
+//
+// The provider source string is parsed in the same way as by
+// ParseProviderSource, and errors are returned as *ParserError.
+func ParseVersionedProvider(str string) (VersionedProvider, error) {
+	source, constraints, hasConstraints := strings.Cut(str, "@")
+	provider, err := ParseProviderSource(source)
+	if err != nil {
+		return VersionedProvider{}, err
+	}
+	ret := VersionedProvider{Provider: provider}
+	if hasConstraints {
+		ret.Constraints, err = ParseVersionConstraints(constraints)
+		if err != nil {
+			return VersionedProvider{}, &ParserError{
+				Summary: "Invalid version constraints",
+				Detail:  fmt.Sprintf("The version constraints for provider %s are invalid: %s.", provider.ForDisplay(), err),
+			}
+		}
+	}
+	return ret, nil
+}
+
+// ParseProviderRequirement parses the combined form of a provider source
+// string and version constraints that several HashiCorp tools use, where
+// the constraints follow the source string after whitespace, such as
+// "registry.terraform.io/hashicorp/aws >= 4.0, < 5.0".
+//
+// The constraints may be omitted, in which case any version of the provider
+// is acceptable. Errors are returned as *ParserError.
+func ParseProviderRequirement(str string) (VersionedProvider, error) {
+	str = strings.TrimSpace(str)
+	source, constraints := str, ""
+	if idx := strings.IndexAny(str, " \t"); idx != -1 {
+		source, constraints = str[:idx], strings.TrimSpace(str[idx+1:])
+	}
+	provider, err := ParseProviderSource(source)
+	if err != nil {
+		return VersionedProvider{}, err
+	}
+	ret := VersionedProvider{Provider: provider}
+	if constraints != "" {
+		ret.Constraints, err = ParseVersionConstraints(constraints)
+		if err != nil {
+			return VersionedProvider{}, &ParserError{
+				Summary: "Invalid version constraints",
+				Detail:  fmt.Sprintf("The version constraints for provider %s are invalid: %s.", provider.ForDisplay(), err),
+			}
+		}
+	}
+	return ret, nil
+}
+
+// String returns the fully-qualified provider address, followed by "@" and
+// the normalized version constraints if there are any.
+//
+// A provider address with an unknown namespace, as produced by parsing a
+// source string like "aws", is given in its ShortestForm instead, because
+// the placeholder for an unknown namespace can't be parsed.
+func (vp VersionedProvider) String() string {
+	return vp.withConstraints(vp.Provider.sourceString())
+}
+
+// ForDisplay is like String, but uses the display form of the provider
+// address, as returned by Provider.ForDisplay, or its ShortestForm if it
+// has an unknown namespace.
+func (vp VersionedProvider) ForDisplay() string {
+	if vp.Provider.Namespace == UnknownProviderNamespace {
+		return vp.withConstraints(vp.Provider.ShortestForm())
+	}
+	return vp.withConstraints(vp.Provider.ForDisplay())
+}
+
+// withConstraints returns the given provider string followed by "@" and the
+// receiver's normalized version constraints, if there are any.
+func (vp VersionedProvider) withConstraints(provider string) string {
+	if len(vp.Constraints) == 0 {
+		return provider
+	}
+	return provider + "@" + vp.Constraints.String()
+}
@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfaddr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseVersionedProvider(t *testing.T) {
+	tests := map[string]struct {
+		want        VersionedProvider
+		wantString  string
+		wantDisplay string
+		wantErr     string
+	}{
+		"hashicorp/aws@>= 4.0, < 5.0": {
+			want: VersionedProvider{
+				Provider:    MustParseProviderSource("hashicorp/aws"),
+				Constraints: VersionConstraints{{">=", "4.0"}, {"<", "5.0"}},
+			},
+			wantString:  "registry.terraform.io/hashicorp/aws@>= 4.0, < 5.0",
+			wantDisplay: "hashicorp/aws@>= 4.0, < 5.0",
+		},
+		"example.com/foo/bar@~>1.2": {
+			want: VersionedProvider{
+				Provider:    MustParseProviderSource("example.com/foo/bar"),
+				Constraints: VersionConstraints{{"~>", "1.2"}},
+			},
+			wantString:  "example.com/foo/bar@~> 1.2",
+			wantDisplay: "example.com/foo/bar@~> 1.2",
+		},
+		"hashicorp/aws": {
+			want: VersionedProvider{
+				Provider: MustParseProviderSource("hashicorp/aws"),
+			},
+			wantString:  "registry.terraform.io/hashicorp/aws",
+			wantDisplay: "hashicorp/aws",
+		},
+		"aws@>= 4.0": {
+			want: VersionedProvider{
+				Provider:    MustParseProviderSource("aws"),
+				Constraints: VersionConstraints{{">=", "4.0"}},
+			},
+			wantString:  "aws@>= 4.0",
+			wantDisplay: "aws@>= 4.0",
+		},
+		"hashicorp/aws@": {
+			wantErr: `Invalid version constraints: The version constraints for provider hashicorp/aws are invalid: must not be empty.`,
+		},
+		"hashicorp/aws@latest": {
+			wantErr: `Invalid version constraints: The version constraints for provider hashicorp/aws are invalid: invalid version "latest" in constraint "latest".`,
+		},
+		"a/b/c/d@1.0": {
+			wantErr: `Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name". The given string "a/b/c/d" looks like a module source address, which belongs in the "source" argument of a module block instead.`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseVersionedProvider(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+			if got := got.String(); got != test.wantString {
+				t.Errorf("wrong string %q; want %q", got, test.wantString)
+			}
+			if got := got.ForDisplay(); got != test.wantDisplay {
+				t.Errorf("wrong display string %q; want %q", got, test.wantDisplay)
+			}
+
+			for _, str := range []string{got.String(), got.ForDisplay()} {
+				again, err := ParseVersionedProvider(str)
+				if err != nil {
+					t.Fatalf("failed to parse %q: %s", str, err)
+				}
+				if diff := cmp.Diff(got, again); diff != "" {
+					t.Errorf("%q doesn't round-trip\n%s", str, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProviderRequirement(t *testing.T) {
+	tests := map[string]struct {
+		want    VersionedProvider
+		wantErr string
+	}{
+		"registry.terraform.io/hashicorp/aws >= 4.0": {
+			want: VersionedProvider{
+				Provider:    MustParseProviderSource("hashicorp/aws"),
+				Constraints: VersionConstraints{{">=", "4.0"}},
+			},
+		},
+		"hashicorp/aws\t>= 4.0, < 5.0 ": {
+			want: VersionedProvider{
+				Provider:    MustParseProviderSource("hashicorp/aws"),
+				Constraints: VersionConstraints{{">=", "4.0"}, {"<", "5.0"}},
+			},
+		},
+		"example.com/foo/bar": {
+			want: VersionedProvider{
+				Provider: MustParseProviderSource("example.com/foo/bar"),
+			},
+		},
+		"hashicorp/aws >= four": {
+			wantErr: `Invalid version constraints: The version constraints for provider hashicorp/aws are invalid: invalid version "four" in constraint ">= four".`,
+		},
+		"": {
+			wantErr: `Invalid provider source string: The "source" attribute must be in the format "[hostname/][namespace/]name"`,
+		},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseProviderRequirement(input)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				var parserErr *ParserError
+				if !errors.As(err, &parserErr) {
+					t.Errorf("wrong error type %T; want *ParserError", err)
+				}
+				if got := err.Error(); got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}